@@ -2,7 +2,26 @@
 
 // Package assets generated by go-bindata.// sources:
 // templates/cloudformation/iam_user_osdCcsAdmin.json
+// templates/cloudformation/vpc_rosaNetwork.json
+// templates/credentialsrequests/4.7/openshift-cluster-csi-drivers.yaml
+// templates/credentialsrequests/4.7/openshift-image-registry.yaml
+// templates/credentialsrequests/4.7/openshift-ingress.yaml
+// templates/credentialsrequests/4.7/openshift-machine-api-aws.yaml
+// templates/credentialsrequests/4.8/openshift-cloud-network-config-controller-aws.yaml
+// templates/credentialsrequests/4.8/openshift-cluster-csi-drivers.yaml
+// templates/credentialsrequests/4.8/openshift-image-registry.yaml
+// templates/credentialsrequests/4.8/openshift-ingress.yaml
+// templates/credentialsrequests/4.8/openshift-machine-api-aws.yaml
 // templates/policies/osd_scp_policy.json
+// templates/policies/sts_installer_permission_policy.json
+// templates/policies/sts_installer_trust_policy.json
+// templates/policies/sts_instance_controlplane_permission_policy.json
+// templates/policies/sts_instance_controlplane_trust_policy.json
+// templates/policies/sts_instance_worker_permission_policy.json
+// templates/policies/sts_instance_worker_trust_policy.json
+// templates/policies/sts_support_permission_policy.json
+// templates/policies/sts_support_trust_policy.json
+// templates/pricing/aws.json
 package assets
 
 import (
@@ -85,6 +104,1216 @@ func templatesCloudformationIam_user_osdccsadminJson() (*asset, error) {
 	return a, nil
 }
 
+var _templatesCloudformationVpc_rosanetworkJson = []byte(`{
+  "AWSTemplateFormatVersion": "2010-09-09",
+  "Description": "VPC with private and, optionally, public subnets in one or three availability zones, as required by ROSA clusters that use existing subnets.",
+  "Parameters": {
+    "VpcCidr": {
+      "Type": "String",
+      "Default": "10.0.0.0/16",
+      "AllowedPattern": "^(\\d{1,3}\\.){3}\\d{1,3}/(1[6-9]|2[0-4])$",
+      "Description": "CIDR block of the VPC, between /16 and /24. It is split in six subnets of the same size."
+    },
+    "SubnetBits": {
+      "Type": "Number",
+      "Default": 13,
+      "MinValue": 5,
+      "MaxValue": 13,
+      "Description": "Number of host bits of each subnet, at most the size of the VPC CIDR minus 3."
+    },
+    "AvailabilityZone1": {
+      "Type": "AWS::EC2::AvailabilityZone::Name",
+      "Description": "Availability zone of the first pair of subnets."
+    },
+    "AvailabilityZone2": {
+      "Type": "String",
+      "Default": "",
+      "Description": "Availability zone of the second pair of subnets, empty for single AZ networks."
+    },
+    "AvailabilityZone3": {
+      "Type": "String",
+      "Default": "",
+      "Description": "Availability zone of the third pair of subnets, empty for single AZ networks."
+    },
+    "PublicSubnets": {
+      "Type": "String",
+      "Default": "true",
+      "AllowedValues": [
+        "true",
+        "false"
+      ],
+      "Description": "Whether to create public subnets, an internet gateway and NAT gateways. PrivateLink clusters only need the private subnets."
+    }
+  },
+  "Conditions": {
+    "MultiAZ": {
+      "Fn::Not": [
+        {
+          "Fn::Equals": [
+            {
+              "Ref": "AvailabilityZone2"
+            },
+            ""
+          ]
+        }
+      ]
+    },
+    "HasPublicSubnets": {
+      "Fn::Equals": [
+        {
+          "Ref": "PublicSubnets"
+        },
+        "true"
+      ]
+    },
+    "HasPublicSubnetsMultiAZ": {
+      "Fn::And": [
+        {
+          "Condition": "MultiAZ"
+        },
+        {
+          "Condition": "HasPublicSubnets"
+        }
+      ]
+    }
+  },
+  "Resources": {
+    "VPC": {
+      "Type": "AWS::EC2::VPC",
+      "Properties": {
+        "CidrBlock": {
+          "Ref": "VpcCidr"
+        },
+        "EnableDnsSupport": true,
+        "EnableDnsHostnames": true,
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-vpc"
+            }
+          }
+        ]
+      }
+    },
+    "InternetGateway": {
+      "Type": "AWS::EC2::InternetGateway",
+      "Condition": "HasPublicSubnets",
+      "Properties": {
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-igw"
+            }
+          }
+        ]
+      }
+    },
+    "InternetGatewayAttachment": {
+      "Type": "AWS::EC2::VPCGatewayAttachment",
+      "Condition": "HasPublicSubnets",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "InternetGatewayId": {
+          "Ref": "InternetGateway"
+        }
+      }
+    },
+    "PublicRouteTable": {
+      "Type": "AWS::EC2::RouteTable",
+      "Condition": "HasPublicSubnets",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-public"
+            }
+          }
+        ]
+      }
+    },
+    "PublicRoute": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasPublicSubnets",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "GatewayId": {
+          "Ref": "InternetGateway"
+        }
+      }
+    },
+    "PublicSubnet1": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "HasPublicSubnets",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            3,
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                6,
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Ref": "AvailabilityZone1"
+        },
+        "MapPublicIpOnLaunch": true,
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-public-1"
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PublicSubnet1RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "HasPublicSubnets",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PublicSubnet1"
+        },
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        }
+      }
+    },
+    "NatGateway1EIP": {
+      "Type": "AWS::EC2::EIP",
+      "Condition": "HasPublicSubnets",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "Domain": "vpc"
+      }
+    },
+    "NatGateway1": {
+      "Type": "AWS::EC2::NatGateway",
+      "Condition": "HasPublicSubnets",
+      "Properties": {
+        "AllocationId": {
+          "Fn::GetAtt": [
+            "NatGateway1EIP",
+            "AllocationId"
+          ]
+        },
+        "SubnetId": {
+          "Ref": "PublicSubnet1"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-nat-1"
+            }
+          }
+        ]
+      }
+    },
+    "PublicSubnet2": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            4,
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                6,
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Ref": "AvailabilityZone2"
+        },
+        "MapPublicIpOnLaunch": true,
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-public-2"
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PublicSubnet2RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PublicSubnet2"
+        },
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        }
+      }
+    },
+    "NatGateway2EIP": {
+      "Type": "AWS::EC2::EIP",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "Domain": "vpc"
+      }
+    },
+    "NatGateway2": {
+      "Type": "AWS::EC2::NatGateway",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "AllocationId": {
+          "Fn::GetAtt": [
+            "NatGateway2EIP",
+            "AllocationId"
+          ]
+        },
+        "SubnetId": {
+          "Ref": "PublicSubnet2"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-nat-2"
+            }
+          }
+        ]
+      }
+    },
+    "PublicSubnet3": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            5,
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                6,
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Ref": "AvailabilityZone3"
+        },
+        "MapPublicIpOnLaunch": true,
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-public-3"
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PublicSubnet3RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PublicSubnet3"
+        },
+        "RouteTableId": {
+          "Ref": "PublicRouteTable"
+        }
+      }
+    },
+    "NatGateway3EIP": {
+      "Type": "AWS::EC2::EIP",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "DependsOn": "InternetGatewayAttachment",
+      "Properties": {
+        "Domain": "vpc"
+      }
+    },
+    "NatGateway3": {
+      "Type": "AWS::EC2::NatGateway",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "AllocationId": {
+          "Fn::GetAtt": [
+            "NatGateway3EIP",
+            "AllocationId"
+          ]
+        },
+        "SubnetId": {
+          "Ref": "PublicSubnet3"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-nat-3"
+            }
+          }
+        ]
+      }
+    },
+    "PrivateSubnet1": {
+      "Type": "AWS::EC2::Subnet",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            0,
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                6,
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Ref": "AvailabilityZone1"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-private-1"
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/internal-elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PrivateRouteTable1": {
+      "Type": "AWS::EC2::RouteTable",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-private-1"
+            }
+          }
+        ]
+      }
+    },
+    "PrivateRoute1": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasPublicSubnets",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable1"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "NatGatewayId": {
+          "Ref": "NatGateway1"
+        }
+      }
+    },
+    "PrivateSubnet1RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PrivateSubnet1"
+        },
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable1"
+        }
+      }
+    },
+    "PrivateSubnet2": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "MultiAZ",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            1,
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                6,
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Ref": "AvailabilityZone2"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-private-2"
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/internal-elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PrivateRouteTable2": {
+      "Type": "AWS::EC2::RouteTable",
+      "Condition": "MultiAZ",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-private-2"
+            }
+          }
+        ]
+      }
+    },
+    "PrivateRoute2": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable2"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "NatGatewayId": {
+          "Ref": "NatGateway2"
+        }
+      }
+    },
+    "PrivateSubnet2RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "MultiAZ",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PrivateSubnet2"
+        },
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable2"
+        }
+      }
+    },
+    "PrivateSubnet3": {
+      "Type": "AWS::EC2::Subnet",
+      "Condition": "MultiAZ",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "CidrBlock": {
+          "Fn::Select": [
+            2,
+            {
+              "Fn::Cidr": [
+                {
+                  "Ref": "VpcCidr"
+                },
+                6,
+                {
+                  "Ref": "SubnetBits"
+                }
+              ]
+            }
+          ]
+        },
+        "AvailabilityZone": {
+          "Ref": "AvailabilityZone3"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-private-3"
+            }
+          },
+          {
+            "Key": "kubernetes.io/role/internal-elb",
+            "Value": "1"
+          }
+        ]
+      }
+    },
+    "PrivateRouteTable3": {
+      "Type": "AWS::EC2::RouteTable",
+      "Condition": "MultiAZ",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "Tags": [
+          {
+            "Key": "Name",
+            "Value": {
+              "Fn::Sub": "${AWS::StackName}-private-3"
+            }
+          }
+        ]
+      }
+    },
+    "PrivateRoute3": {
+      "Type": "AWS::EC2::Route",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Properties": {
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable3"
+        },
+        "DestinationCidrBlock": "0.0.0.0/0",
+        "NatGatewayId": {
+          "Ref": "NatGateway3"
+        }
+      }
+    },
+    "PrivateSubnet3RouteTableAssociation": {
+      "Type": "AWS::EC2::SubnetRouteTableAssociation",
+      "Condition": "MultiAZ",
+      "Properties": {
+        "SubnetId": {
+          "Ref": "PrivateSubnet3"
+        },
+        "RouteTableId": {
+          "Ref": "PrivateRouteTable3"
+        }
+      }
+    },
+    "S3Endpoint": {
+      "Type": "AWS::EC2::VPCEndpoint",
+      "Properties": {
+        "VpcId": {
+          "Ref": "VPC"
+        },
+        "ServiceName": {
+          "Fn::Sub": "com.amazonaws.${AWS::Region}.s3"
+        },
+        "RouteTableIds": {
+          "Fn::If": [
+            "MultiAZ",
+            [
+              {
+                "Ref": "PrivateRouteTable1"
+              },
+              {
+                "Ref": "PrivateRouteTable2"
+              },
+              {
+                "Ref": "PrivateRouteTable3"
+              }
+            ],
+            [
+              {
+                "Ref": "PrivateRouteTable1"
+              }
+            ]
+          ]
+        }
+      }
+    }
+  },
+  "Outputs": {
+    "VpcId": {
+      "Description": "Identifier of the VPC.",
+      "Value": {
+        "Ref": "VPC"
+      }
+    },
+    "PrivateSubnet1": {
+      "Description": "Identifier of private subnet 1.",
+      "Value": {
+        "Ref": "PrivateSubnet1"
+      }
+    },
+    "PrivateSubnet2": {
+      "Description": "Identifier of private subnet 2.",
+      "Condition": "MultiAZ",
+      "Value": {
+        "Ref": "PrivateSubnet2"
+      }
+    },
+    "PrivateSubnet3": {
+      "Description": "Identifier of private subnet 3.",
+      "Condition": "MultiAZ",
+      "Value": {
+        "Ref": "PrivateSubnet3"
+      }
+    },
+    "PublicSubnet1": {
+      "Description": "Identifier of public subnet 1.",
+      "Condition": "HasPublicSubnets",
+      "Value": {
+        "Ref": "PublicSubnet1"
+      }
+    },
+    "PublicSubnet2": {
+      "Description": "Identifier of public subnet 2.",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Value": {
+        "Ref": "PublicSubnet2"
+      }
+    },
+    "PublicSubnet3": {
+      "Description": "Identifier of public subnet 3.",
+      "Condition": "HasPublicSubnetsMultiAZ",
+      "Value": {
+        "Ref": "PublicSubnet3"
+      }
+    }
+  }
+}
+`)
+
+func templatesCloudformationVpc_rosanetworkJsonBytes() ([]byte, error) {
+	return _templatesCloudformationVpc_rosanetworkJson, nil
+}
+
+func templatesCloudformationVpc_rosanetworkJson() (*asset, error) {
+	bytes, err := templatesCloudformationVpc_rosanetworkJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/cloudformation/vpc_rosaNetwork.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests47OpenshiftClusterCsiDriversYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: aws-ebs-csi-driver-operator
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - ec2:AttachVolume
+      - ec2:CreateSnapshot
+      - ec2:CreateTags
+      - ec2:CreateVolume
+      - ec2:DeleteSnapshot
+      - ec2:DeleteTags
+      - ec2:DeleteVolume
+      - ec2:DescribeInstances
+      - ec2:DescribeSnapshots
+      - ec2:DescribeTags
+      - ec2:DescribeVolumes
+      - ec2:DescribeVolumesModifications
+      - ec2:DetachVolume
+      - ec2:ModifyVolume
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: ebs-cloud-credentials
+    namespace: openshift-cluster-csi-drivers
+  serviceAccountNames:
+  - aws-ebs-csi-driver-operator
+  - aws-ebs-csi-driver-controller-sa
+`)
+
+func templatesCredentialsrequests47OpenshiftClusterCsiDriversYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests47OpenshiftClusterCsiDriversYaml, nil
+}
+
+func templatesCredentialsrequests47OpenshiftClusterCsiDriversYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests47OpenshiftClusterCsiDriversYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.7/openshift-cluster-csi-drivers.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests47OpenshiftImageRegistryYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-image-registry
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - s3:CreateBucket
+      - s3:DeleteBucket
+      - s3:PutBucketTagging
+      - s3:GetBucketTagging
+      - s3:PutBucketPublicAccessBlock
+      - s3:GetBucketPublicAccessBlock
+      - s3:PutEncryptionConfiguration
+      - s3:GetEncryptionConfiguration
+      - s3:PutLifecycleConfiguration
+      - s3:GetLifecycleConfiguration
+      - s3:GetBucketLocation
+      - s3:ListBucket
+      - s3:GetObject
+      - s3:PutObject
+      - s3:DeleteObject
+      - s3:ListBucketMultipartUploads
+      - s3:AbortMultipartUpload
+      - s3:ListMultipartUploadParts
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: installer-cloud-credentials
+    namespace: openshift-image-registry
+  serviceAccountNames:
+  - cluster-image-registry-operator
+  - registry
+`)
+
+func templatesCredentialsrequests47OpenshiftImageRegistryYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests47OpenshiftImageRegistryYaml, nil
+}
+
+func templatesCredentialsrequests47OpenshiftImageRegistryYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests47OpenshiftImageRegistryYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.7/openshift-image-registry.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests47OpenshiftIngressYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-ingress
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - elasticloadbalancing:DescribeLoadBalancers
+      - route53:ListHostedZones
+      - route53:ChangeResourceRecordSets
+      - tag:GetResources
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: cloud-credentials
+    namespace: openshift-ingress-operator
+  serviceAccountNames:
+  - ingress-operator
+`)
+
+func templatesCredentialsrequests47OpenshiftIngressYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests47OpenshiftIngressYaml, nil
+}
+
+func templatesCredentialsrequests47OpenshiftIngressYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests47OpenshiftIngressYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.7/openshift-ingress.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests47OpenshiftMachineApiAwsYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-machine-api-aws
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - ec2:CreateTags
+      - ec2:DescribeAvailabilityZones
+      - ec2:DescribeDhcpOptions
+      - ec2:DescribeImages
+      - ec2:DescribeInstances
+      - ec2:DescribeSecurityGroups
+      - ec2:DescribeSubnets
+      - ec2:DescribeVpcs
+      - ec2:RunInstances
+      - ec2:TerminateInstances
+      - elasticloadbalancing:DescribeLoadBalancers
+      - elasticloadbalancing:DescribeTargetGroups
+      - elasticloadbalancing:RegisterInstancesWithLoadBalancer
+      - elasticloadbalancing:RegisterTargets
+      - iam:PassRole
+      - iam:CreateServiceLinkedRole
+      effect: Allow
+      resource: '*'
+    - action:
+      - kms:Decrypt
+      - kms:Encrypt
+      - kms:GenerateDataKey
+      - kms:GenerateDataKeyWithoutPlainText
+      - kms:DescribeKey
+      effect: Allow
+      resource: '*'
+    - action:
+      - kms:RevokeGrant
+      - kms:CreateGrant
+      - kms:ListGrants
+      effect: Allow
+      resource: '*'
+      policyCondition:
+        Bool:
+          kms:GrantIsForAWSResource: true
+  secretRef:
+    name: aws-cloud-credentials
+    namespace: openshift-machine-api
+  serviceAccountNames:
+  - machine-api-controllers
+`)
+
+func templatesCredentialsrequests47OpenshiftMachineApiAwsYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests47OpenshiftMachineApiAwsYaml, nil
+}
+
+func templatesCredentialsrequests47OpenshiftMachineApiAwsYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests47OpenshiftMachineApiAwsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.7/openshift-machine-api-aws.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests48OpenshiftCloudNetworkConfigControllerAwsYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-cloud-network-config-controller-aws
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - ec2:DescribeInstances
+      - ec2:DescribeInstanceStatus
+      - ec2:DescribeInstanceTypes
+      - ec2:UnassignPrivateIpAddresses
+      - ec2:AssignPrivateIpAddresses
+      - ec2:UnassignIpv6Addresses
+      - ec2:AssignIpv6Addresses
+      - ec2:DescribeSubnets
+      - ec2:DescribeNetworkInterfaces
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: cloud-credentials
+    namespace: openshift-cloud-network-config-controller
+  serviceAccountNames:
+  - cloud-network-config-controller
+`)
+
+func templatesCredentialsrequests48OpenshiftCloudNetworkConfigControllerAwsYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests48OpenshiftCloudNetworkConfigControllerAwsYaml, nil
+}
+
+func templatesCredentialsrequests48OpenshiftCloudNetworkConfigControllerAwsYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests48OpenshiftCloudNetworkConfigControllerAwsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.8/openshift-cloud-network-config-controller-aws.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests48OpenshiftClusterCsiDriversYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: aws-ebs-csi-driver-operator
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - ec2:AttachVolume
+      - ec2:CreateSnapshot
+      - ec2:CreateTags
+      - ec2:CreateVolume
+      - ec2:DeleteSnapshot
+      - ec2:DeleteTags
+      - ec2:DeleteVolume
+      - ec2:DescribeInstances
+      - ec2:DescribeSnapshots
+      - ec2:DescribeTags
+      - ec2:DescribeVolumes
+      - ec2:DescribeVolumesModifications
+      - ec2:DetachVolume
+      - ec2:ModifyVolume
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: ebs-cloud-credentials
+    namespace: openshift-cluster-csi-drivers
+  serviceAccountNames:
+  - aws-ebs-csi-driver-operator
+  - aws-ebs-csi-driver-controller-sa
+`)
+
+func templatesCredentialsrequests48OpenshiftClusterCsiDriversYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests48OpenshiftClusterCsiDriversYaml, nil
+}
+
+func templatesCredentialsrequests48OpenshiftClusterCsiDriversYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests48OpenshiftClusterCsiDriversYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.8/openshift-cluster-csi-drivers.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests48OpenshiftImageRegistryYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-image-registry
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - s3:CreateBucket
+      - s3:DeleteBucket
+      - s3:PutBucketTagging
+      - s3:GetBucketTagging
+      - s3:PutBucketPublicAccessBlock
+      - s3:GetBucketPublicAccessBlock
+      - s3:PutEncryptionConfiguration
+      - s3:GetEncryptionConfiguration
+      - s3:PutLifecycleConfiguration
+      - s3:GetLifecycleConfiguration
+      - s3:GetBucketLocation
+      - s3:ListBucket
+      - s3:GetObject
+      - s3:PutObject
+      - s3:DeleteObject
+      - s3:ListBucketMultipartUploads
+      - s3:AbortMultipartUpload
+      - s3:ListMultipartUploadParts
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: installer-cloud-credentials
+    namespace: openshift-image-registry
+  serviceAccountNames:
+  - cluster-image-registry-operator
+  - registry
+`)
+
+func templatesCredentialsrequests48OpenshiftImageRegistryYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests48OpenshiftImageRegistryYaml, nil
+}
+
+func templatesCredentialsrequests48OpenshiftImageRegistryYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests48OpenshiftImageRegistryYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.8/openshift-image-registry.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests48OpenshiftIngressYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-ingress
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - elasticloadbalancing:DescribeLoadBalancers
+      - route53:ListHostedZones
+      - route53:ChangeResourceRecordSets
+      - tag:GetResources
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: cloud-credentials
+    namespace: openshift-ingress-operator
+  serviceAccountNames:
+  - ingress-operator
+`)
+
+func templatesCredentialsrequests48OpenshiftIngressYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests48OpenshiftIngressYaml, nil
+}
+
+func templatesCredentialsrequests48OpenshiftIngressYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests48OpenshiftIngressYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.8/openshift-ingress.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesCredentialsrequests48OpenshiftMachineApiAwsYaml = []byte(`apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-machine-api-aws
+  namespace: openshift-cloud-credential-operator
+spec:
+  providerSpec:
+    apiVersion: cloudcredential.openshift.io/v1
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - ec2:CreateTags
+      - ec2:DescribeAvailabilityZones
+      - ec2:DescribeDhcpOptions
+      - ec2:DescribeImages
+      - ec2:DescribeInstances
+      - ec2:DescribeSecurityGroups
+      - ec2:DescribeSubnets
+      - ec2:DescribeVpcs
+      - ec2:RunInstances
+      - ec2:TerminateInstances
+      - elasticloadbalancing:DescribeLoadBalancers
+      - elasticloadbalancing:DescribeTargetGroups
+      - elasticloadbalancing:RegisterInstancesWithLoadBalancer
+      - elasticloadbalancing:RegisterTargets
+      - iam:PassRole
+      - iam:CreateServiceLinkedRole
+      effect: Allow
+      resource: '*'
+    - action:
+      - kms:Decrypt
+      - kms:Encrypt
+      - kms:GenerateDataKey
+      - kms:GenerateDataKeyWithoutPlainText
+      - kms:DescribeKey
+      effect: Allow
+      resource: '*'
+    - action:
+      - kms:RevokeGrant
+      - kms:CreateGrant
+      - kms:ListGrants
+      effect: Allow
+      resource: '*'
+      policyCondition:
+        Bool:
+          kms:GrantIsForAWSResource: true
+  secretRef:
+    name: aws-cloud-credentials
+    namespace: openshift-machine-api
+  serviceAccountNames:
+  - machine-api-controllers
+`)
+
+func templatesCredentialsrequests48OpenshiftMachineApiAwsYamlBytes() ([]byte, error) {
+	return _templatesCredentialsrequests48OpenshiftMachineApiAwsYaml, nil
+}
+
+func templatesCredentialsrequests48OpenshiftMachineApiAwsYaml() (*asset, error) {
+	bytes, err := templatesCredentialsrequests48OpenshiftMachineApiAwsYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/credentialsrequests/4.8/openshift-machine-api-aws.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _templatesPoliciesOsd_scp_policyJson = []byte(`{
     "Version": "2012-10-17",
     "Id": "OSD SCP Policy Document",
@@ -279,6 +1508,585 @@ func templatesPoliciesOsd_scp_policyJson() (*asset, error) {
 	return a, nil
 }
 
+var _templatesPoliciesSts_installer_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "autoscaling:DescribeAutoScalingGroups",
+                "ec2:AllocateAddress",
+                "ec2:AssociateAddress",
+                "ec2:AssociateDhcpOptions",
+                "ec2:AssociateRouteTable",
+                "ec2:AttachInternetGateway",
+                "ec2:AttachNetworkInterface",
+                "ec2:AuthorizeSecurityGroupEgress",
+                "ec2:AuthorizeSecurityGroupIngress",
+                "ec2:CopyImage",
+                "ec2:CreateDhcpOptions",
+                "ec2:CreateInternetGateway",
+                "ec2:CreateNatGateway",
+                "ec2:CreateNetworkInterface",
+                "ec2:CreateRoute",
+                "ec2:CreateRouteTable",
+                "ec2:CreateSecurityGroup",
+                "ec2:CreateSubnet",
+                "ec2:CreateTags",
+                "ec2:CreateVolume",
+                "ec2:CreateVpc",
+                "ec2:CreateVpcEndpoint",
+                "ec2:DeleteDhcpOptions",
+                "ec2:DeleteInternetGateway",
+                "ec2:DeleteNatGateway",
+                "ec2:DeleteNetworkInterface",
+                "ec2:DeleteRoute",
+                "ec2:DeleteRouteTable",
+                "ec2:DeleteSecurityGroup",
+                "ec2:DeleteSnapshot",
+                "ec2:DeleteSubnet",
+                "ec2:DeleteTags",
+                "ec2:DeleteVolume",
+                "ec2:DeleteVpc",
+                "ec2:DeleteVpcEndpoints",
+                "ec2:DeregisterImage",
+                "ec2:DescribeAccountAttributes",
+                "ec2:DescribeAddresses",
+                "ec2:DescribeAvailabilityZones",
+                "ec2:DescribeDhcpOptions",
+                "ec2:DescribeImages",
+                "ec2:DescribeInstanceAttribute",
+                "ec2:DescribeInstanceCreditSpecifications",
+                "ec2:DescribeInstances",
+                "ec2:DescribeInstanceStatus",
+                "ec2:DescribeInstanceTypeOfferings",
+                "ec2:DescribeInstanceTypes",
+                "ec2:DescribeInternetGateways",
+                "ec2:DescribeKeyPairs",
+                "ec2:DescribeNatGateways",
+                "ec2:DescribeNetworkAcls",
+                "ec2:DescribeNetworkInterfaces",
+                "ec2:DescribePrefixLists",
+                "ec2:DescribeRegions",
+                "ec2:DescribeReservedInstancesOfferings",
+                "ec2:DescribeRouteTables",
+                "ec2:DescribeSecurityGroups",
+                "ec2:DescribeSubnets",
+                "ec2:DescribeTags",
+                "ec2:DescribeVolumes",
+                "ec2:DescribeVpcAttribute",
+                "ec2:DescribeVpcClassicLink",
+                "ec2:DescribeVpcClassicLinkDnsSupport",
+                "ec2:DescribeVpcEndpoints",
+                "ec2:DescribeVpcs",
+                "ec2:DetachInternetGateway",
+                "ec2:DisassociateRouteTable",
+                "ec2:GetConsoleOutput",
+                "ec2:GetEbsDefaultKmsKeyId",
+                "ec2:ModifyInstanceAttribute",
+                "ec2:ModifyNetworkInterfaceAttribute",
+                "ec2:ModifySubnetAttribute",
+                "ec2:ModifyVpcAttribute",
+                "ec2:ReleaseAddress",
+                "ec2:RevokeSecurityGroupEgress",
+                "ec2:RevokeSecurityGroupIngress",
+                "ec2:RunInstances",
+                "ec2:StartInstances",
+                "ec2:StopInstances",
+                "ec2:TerminateInstances",
+                "elasticloadbalancing:AddTags",
+                "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+                "elasticloadbalancing:AttachLoadBalancerToSubnets",
+                "elasticloadbalancing:ConfigureHealthCheck",
+                "elasticloadbalancing:CreateListener",
+                "elasticloadbalancing:CreateLoadBalancer",
+                "elasticloadbalancing:CreateLoadBalancerListeners",
+                "elasticloadbalancing:CreateTargetGroup",
+                "elasticloadbalancing:DeleteLoadBalancer",
+                "elasticloadbalancing:DeleteTargetGroup",
+                "elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+                "elasticloadbalancing:DeregisterTargets",
+                "elasticloadbalancing:DescribeInstanceHealth",
+                "elasticloadbalancing:DescribeListeners",
+                "elasticloadbalancing:DescribeLoadBalancerAttributes",
+                "elasticloadbalancing:DescribeLoadBalancers",
+                "elasticloadbalancing:DescribeTags",
+                "elasticloadbalancing:DescribeTargetGroupAttributes",
+                "elasticloadbalancing:DescribeTargetGroups",
+                "elasticloadbalancing:DescribeTargetHealth",
+                "elasticloadbalancing:ModifyLoadBalancerAttributes",
+                "elasticloadbalancing:ModifyTargetGroup",
+                "elasticloadbalancing:ModifyTargetGroupAttributes",
+                "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+                "elasticloadbalancing:RegisterTargets",
+                "elasticloadbalancing:SetLoadBalancerPoliciesOfListener",
+                "iam:AddRoleToInstanceProfile",
+                "iam:CreateInstanceProfile",
+                "iam:DeleteInstanceProfile",
+                "iam:GetInstanceProfile",
+                "iam:GetRole",
+                "iam:GetRolePolicy",
+                "iam:GetUser",
+                "iam:ListAttachedRolePolicies",
+                "iam:ListInstanceProfiles",
+                "iam:ListInstanceProfilesForRole",
+                "iam:ListRolePolicies",
+                "iam:ListRoles",
+                "iam:ListUserPolicies",
+                "iam:ListUsers",
+                "iam:PassRole",
+                "iam:RemoveRoleFromInstanceProfile",
+                "iam:SimulatePrincipalPolicy",
+                "iam:TagRole",
+                "iam:UntagRole",
+                "route53:ChangeResourceRecordSets",
+                "route53:ChangeTagsForResource",
+                "route53:CreateHostedZone",
+                "route53:DeleteHostedZone",
+                "route53:GetChange",
+                "route53:GetHostedZone",
+                "route53:ListHostedZones",
+                "route53:ListHostedZonesByName",
+                "route53:ListResourceRecordSets",
+                "route53:ListTagsForResource",
+                "route53:UpdateHostedZoneComment",
+                "s3:CreateBucket",
+                "s3:DeleteBucket",
+                "s3:DeleteObject",
+                "s3:GetAccelerateConfiguration",
+                "s3:GetBucketAcl",
+                "s3:GetBucketCORS",
+                "s3:GetBucketLocation",
+                "s3:GetBucketLogging",
+                "s3:GetBucketObjectLockConfiguration",
+                "s3:GetBucketReplication",
+                "s3:GetBucketRequestPayment",
+                "s3:GetBucketTagging",
+                "s3:GetBucketVersioning",
+                "s3:GetBucketWebsite",
+                "s3:GetEncryptionConfiguration",
+                "s3:GetLifecycleConfiguration",
+                "s3:GetObject",
+                "s3:GetObjectAcl",
+                "s3:GetObjectTagging",
+                "s3:GetObjectVersion",
+                "s3:GetReplicationConfiguration",
+                "s3:ListBucket",
+                "s3:ListBucketVersions",
+                "s3:PutBucketAcl",
+                "s3:PutBucketTagging",
+                "s3:PutEncryptionConfiguration",
+                "s3:PutObject",
+                "s3:PutObjectAcl",
+                "s3:PutObjectTagging",
+                "servicequotas:GetServiceQuota",
+                "servicequotas:ListAWSDefaultServiceQuotas",
+                "sts:AssumeRole",
+                "sts:AssumeRoleWithWebIdentity",
+                "sts:GetCallerIdentity",
+                "tag:GetResources",
+                "tag:UntagResources"
+            ],
+            "Resource": [
+                "*"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_installer_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_installer_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_installer_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_installer_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_installer_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_installer_trust_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Principal": {
+                "AWS": [
+                    "arn:aws:iam::%{aws_account_id}:role/RH-Managed-OpenShift-Installer"
+                ]
+            },
+            "Action": [
+                "sts:AssumeRole"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_installer_trust_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_installer_trust_policyJson, nil
+}
+
+func templatesPoliciesSts_installer_trust_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_installer_trust_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_installer_trust_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_instance_controlplane_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "ec2:AttachVolume",
+                "ec2:AuthorizeSecurityGroupIngress",
+                "ec2:CreateSecurityGroup",
+                "ec2:CreateTags",
+                "ec2:CreateVolume",
+                "ec2:DeleteSecurityGroup",
+                "ec2:DeleteVolume",
+                "ec2:Describe*",
+                "ec2:DetachVolume",
+                "ec2:ModifyInstanceAttribute",
+                "ec2:ModifyVolume",
+                "ec2:RevokeSecurityGroupIngress",
+                "elasticloadbalancing:AddTags",
+                "elasticloadbalancing:AttachLoadBalancerToSubnets",
+                "elasticloadbalancing:ApplySecurityGroupsToLoadBalancer",
+                "elasticloadbalancing:CreateListener",
+                "elasticloadbalancing:CreateLoadBalancer",
+                "elasticloadbalancing:CreateLoadBalancerPolicy",
+                "elasticloadbalancing:CreateLoadBalancerListeners",
+                "elasticloadbalancing:CreateTargetGroup",
+                "elasticloadbalancing:ConfigureHealthCheck",
+                "elasticloadbalancing:DeleteListener",
+                "elasticloadbalancing:DeleteLoadBalancer",
+                "elasticloadbalancing:DeleteLoadBalancerListeners",
+                "elasticloadbalancing:DeleteTargetGroup",
+                "elasticloadbalancing:DeregisterInstancesFromLoadBalancer",
+                "elasticloadbalancing:DeregisterTargets",
+                "elasticloadbalancing:Describe*",
+                "elasticloadbalancing:DetachLoadBalancerFromSubnets",
+                "elasticloadbalancing:ModifyListener",
+                "elasticloadbalancing:ModifyLoadBalancerAttributes",
+                "elasticloadbalancing:ModifyTargetGroup",
+                "elasticloadbalancing:ModifyTargetGroupAttributes",
+                "elasticloadbalancing:RegisterInstancesWithLoadBalancer",
+                "elasticloadbalancing:RegisterTargets",
+                "elasticloadbalancing:SetLoadBalancerPoliciesForBackendServer",
+                "elasticloadbalancing:SetLoadBalancerPoliciesOfListener",
+                "kms:DescribeKey"
+            ],
+            "Resource": [
+                "*"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_instance_controlplane_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_controlplane_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_controlplane_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_controlplane_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_controlplane_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_instance_controlplane_trust_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Principal": {
+                "Service": [
+                    "ec2.amazonaws.com"
+                ]
+            },
+            "Action": [
+                "sts:AssumeRole"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_instance_controlplane_trust_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_controlplane_trust_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_controlplane_trust_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_controlplane_trust_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_controlplane_trust_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_instance_worker_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "ec2:DescribeInstances",
+                "ec2:DescribeRegions"
+            ],
+            "Resource": [
+                "*"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_instance_worker_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_worker_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_worker_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_worker_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_worker_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_instance_worker_trust_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Principal": {
+                "Service": [
+                    "ec2.amazonaws.com"
+                ]
+            },
+            "Action": [
+                "sts:AssumeRole"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_instance_worker_trust_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_instance_worker_trust_policyJson, nil
+}
+
+func templatesPoliciesSts_instance_worker_trust_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_instance_worker_trust_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_instance_worker_trust_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_support_permission_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": [
+                "cloudtrail:DescribeTrails",
+                "cloudtrail:LookupEvents",
+                "cloudwatch:GetMetricData",
+                "cloudwatch:GetMetricStatistics",
+                "cloudwatch:ListMetrics",
+                "ec2:Describe*",
+                "ec2:GetConsoleOutput",
+                "elasticloadbalancing:DescribeAccountLimits",
+                "elasticloadbalancing:DescribeInstanceHealth",
+                "elasticloadbalancing:DescribeListeners",
+                "elasticloadbalancing:DescribeLoadBalancerAttributes",
+                "elasticloadbalancing:DescribeLoadBalancers",
+                "elasticloadbalancing:DescribeTags",
+                "elasticloadbalancing:DescribeTargetGroups",
+                "elasticloadbalancing:DescribeTargetHealth",
+                "iam:GetRole",
+                "iam:ListRoles",
+                "route53:GetHostedZone",
+                "route53:GetHostedZoneCount",
+                "route53:ListHostedZones",
+                "route53:ListHostedZonesByName",
+                "route53:ListResourceRecordSets",
+                "s3:GetBucketTagging",
+                "s3:GetObjectAcl",
+                "s3:GetObjectTagging",
+                "s3:ListAllMyBuckets",
+                "sts:DecodeAuthorizationMessage",
+                "tag:GetResources"
+            ],
+            "Resource": [
+                "*"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_support_permission_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_support_permission_policyJson, nil
+}
+
+func templatesPoliciesSts_support_permission_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_support_permission_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_support_permission_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPoliciesSts_support_trust_policyJson = []byte(`{
+    "Version": "2012-10-17",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Principal": {
+                "AWS": [
+                    "arn:aws:iam::%{aws_account_id}:role/RH-Technical-Support-Access"
+                ]
+            },
+            "Action": [
+                "sts:AssumeRole"
+            ]
+        }
+    ]
+}
+`)
+
+func templatesPoliciesSts_support_trust_policyJsonBytes() ([]byte, error) {
+	return _templatesPoliciesSts_support_trust_policyJson, nil
+}
+
+func templatesPoliciesSts_support_trust_policyJson() (*asset, error) {
+	bytes, err := templatesPoliciesSts_support_trust_policyJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/policies/sts_support_trust_policy.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _templatesPricingAwsJson = []byte(`{
+  "currency": "USD",
+  "baseRegion": "us-east-1",
+  "hoursPerMonth": 730,
+  "regionMultipliers": {
+    "af-south-1": 1.19,
+    "ap-east-1": 1.37,
+    "ap-northeast-1": 1.29,
+    "ap-northeast-2": 1.23,
+    "ap-northeast-3": 1.29,
+    "ap-south-1": 1.05,
+    "ap-southeast-1": 1.25,
+    "ap-southeast-2": 1.25,
+    "ca-central-1": 1.11,
+    "eu-central-1": 1.19,
+    "eu-north-1": 1.05,
+    "eu-south-1": 1.17,
+    "eu-west-1": 1.11,
+    "eu-west-2": 1.16,
+    "eu-west-3": 1.16,
+    "me-south-1": 1.22,
+    "sa-east-1": 1.59,
+    "us-east-1": 1.0,
+    "us-east-2": 1.0,
+    "us-west-1": 1.17,
+    "us-west-2": 1.0
+  },
+  "instanceHourly": {
+    "c5.2xlarge": 0.34,
+    "c5.4xlarge": 0.68,
+    "c5.9xlarge": 1.53,
+    "c5.12xlarge": 2.04,
+    "c5.18xlarge": 3.06,
+    "m5.xlarge": 0.192,
+    "m5.2xlarge": 0.384,
+    "m5.4xlarge": 0.768,
+    "m5.8xlarge": 1.536,
+    "m5.12xlarge": 2.304,
+    "m5.16xlarge": 3.072,
+    "m5.24xlarge": 4.608,
+    "m5a.xlarge": 0.172,
+    "m5a.2xlarge": 0.344,
+    "m5a.4xlarge": 0.688,
+    "m5a.8xlarge": 1.376,
+    "m5a.12xlarge": 2.064,
+    "m5a.16xlarge": 2.752,
+    "m5a.24xlarge": 4.128,
+    "m6i.xlarge": 0.192,
+    "m6i.2xlarge": 0.384,
+    "m6i.4xlarge": 0.768,
+    "m6i.8xlarge": 1.536,
+    "r5.xlarge": 0.252,
+    "r5.2xlarge": 0.504,
+    "r5.4xlarge": 1.008,
+    "r5.8xlarge": 2.016,
+    "r5.12xlarge": 3.024,
+    "r5.16xlarge": 4.032,
+    "r5.24xlarge": 6.048,
+    "r5a.xlarge": 0.226,
+    "r5a.2xlarge": 0.452,
+    "r5a.4xlarge": 0.904,
+    "r5a.8xlarge": 1.808,
+    "z1d.2xlarge": 0.744,
+    "z1d.3xlarge": 1.116,
+    "z1d.6xlarge": 2.232
+  },
+  "volumeGBMonthly": 0.08,
+  "natGatewayHourly": 0.045,
+  "loadBalancerHourly": 0.025
+}
+`)
+
+func templatesPricingAwsJsonBytes() ([]byte, error) {
+	return _templatesPricingAwsJson, nil
+}
+
+func templatesPricingAwsJson() (*asset, error) {
+	bytes, err := templatesPricingAwsJsonBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "templates/pricing/aws.json", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -331,8 +2139,27 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"templates/cloudformation/iam_user_osdCcsAdmin.json": templatesCloudformationIam_user_osdccsadminJson,
-	"templates/policies/osd_scp_policy.json":             templatesPoliciesOsd_scp_policyJson,
+	"templates/cloudformation/iam_user_osdCcsAdmin.json":                                   templatesCloudformationIam_user_osdccsadminJson,
+	"templates/cloudformation/vpc_rosaNetwork.json":                                        templatesCloudformationVpc_rosanetworkJson,
+	"templates/credentialsrequests/4.7/openshift-cluster-csi-drivers.yaml":                 templatesCredentialsrequests47OpenshiftClusterCsiDriversYaml,
+	"templates/credentialsrequests/4.7/openshift-image-registry.yaml":                      templatesCredentialsrequests47OpenshiftImageRegistryYaml,
+	"templates/credentialsrequests/4.7/openshift-ingress.yaml":                             templatesCredentialsrequests47OpenshiftIngressYaml,
+	"templates/credentialsrequests/4.7/openshift-machine-api-aws.yaml":                     templatesCredentialsrequests47OpenshiftMachineApiAwsYaml,
+	"templates/credentialsrequests/4.8/openshift-cloud-network-config-controller-aws.yaml": templatesCredentialsrequests48OpenshiftCloudNetworkConfigControllerAwsYaml,
+	"templates/credentialsrequests/4.8/openshift-cluster-csi-drivers.yaml":                 templatesCredentialsrequests48OpenshiftClusterCsiDriversYaml,
+	"templates/credentialsrequests/4.8/openshift-image-registry.yaml":                      templatesCredentialsrequests48OpenshiftImageRegistryYaml,
+	"templates/credentialsrequests/4.8/openshift-ingress.yaml":                             templatesCredentialsrequests48OpenshiftIngressYaml,
+	"templates/credentialsrequests/4.8/openshift-machine-api-aws.yaml":                     templatesCredentialsrequests48OpenshiftMachineApiAwsYaml,
+	"templates/policies/osd_scp_policy.json":                                               templatesPoliciesOsd_scp_policyJson,
+	"templates/policies/sts_installer_permission_policy.json":                              templatesPoliciesSts_installer_permission_policyJson,
+	"templates/policies/sts_installer_trust_policy.json":                                   templatesPoliciesSts_installer_trust_policyJson,
+	"templates/policies/sts_instance_controlplane_permission_policy.json":                  templatesPoliciesSts_instance_controlplane_permission_policyJson,
+	"templates/policies/sts_instance_controlplane_trust_policy.json":                       templatesPoliciesSts_instance_controlplane_trust_policyJson,
+	"templates/policies/sts_instance_worker_permission_policy.json":                        templatesPoliciesSts_instance_worker_permission_policyJson,
+	"templates/policies/sts_instance_worker_trust_policy.json":                             templatesPoliciesSts_instance_worker_trust_policyJson,
+	"templates/policies/sts_support_permission_policy.json":                                templatesPoliciesSts_support_permission_policyJson,
+	"templates/policies/sts_support_trust_policy.json":                                     templatesPoliciesSts_support_trust_policyJson,
+	"templates/pricing/aws.json":                                                           templatesPricingAwsJson,
 }
 
 // AssetDir returns the file names below a certain
@@ -379,9 +2206,36 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"templates": &bintree{nil, map[string]*bintree{
 		"cloudformation": &bintree{nil, map[string]*bintree{
 			"iam_user_osdCcsAdmin.json": &bintree{templatesCloudformationIam_user_osdccsadminJson, map[string]*bintree{}},
+			"vpc_rosaNetwork.json": &bintree{templatesCloudformationVpc_rosanetworkJson, map[string]*bintree{}},
+		}},
+		"credentialsrequests": &bintree{nil, map[string]*bintree{
+			"4.7": &bintree{nil, map[string]*bintree{
+				"openshift-cluster-csi-drivers.yaml": &bintree{templatesCredentialsrequests47OpenshiftClusterCsiDriversYaml, map[string]*bintree{}},
+				"openshift-image-registry.yaml": &bintree{templatesCredentialsrequests47OpenshiftImageRegistryYaml, map[string]*bintree{}},
+				"openshift-ingress.yaml": &bintree{templatesCredentialsrequests47OpenshiftIngressYaml, map[string]*bintree{}},
+				"openshift-machine-api-aws.yaml": &bintree{templatesCredentialsrequests47OpenshiftMachineApiAwsYaml, map[string]*bintree{}},
+			}},
+			"4.8": &bintree{nil, map[string]*bintree{
+				"openshift-cloud-network-config-controller-aws.yaml": &bintree{templatesCredentialsrequests48OpenshiftCloudNetworkConfigControllerAwsYaml, map[string]*bintree{}},
+				"openshift-cluster-csi-drivers.yaml": &bintree{templatesCredentialsrequests48OpenshiftClusterCsiDriversYaml, map[string]*bintree{}},
+				"openshift-image-registry.yaml": &bintree{templatesCredentialsrequests48OpenshiftImageRegistryYaml, map[string]*bintree{}},
+				"openshift-ingress.yaml": &bintree{templatesCredentialsrequests48OpenshiftIngressYaml, map[string]*bintree{}},
+				"openshift-machine-api-aws.yaml": &bintree{templatesCredentialsrequests48OpenshiftMachineApiAwsYaml, map[string]*bintree{}},
+			}},
 		}},
 		"policies": &bintree{nil, map[string]*bintree{
 			"osd_scp_policy.json": &bintree{templatesPoliciesOsd_scp_policyJson, map[string]*bintree{}},
+			"sts_installer_permission_policy.json": &bintree{templatesPoliciesSts_installer_permission_policyJson, map[string]*bintree{}},
+			"sts_installer_trust_policy.json": &bintree{templatesPoliciesSts_installer_trust_policyJson, map[string]*bintree{}},
+			"sts_instance_controlplane_permission_policy.json": &bintree{templatesPoliciesSts_instance_controlplane_permission_policyJson, map[string]*bintree{}},
+			"sts_instance_controlplane_trust_policy.json": &bintree{templatesPoliciesSts_instance_controlplane_trust_policyJson, map[string]*bintree{}},
+			"sts_instance_worker_permission_policy.json": &bintree{templatesPoliciesSts_instance_worker_permission_policyJson, map[string]*bintree{}},
+			"sts_instance_worker_trust_policy.json": &bintree{templatesPoliciesSts_instance_worker_trust_policyJson, map[string]*bintree{}},
+			"sts_support_permission_policy.json": &bintree{templatesPoliciesSts_support_permission_policyJson, map[string]*bintree{}},
+			"sts_support_trust_policy.json": &bintree{templatesPoliciesSts_support_trust_policyJson, map[string]*bintree{}},
+		}},
+		"pricing": &bintree{nil, map[string]*bintree{
+			"aws.json": &bintree{templatesPricingAwsJson, map[string]*bintree{}},
 		}},
 	}},
 }}
@@ -0,0 +1,183 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package accountroles
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/logging"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	prefix              string
+	permissionsBoundary string
+	externalID          string
+	tags                []string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "account-roles",
+	Aliases: []string{"accountroles"},
+	Short:   "Create account-wide IAM roles for STS clusters",
+	Long: "Create the installer, support, control plane and worker IAM roles, and their permission " +
+		"policies, that are needed to create clusters that use AWS STS.",
+	Example: `  # Create the account roles with the default prefix
+  rosa create account-roles
+
+  # Create the account roles with a custom prefix and permissions boundary
+  rosa create account-roles --prefix=myorg \
+    --permissions-boundary=arn:aws:iam::123456789012:policy/boundary`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		aws.DefaultRolePrefix,
+		"User-defined prefix for the names of the roles and of their policies.",
+	)
+	flags.StringVar(
+		&args.permissionsBoundary,
+		"permissions-boundary",
+		"",
+		"The ARN of the policy that is used to set the permissions boundary of the roles.",
+	)
+	flags.StringVar(
+		&args.externalID,
+		"external-id",
+		"",
+		"Optional external ID that Red Hat will need to present in order to assume the installer role. "+
+			"The same value must then be passed to 'rosa create cluster'.",
+	)
+	flags.StringSliceVar(
+		&args.tags,
+		"tags",
+		nil,
+		"Apply user defined tags to the roles. "+
+			"Tags are comma separated, for example: --tags=foo:bar,bar:baz",
+	)
+
+	interactive.AddFlag(flags)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+	var err error
+
+	prefix := args.prefix
+	permissionsBoundary := args.permissionsBoundary
+	if interactive.Enabled() {
+		prefix, err = interactive.GetString(interactive.Input{
+			Question: "Role prefix",
+			Help:     cmd.Flags().Lookup("prefix").Usage,
+			Default:  prefix,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid role prefix: %s", err)
+			os.Exit(1)
+		}
+		permissionsBoundary, err = interactive.GetString(interactive.Input{
+			Question: "Permissions boundary ARN (optional)",
+			Help:     cmd.Flags().Lookup("permissions-boundary").Usage,
+			Default:  permissionsBoundary,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid policy ARN: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	err = aws.ValidatePrefix(prefix)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if permissionsBoundary != "" {
+		err = aws.ValidatePolicyARN(permissionsBoundary)
+		if err != nil {
+			reporter.Errorf("Expected a valid permissions boundary: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	tagsList, err := awstags.Parse(args.tags)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	err = awstags.Validate(tagsList)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+
+	roleARNs := map[string]string{}
+	for _, role := range aws.AccountRoles {
+		trustPolicy, err := aws.ReadPolicyTemplate(role.TrustPolicyPath(), map[string]string{
+			"aws_account_id": aws.RedHatAccountID,
+		})
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+		if args.externalID != "" && role.Flag == "role-arn" {
+			trustPolicy, err = aws.AddExternalIDCondition(trustPolicy, args.externalID)
+			if err != nil {
+				reporter.Errorf("%s", err)
+				os.Exit(1)
+			}
+		}
+		permissionPolicy, err := aws.ReadPolicyTemplate(role.PermissionPolicyPath(), nil)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+
+		roleARN, err := aws.EnsureRole(reporter, awsClient, role.RoleName(prefix), role.PolicyName(prefix),
+			trustPolicy, permissionPolicy, permissionsBoundary, tagsList)
+		if err != nil {
+			reporter.Errorf("Failed to create role '%s': %v", role.RoleName(prefix), err)
+			os.Exit(1)
+		}
+		roleARNs[role.Flag] = roleARN
+	}
+
+	command := "rosa create cluster --cluster-name <cluster-name>"
+	for _, role := range aws.AccountRoles {
+		command += fmt.Sprintf(" --%s %s", role.Flag, roleARNs[role.Flag])
+	}
+	if args.externalID != "" {
+		command += fmt.Sprintf(" --external-id %s", args.externalID)
+	}
+	reporter.Infof("To create a cluster with these roles, run the following command:\n\n"+
+		"   %s\n", command)
+}
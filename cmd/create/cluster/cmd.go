@@ -25,16 +25,21 @@ import (
 	"time"
 
 	awssdk "github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	clusterdescribe "github.com/openshift/rosa/cmd/describe/cluster"
 	installLogs "github.com/openshift/rosa/cmd/logs/install"
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/cost"
 	"github.com/openshift/rosa/pkg/interactive"
 	"github.com/openshift/rosa/pkg/interactive/confirm"
 	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/network"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/properties"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
@@ -44,14 +49,36 @@ var args struct {
 	// Watch logs during cluster installation
 	watch bool
 
+	// Wait for the cluster to be installed
+	wait    bool
+	timeout time.Duration
+
 	// Simulate creating a cluster
 	dryRun bool
+
+	// Only run the checks that don't need to contact OCM or AWS
+	validateOnly bool
+
+	// Print the cluster in the given format instead of creating it
+	export string
+
+	// Show the estimated cost of the cluster before creating it
+	estimateCost bool
+	pricingFile  string
+
 	// Create a fake cluster with no AWS resources
 	fakeCluster bool
 
 	// Disable SCP checks in the installer
 	disableSCPChecks bool
 
+	// Path of a cluster spec file to read the options from
+	fromFile string
+	// Name of a saved cluster profile to read the options from
+	clusterProfile string
+	// Availability zones read from the spec file, as there is no flag for them
+	availabilityZones []string
+
 	// Basic options
 	private            bool
 	privateLink        bool
@@ -64,6 +91,7 @@ var args struct {
 	channelGroup       string
 	flavour            string
 	etcdEncryption     bool
+	kmsKeyARN          string
 
 	// Scaling options
 	computeMachineType string
@@ -83,6 +111,12 @@ var args struct {
 	// unless using PrivateLink, in which case it should only be one private per availability zone
 	subnetIDs []string
 
+	// Cluster-wide proxy
+	httpProxy                 string
+	httpsProxy                string
+	noProxy                   string
+	additionalTrustBundleFile string
+
 	// STS
 	roleARN          string
 	externalID       string
@@ -102,7 +136,22 @@ var Cmd = &cobra.Command{
   rosa create cluster --cluster-name=mycluster
 
   # Create a cluster in the us-east-2 region
-  rosa create cluster --cluster-name=mycluster --region=us-east-2`,
+  rosa create cluster --cluster-name=mycluster --region=us-east-2
+
+  # Create a cluster from a spec file, overriding the name given in the file
+  rosa create cluster --from-file=mycluster.yaml --cluster-name=othercluster
+
+  # Create a cluster with the defaults saved in the "prod" profile
+  rosa create cluster --cluster-name=mycluster --cluster-profile=prod
+
+  # Create a cluster and wait up to 90 minutes for it to be ready
+  rosa create cluster --cluster-name=mycluster --wait --timeout=90m
+
+  # Show the estimated monthly cost of a cluster without creating it
+  rosa create cluster --cluster-name=mycluster --multi-az --estimate-cost --dry-run
+
+  # Print the Terraform resources of a cluster from a spec file instead of creating it
+  rosa create cluster --from-file=mycluster.yaml --export=terraform > mycluster.tf`,
 	Run: run,
 }
 
@@ -126,6 +175,20 @@ func init() {
 		"",
 		"Name of the cluster. This will be used when generating a sub-domain for your cluster on openshiftapps.com.",
 	)
+	flags.StringVar(
+		&args.fromFile,
+		"from-file",
+		"",
+		"Path of a YAML or JSON cluster spec file to read the cluster options from. "+
+			"Options given on the command line take precedence over the ones in the file.",
+	)
+	flags.StringVar(
+		&args.clusterProfile,
+		"cluster-profile",
+		"",
+		"Name of a profile saved with 'rosa create profile' to read the cluster options from. "+
+			"Options given on the command line or in the spec file take precedence over the ones in the profile.",
+	)
 	flags.StringVar(
 		&args.roleARN,
 		"role-arn",
@@ -171,10 +234,11 @@ func init() {
 		&args.tags,
 		"tags",
 		nil,
-		"Apply user defined tags to all resources created by ROSA in AWS."+
-			"Tags are comma separated, for example: --tags=foo:bar,bar:baz",
+		"Apply user defined tags to all resources created by ROSA in AWS. "+
+			"Tags are comma separated 'key:value' pairs, for example: --tags=foo:bar,bar:baz. "+
+			"Keys starting with 'aws:' and the keys used by ROSA, like 'rosa_cluster_id', are reserved. "+
+			"Tags can't be changed after the cluster is created.",
 	)
-	flags.MarkHidden("tags")
 	flags.BoolVar(
 		&args.multiAZ,
 		"multi-az",
@@ -207,7 +271,15 @@ func init() {
 		&args.etcdEncryption,
 		"etcd-encryption",
 		false,
-		"Enable etcd encryption for your cluster to provide an additional layer of data security.",
+		clusterspec.EtcdEncryptionUsage,
+	)
+
+	flags.StringVar(
+		&args.kmsKeyARN,
+		"kms-key-arn",
+		"",
+		"ARN of a customer managed KMS key used to encrypt the EBS volumes of the cluster and, "+
+			"if etcd encryption is enabled, the etcd data. The key must be in the region of the cluster.",
 	)
 
 	flags.StringVar(
@@ -243,6 +315,37 @@ func init() {
 			"Leave empty for installer provisioned subnet IDs.",
 	)
 
+	flags.StringVar(
+		&args.httpProxy,
+		"http-proxy",
+		"",
+		"A proxy URL to use for creating HTTP connections outside the cluster. The URL scheme must be http. "+
+			"Requires existing subnets.",
+	)
+
+	flags.StringVar(
+		&args.httpsProxy,
+		"https-proxy",
+		"",
+		"A proxy URL to use for creating HTTPS connections outside the cluster. Requires existing subnets.",
+	)
+
+	flags.StringVar(
+		&args.noProxy,
+		"no-proxy",
+		"",
+		"A comma-separated list of destination domain names, IP addresses or CIDR blocks for which "+
+			"the proxy should not be used.",
+	)
+
+	flags.StringVar(
+		&args.additionalTrustBundleFile,
+		"additional-trust-bundle-file",
+		"",
+		"A file containing a PEM encoded X.509 certificate bundle that will be added to the trusted "+
+			"certificates of the nodes, for example the CA of a TLS inspecting proxy.",
+	)
+
 	// Scaling options
 	flags.StringVar(
 		&args.computeMachineType,
@@ -325,6 +428,21 @@ func init() {
 		"Watch cluster installation logs.",
 	)
 
+	flags.BoolVar(
+		&args.wait,
+		"wait",
+		false,
+		"Block until the cluster is ready. Exits with 2 if the installation fails and with 3 if "+
+			"it doesn't finish before the timeout.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		time.Hour,
+		"Maximum time to wait for the cluster to be ready, used with '--wait'.",
+	)
+
 	flags.BoolVar(
 		&args.dryRun,
 		"dry-run",
@@ -332,6 +450,32 @@ func init() {
 		"Simulate creating the cluster.",
 	)
 
+	flags.BoolVar(
+		&args.validateOnly,
+		"validate-only",
+		false,
+		"Only check the options, without contacting OCM or AWS, and report all the problems found. "+
+			"Checks that need the services, like the available versions or the existing subnets, "+
+			"are skipped.",
+	)
+
+	flags.StringVar(
+		&args.export,
+		"export",
+		"",
+		fmt.Sprintf("Print the configuration of the cluster in the given format instead of creating it. "+
+			"Allowed formats are %s", []string{clusterspec.FormatTerraform}),
+	)
+
+	flags.BoolVar(
+		&args.estimateCost,
+		"estimate-cost",
+		false,
+		"Show a rough estimate of the monthly AWS cost of the cluster before creating it. "+
+			"Use together with '--dry-run' to only show the estimate.",
+	)
+	clusterdescribe.AddPricingFileFlag(flags, &args.pricingFile)
+
 	flags.BoolVar(
 		&args.fakeCluster,
 		"fake-cluster",
@@ -348,6 +492,75 @@ func run(cmd *cobra.Command, _ []string) {
 	logger := logging.CreateLoggerOrExit(reporter)
 	var err error
 
+	// Read the options that weren't given on the command line from the spec file:
+	if args.fromFile != "" {
+		file, err := clusterspec.Load(args.fromFile)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+		err = applySpec(cmd, file, fmt.Sprintf("spec file '%s'", args.fromFile))
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	// Then read the options that are still missing from the profile. Values of the profile that
+	// conflict with the command line or the spec file, like autoscaling when the number of compute
+	// nodes is given, are ignored:
+	if args.clusterProfile != "" {
+		path, err := ocm.ProfilesLocation()
+		if err != nil {
+			reporter.Errorf("Failed to get location of profiles: %v", err)
+			os.Exit(1)
+		}
+		profiles, err := clusterspec.LoadProfiles(path)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+		profile, ok := profiles[args.clusterProfile]
+		if !ok {
+			reporter.Errorf("Profile '%s' doesn't exist. Try running 'rosa list profiles' to see all profiles.",
+				args.clusterProfile)
+			os.Exit(1)
+		}
+		err = applySpec(cmd, profile, fmt.Sprintf("profile '%s'", args.clusterProfile))
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+	}
+
+	if args.timeout <= 0 {
+		reporter.Errorf("Expected a positive timeout, got '%s'", args.timeout)
+		os.Exit(1)
+	}
+
+	if args.export != "" && args.export != clusterspec.FormatTerraform {
+		reporter.Errorf("Unknown export format '%s'. Valid formats are %s", args.export,
+			[]string{clusterspec.FormatTerraform})
+		os.Exit(1)
+	}
+
+	// Check the options without contacting OCM or AWS:
+	if args.validateOnly {
+		if interactive.Enabled() {
+			reporter.Errorf("Interactive mode can't be used with '--validate-only'")
+			os.Exit(1)
+		}
+		problems := validateFlags(cmd)
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				reporter.Errorf("%s", problem)
+			}
+			os.Exit(1)
+		}
+		reporter.Infof("Cluster '%s' is valid", args.clusterName)
+		os.Exit(0)
+	}
+
 	// Create the client for the OCM API:
 	ocmClient, err := ocm.NewClient().
 		Logger(logger).
@@ -376,6 +589,137 @@ func run(cmd *cobra.Command, _ []string) {
 			"Any optional fields can be left empty and a default will be selected.")
 	}
 
+	// Ask the questions, and let the user review and change the answers in interactive mode:
+	clusterConfig, err := askClusterSpec(cmd, reporter, logger, ocmClient, awsCreator, nil)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if interactive.Enabled() {
+		clusterConfig = reviewClusterSpec(cmd, reporter, logger, ocmClient, awsCreator, clusterConfig)
+	}
+	clusterName := clusterConfig.Name
+
+	// Cost estimate:
+	estimateCost := args.estimateCost
+	if interactive.Enabled() {
+		estimateCost, err = interactive.GetBool(interactive.Input{
+			Question: "Estimate cost",
+			Help:     cmd.Flags().Lookup("estimate-cost").Usage,
+			Default:  estimateCost,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid estimate-cost value: %s", err)
+			os.Exit(1)
+		}
+	}
+	if estimateCost {
+		pool := cost.ComputePool{
+			Name:         "default",
+			InstanceType: clusterConfig.ComputeMachineType,
+			MinNodes:     clusterConfig.ComputeNodes,
+			MaxNodes:     clusterConfig.ComputeNodes,
+		}
+		if pool.InstanceType == "" {
+			// Same default as the service:
+			pool.InstanceType = "m5.xlarge"
+		}
+		if clusterConfig.Autoscaling {
+			pool.MinNodes = clusterConfig.MinReplicas
+			pool.MaxNodes = clusterConfig.MaxReplicas
+		}
+		clusterdescribe.PrintCostEstimate(reporter, args.pricingFile, &cost.Cluster{
+			Region:       clusterConfig.Region,
+			MultiAZ:      clusterConfig.MultiAZ,
+			ComputePools: []cost.ComputePool{pool},
+			Private:      *clusterConfig.Private,
+			ExistingVPC:  len(clusterConfig.SubnetIds) > 0,
+		})
+		if interactive.Enabled() && !args.dryRun && !confirm.Confirm("create cluster '%s'", clusterName) {
+			os.Exit(0)
+		}
+	}
+
+	if args.export != "" {
+		export := &clusterspec.TerraformExport{Spec: clusterConfig}
+		fmt.Print(export.Render())
+		os.Exit(0)
+	}
+
+	reporter.Infof("Creating cluster '%s'", clusterName)
+	if interactive.Enabled() {
+		command := buildCommand(clusterConfig)
+		reporter.Infof("To create this cluster again in the future, you can run:\n   %s", command)
+	}
+	reporter.Infof("To view a list of clusters and their status, run 'rosa list clusters'")
+
+	cluster, err := ocmClient.CreateCluster(clusterConfig)
+	if err != nil {
+		if args.dryRun {
+			reporter.Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
+		} else {
+			reporter.Errorf("Failed to create cluster: %s", err)
+		}
+		os.Exit(1)
+	}
+
+	if args.dryRun {
+		reporter.Infof(
+			"Creating cluster '%s' should succeed. Run without the '--dry-run' flag to create the cluster.",
+			clusterName)
+		os.Exit(0)
+	}
+
+	reporter.Infof("Cluster '%s' has been created.", clusterName)
+	reporter.Infof(
+		"Once the cluster is installed you will need to add an Identity Provider " +
+			"before you can login into the cluster. See 'rosa create idp --help' " +
+			"for more information.")
+
+	waitStart := time.Now()
+	if args.watch {
+		installLogs.Cmd.Run(installLogs.Cmd, []string{clusterName})
+	} else if !args.wait {
+		reporter.Infof(
+			"To determine when your cluster is Ready, run 'rosa describe cluster -c %s'.",
+			clusterName,
+		)
+		reporter.Infof(
+			"To watch your cluster installation logs, run 'rosa logs install -c %s --watch'.",
+			clusterName,
+		)
+	}
+
+	result := ocm.WaitSucceeded
+	if args.wait {
+		result, err = ocmClient.WaitForClusterAndReport(reporter, cluster, ocm.WaitInstall,
+			args.timeout-time.Since(waitStart))
+		if err != nil {
+			reporter.Errorf("Failed to get state of cluster '%s': %v", clusterName, err)
+			os.Exit(1)
+		}
+	}
+
+	clusterdescribe.Cmd.Run(clusterdescribe.Cmd, []string{clusterName})
+
+	if result != ocm.WaitSucceeded {
+		os.Exit(result.ExitCode())
+	}
+}
+
+// askClusterSpec asks the questions of the interactive mode, or reads the values of the flags
+// otherwise, and validates them, returning the first problem found. When the list of flags to ask
+// for isn't nil only the questions for those flags are asked, so that some of the answers can be
+// changed reading the rest of them from the flags.
+func askClusterSpec(cmd *cobra.Command, reporter *rprtr.Object, logger *logrus.Logger, ocmClient *ocm.Client,
+	awsCreator *aws.Creator, only []string) (ocm.Spec, error) {
+	var err error
+
+	// ask returns true if the question for the given flag has to be asked:
+	ask := func(flag string) bool {
+		return interactive.Enabled() && (only == nil || contains(only, flag))
+	}
+
 	// Get cluster name
 	clusterName := strings.Trim(args.clusterName, " \t")
 
@@ -384,7 +728,7 @@ func run(cmd *cobra.Command, _ []string) {
 		reporter.Infof("Enabling interactive mode")
 	}
 
-	if interactive.Enabled() {
+	if ask("cluster-name") {
 		clusterName, err = interactive.GetString(interactive.Input{
 			Question: "Cluster name",
 			Help:     cmd.Flags().Lookup("cluster-name").Usage,
@@ -392,8 +736,7 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid cluster name: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid cluster name: %s", err)
 		}
 	}
 
@@ -401,10 +744,9 @@ func run(cmd *cobra.Command, _ []string) {
 	clusterName = strings.Trim(clusterName, " \t")
 
 	if !ocm.IsValidClusterName(clusterName) {
-		reporter.Errorf("Cluster name must consist" +
+		return ocm.Spec{}, errors.New("Cluster name must consist" +
 			" of no more than 15 lowercase alphanumeric characters or '-', " +
 			"start with a letter, and end with an alphanumeric character.")
-		os.Exit(1)
 	}
 
 	// AWS ARN Role
@@ -416,14 +758,13 @@ func run(cmd *cobra.Command, _ []string) {
 				"create STS clusters. Otherwise, switch to IAM credentials.",
 		})
 		if err != nil {
-			reporter.Errorf("%s", err)
-			os.Exit(1)
+			return ocm.Spec{}, err
 		}
 		interactive.Enable()
 		reporter.Infof("Enabling interactive mode")
 	}
 
-	if interactive.Enabled() {
+	if ask("role-arn") {
 		roleARN, err = interactive.GetString(interactive.Input{
 			Question: "Role ARN",
 			Help:     cmd.Flags().Lookup("role-arn").Usage,
@@ -431,50 +772,44 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: awsCreator.IsSTS,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid ARN: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid ARN: %s", err)
 		}
 	}
 	if roleARN != "" {
-		_, err = arn.Parse(roleARN)
+		err = clusterspec.ValidateRoleARN("Installer role ARN", roleARN)
 		if err != nil {
-			reporter.Errorf("Expected a valid Role ARN: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, err
 		}
 	} else {
 		aws.CheckStackReadyForCreateCluster(reporter, logger)
 	}
 
 	externalID := args.externalID
-	if roleARN != "" && interactive.Enabled() {
+	if roleARN != "" && ask("external-id") {
 		externalID, err = interactive.GetString(interactive.Input{
 			Question: "External ID",
 			Help:     cmd.Flags().Lookup("external-id").Usage,
+			Default:  externalID,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid External ID: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid External ID: %s", err)
 		}
 	}
 
 	supportRoleARN := args.supportRoleARN
-	if roleARN != "" && interactive.Enabled() {
+	if roleARN != "" && ask("support-role-arn") {
 		supportRoleARN, err = interactive.GetString(interactive.Input{
 			Question: "Support Role ARN",
 			Help:     cmd.Flags().Lookup("support-role-arn").Usage,
 			Default:  supportRoleARN,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid ARN: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid ARN: %s", err)
 		}
 	}
-	if supportRoleARN != "" {
-		_, err = arn.Parse(supportRoleARN)
-		if err != nil {
-			reporter.Errorf("Expected a valid Support Role ARN: %s", err)
-			os.Exit(1)
-		}
+	err = clusterspec.ValidateRoleARN("Support role ARN", supportRoleARN)
+	if err != nil {
+		return ocm.Spec{}, err
 	}
 
 	// OpenShift version:
@@ -482,13 +817,12 @@ func run(cmd *cobra.Command, _ []string) {
 	channelGroup := args.channelGroup
 	versionList, err := getVersionList(ocmClient, channelGroup, roleARN != "")
 	if err != nil {
-		reporter.Errorf("%s", err)
-		os.Exit(1)
+		return ocm.Spec{}, err
 	}
 	if version == "" {
 		version = versionList[0]
 	}
-	if interactive.Enabled() {
+	if ask("version") {
 		version, err = interactive.GetOption(interactive.Input{
 			Question: "OpenShift version",
 			Help:     cmd.Flags().Lookup("version").Usage,
@@ -496,14 +830,12 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  version,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid OpenShift version: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid OpenShift version: %s", err)
 		}
 	}
 	version, err = validateVersion(version, versionList, channelGroup, roleARN != "")
 	if err != nil {
-		reporter.Errorf("Expected a valid OpenShift version: %s", err)
-		os.Exit(1)
+		return ocm.Spec{}, fmt.Errorf("Expected a valid OpenShift version: %s", err)
 	}
 
 	operatorIAMRoles := args.operatorIAMRoles
@@ -511,15 +843,13 @@ func run(cmd *cobra.Command, _ []string) {
 	if roleARN != "" {
 		for _, role := range operatorIAMRoles {
 			if !strings.Contains(role, ",") {
-				reporter.Errorf("Expected operator IAM roles to be a comma-separated " +
+				return ocm.Spec{}, errors.New("Expected operator IAM roles to be a comma-separated " +
 					"list of name,namespace,role_arn")
-				os.Exit(1)
 			}
 			roleData := strings.Split(role, ",")
 			if len(roleData) != 3 {
-				reporter.Errorf("Expected operator IAM roles to be a comma-separated " +
+				return ocm.Spec{}, errors.New("Expected operator IAM roles to be a comma-separated " +
 					"list of name,namespace,role_arn")
-				os.Exit(1)
 			}
 			operatorIAMRoleList = append(operatorIAMRoleList, ocm.OperatorIAMRole{
 				Name:      roleData[0],
@@ -535,11 +865,13 @@ func run(cmd *cobra.Command, _ []string) {
 			"\t--from quay.io/openshift-release-dev/ocp-release:%s-x86_64",
 			ocpVersion,
 		)
-		operatorIAMRoleHelp := fmt.Sprintf("To extract the necessary operator credential requests for your "+
-			"specific version, run the following command and enter the name and namespace in the "+
+		operatorIAMRoleHelp := fmt.Sprintf("To create the operator IAM roles for your specific version, "+
+			"run 'rosa create operator-roles --prefix <cluster-name> --version %s --oidc-endpoint-url <url>', "+
+			"which prints the values to use. Alternatively, to extract the necessary operator credential requests, "+
+			"run the following command and enter the name and namespace in the "+
 			"secretRef, as well as a role ARN that has similar permissions to the spec of the generated "+
-			"files:\n %s", credRequest)
-		if interactive.Enabled() {
+			"files:\n %s", ocpVersion, credRequest)
+		if ask("operator-iam-roles") {
 			for {
 				addRole, err := interactive.GetBool(interactive.Input{
 					Question: "Add an operator IAM role?",
@@ -557,8 +889,7 @@ func run(cmd *cobra.Command, _ []string) {
 					Required: true,
 				})
 				if err != nil {
-					reporter.Errorf("Expected the name of the operator IAM role: %s", err)
-					os.Exit(1)
+					return ocm.Spec{}, fmt.Errorf("Expected the name of the operator IAM role: %s", err)
 				}
 				namespace, err := interactive.GetString(interactive.Input{
 					Question: "Operator IAM role namespace",
@@ -566,8 +897,7 @@ func run(cmd *cobra.Command, _ []string) {
 					Required: true,
 				})
 				if err != nil {
-					reporter.Errorf("Expected the namespace of the operator IAM role: %s", err)
-					os.Exit(1)
+					return ocm.Spec{}, fmt.Errorf("Expected the namespace of the operator IAM role: %s", err)
 				}
 				iamRoleARN, err := interactive.GetString(interactive.Input{
 					Question: "Operator IAM role ARN",
@@ -575,13 +905,7 @@ func run(cmd *cobra.Command, _ []string) {
 					Required: true,
 				})
 				if err != nil {
-					reporter.Errorf("Expected the ARN of the operator IAM role: %s", err)
-					os.Exit(1)
-				}
-				_, err = arn.Parse(iamRoleARN)
-				if err != nil {
-					reporter.Errorf("Expected a valid operator IAM role ARN: %s", err)
-					os.Exit(1)
+					return ocm.Spec{}, fmt.Errorf("Expected the ARN of the operator IAM role: %s", err)
 				}
 				operatorIAMRoleList = append(operatorIAMRoleList, ocm.OperatorIAMRole{
 					Namespace: namespace,
@@ -591,101 +915,93 @@ func run(cmd *cobra.Command, _ []string) {
 			}
 		}
 		if len(operatorIAMRoleList) == 0 {
-			reporter.Errorf("Expected a list of operator IAM roles. %s", operatorIAMRoleHelp)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a list of operator IAM roles. %s", operatorIAMRoleHelp)
+		}
+		err = clusterspec.ValidateOperatorRoles(operatorIAMRoleList)
+		if err != nil {
+			return ocm.Spec{}, err
 		}
 	}
 
 	// Instance IAM Roles
 	masterRoleARN := args.masterRoleARN
-	if roleARN != "" && interactive.Enabled() {
+	if roleARN != "" && ask("master-iam-role") {
 		masterRoleARN, err = interactive.GetString(interactive.Input{
 			Question: "Master IAM Role ARN",
 			Help:     cmd.Flags().Lookup("master-iam-role").Usage,
 			Default:  masterRoleARN,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid master IAM role ARN: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid master IAM role ARN: %s", err)
 		}
 	}
-	if masterRoleARN != "" {
-		_, err = arn.Parse(masterRoleARN)
-		if err != nil {
-			reporter.Errorf("Expected a valid instance IAM role ARN: %s", err)
-			os.Exit(1)
-		}
+	err = clusterspec.ValidateRoleARN("Master role ARN", masterRoleARN)
+	if err != nil {
+		return ocm.Spec{}, err
 	}
 
 	workerRoleARN := args.workerRoleARN
-	if roleARN != "" && interactive.Enabled() {
+	if roleARN != "" && ask("worker-iam-role") {
 		workerRoleARN, err = interactive.GetString(interactive.Input{
 			Question: "Worker IAM Role ARN",
 			Help:     cmd.Flags().Lookup("worker-iam-role").Usage,
 			Default:  workerRoleARN,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid worker IAM role ARN: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid worker IAM role ARN: %s", err)
 		}
 	}
-	if workerRoleARN != "" {
-		_, err = arn.Parse(workerRoleARN)
-		if err != nil {
-			reporter.Errorf("Expected a valid instance IAM role ARN: %s", err)
-			os.Exit(1)
-		}
+	err = clusterspec.ValidateRoleARN("Worker role ARN", workerRoleARN)
+	if err != nil {
+		return ocm.Spec{}, err
 	}
 
 	// Custom tags for AWS resources
 	tags := args.tags
-	tagsList := map[string]string{}
-	if len(tags) > 0 && interactive.Enabled() {
+	if ask("tags") {
 		tagsInput, err := interactive.GetString(interactive.Input{
 			Question: "Tags",
 			Help:     cmd.Flags().Lookup("tags").Usage,
 			Default:  strings.Join(tags, ","),
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid set of tags: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid set of tags: %s", err)
 		}
 		tags = strings.Split(tagsInput, ",")
 	}
-	if len(tags) > 0 {
-		for _, tag := range tags {
-			t := strings.Split(tag, ":")
-			tagsList[t[0]] = strings.TrimSpace(t[1])
-		}
+	tagsList, err := awstags.Parse(tags)
+	if err != nil {
+		return ocm.Spec{}, err
+	}
+	err = awstags.Validate(tagsList)
+	if err != nil {
+		return ocm.Spec{}, err
 	}
 
 	// Multi-AZ:
 	multiAZ := args.multiAZ
-	if interactive.Enabled() {
+	if ask("multi-az") {
 		multiAZ, err = interactive.GetBool(interactive.Input{
 			Question: "Multiple availability zones",
 			Help:     cmd.Flags().Lookup("multi-az").Usage,
 			Default:  multiAZ,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid multi-AZ value: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid multi-AZ value: %s", err)
 		}
 	}
 
 	// Get AWS region
 	region, err := aws.GetRegion(arguments.GetRegion())
 	if err != nil {
-		reporter.Errorf("Error getting region: %v", err)
-		os.Exit(1)
+		return ocm.Spec{}, fmt.Errorf("Error getting region: %v", err)
 	}
 
 	regionList, regionAZ, err := ocmClient.GetRegionList(multiAZ, roleARN, externalID)
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return ocm.Spec{}, err
 	}
-	if interactive.Enabled() {
+	if ask("region") {
 		region, err = interactive.GetOption(interactive.Input{
 			Question: "AWS region",
 			Help:     cmd.Flags().Lookup("region").Usage,
@@ -694,23 +1010,19 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: true,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid AWS region: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid AWS region: %s", err)
 		}
 	}
 
 	if region == "" {
-		reporter.Errorf("Expected a valid AWS region")
-		os.Exit(1)
+		return ocm.Spec{}, errors.New("Expected a valid AWS region")
 	} else {
 		if supportsMultiAZ, found := regionAZ[region]; found {
 			if !supportsMultiAZ && multiAZ {
-				reporter.Errorf("Region '%s' does not support multiple availability zones", region)
-				os.Exit(1)
+				return ocm.Spec{}, fmt.Errorf("Region '%s' does not support multiple availability zones", region)
 			}
 		} else {
-			reporter.Errorf("Region '%s' is not supported for this AWS account", region)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Region '%s' is not supported for this AWS account", region)
 		}
 	}
 
@@ -719,24 +1031,22 @@ func run(cmd *cobra.Command, _ []string) {
 		Logger(logger).
 		Build()
 	if err != nil {
-		reporter.Errorf("Failed to create awsClient: %s", err)
-		os.Exit(1)
+		return ocm.Spec{}, fmt.Errorf("Failed to create awsClient: %s", err)
 	}
 
 	useExistingVPC := false
 	privateLink := args.privateLink
 	privateLinkWarning := "Once the cluster is created, this option cannot be changed."
-	if interactive.Enabled() {
+	if ask("private-link") {
 		privateLink, err = interactive.GetBool(interactive.Input{
 			Question: "PrivateLink cluster",
 			Help:     fmt.Sprintf("%s %s", cmd.Flags().Lookup("private-link").Usage, privateLinkWarning),
 			Default:  privateLink,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid private-link value: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid private-link value: %s", err)
 		}
-	} else if privateLink {
+	} else if privateLink && !interactive.Enabled() {
 		reporter.Warnf("You are choosing to use AWS PrivateLink for your cluster. %s", privateLinkWarning)
 		if !confirm.Confirm("use AWS PrivateLink for cluster '%s'", clusterName) {
 			os.Exit(0)
@@ -751,7 +1061,7 @@ func run(cmd *cobra.Command, _ []string) {
 	subnetIDs := args.subnetIDs
 	subnetsProvided := len(subnetIDs) > 0
 	reporter.Debugf("Received the following subnetIDs: %v", args.subnetIDs)
-	if !useExistingVPC && !subnetsProvided && interactive.Enabled() {
+	if !useExistingVPC && !subnetsProvided && ask("subnet-ids") {
 		existingVPCHelp := "To install into an existing VPC you need to ensure that your VPC is configured " +
 			"with two subnets for each availability zone that you want the cluster installed into. "
 		if privateLink {
@@ -763,8 +1073,7 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  useExistingVPC,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid value: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid value: %s", err)
 		}
 	}
 
@@ -772,14 +1081,12 @@ func run(cmd *cobra.Command, _ []string) {
 	if useExistingVPC || subnetsProvided {
 		subnets, err := awsClient.GetSubnetIDs()
 		if err != nil {
-			reporter.Errorf("Failed to get the list of subnets: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Failed to get the list of subnets: %s", err)
 		}
 
 		mapSubnetToAZ := make(map[string]string)
 		mapAZCreated := make(map[string]bool)
 		options := make([]string, len(subnets))
-		defaultOptions := make([]string, len(subnetIDs))
 
 		// Verify subnets provided exist.
 		if subnetsProvided {
@@ -791,8 +1098,7 @@ func run(cmd *cobra.Command, _ []string) {
 					}
 				}
 				if !verifiedSubnet {
-					reporter.Errorf("Could not find the following subnet provided: %s", subnetArg)
-					os.Exit(1)
+					return ocm.Spec{}, fmt.Errorf("Could not find the following subnet provided: %s", subnetArg)
 				}
 			}
 		}
@@ -803,15 +1109,14 @@ func run(cmd *cobra.Command, _ []string) {
 
 			// Create the options to prompt the user.
 			options[i] = setSubnetOption(subnetID, availabilityZone)
-			if subnetsProvided {
-				for _, subnetArg := range subnetIDs {
-					defaultOptions = append(defaultOptions, setSubnetOption(subnetArg, availabilityZone))
-				}
-			}
 			mapSubnetToAZ[subnetID] = availabilityZone
 			mapAZCreated[availabilityZone] = false
 		}
-		if ((privateLink && !subnetsProvided) || interactive.Enabled()) &&
+		defaultOptions := []string{}
+		for _, subnetArg := range subnetIDs {
+			defaultOptions = append(defaultOptions, setSubnetOption(subnetArg, mapSubnetToAZ[subnetArg]))
+		}
+		if ((privateLink && !subnetsProvided) || ask("subnet-ids")) &&
 			len(options) > 0 && (!multiAZ || len(mapAZCreated) >= 3) {
 			subnetIDs, err = interactive.GetMultipleOptions(interactive.Input{
 				Question: "Subnet IDs",
@@ -821,8 +1126,7 @@ func run(cmd *cobra.Command, _ []string) {
 				Default:  defaultOptions,
 			})
 			if err != nil {
-				reporter.Errorf("Expected valid subnet IDs: %s", err)
-				os.Exit(1)
+				return ocm.Spec{}, fmt.Errorf("Expected valid subnet IDs: %s", err)
 			}
 			for i, subnet := range subnetIDs {
 				subnetIDs[i] = parseSubnet(subnet)
@@ -839,8 +1143,78 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 	reporter.Debugf("Found the following availability zones for the subnets provided: %v", availabilityZones)
 
+	// Zones given in the spec file only apply when the cluster isn't installed into existing subnets
+	if len(availabilityZones) == 0 {
+		availabilityZones = args.availabilityZones
+	}
+
+	// Cluster-wide proxy, only supported when the cluster is installed into existing subnets:
+	httpProxy := args.httpProxy
+	httpsProxy := args.httpsProxy
+	noProxy := args.noProxy
+	additionalTrustBundleFile := args.additionalTrustBundleFile
+	existingVPC := len(subnetIDs) > 0
+	if existingVPC && ask("http-proxy") {
+		httpProxy, err = interactive.GetString(interactive.Input{
+			Question: "HTTP proxy",
+			Help:     cmd.Flags().Lookup("http-proxy").Usage,
+			Default:  httpProxy,
+		})
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Expected a valid http-proxy value: %s", err)
+		}
+	}
+	if existingVPC && ask("https-proxy") {
+		httpsProxy, err = interactive.GetString(interactive.Input{
+			Question: "HTTPS proxy",
+			Help:     cmd.Flags().Lookup("https-proxy").Usage,
+			Default:  httpsProxy,
+		})
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Expected a valid https-proxy value: %s", err)
+		}
+	}
+	if existingVPC && (httpProxy != "" || httpsProxy != "") && ask("no-proxy") {
+		noProxy, err = interactive.GetString(interactive.Input{
+			Question: "No proxy",
+			Help:     cmd.Flags().Lookup("no-proxy").Usage,
+			Default:  noProxy,
+		})
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Expected a valid no-proxy value: %s", err)
+		}
+	}
+	if existingVPC && ask("additional-trust-bundle-file") {
+		additionalTrustBundleFile, err = interactive.GetCert(interactive.Input{
+			Question: "Additional trust bundle file path",
+			Help:     cmd.Flags().Lookup("additional-trust-bundle-file").Usage,
+			Default:  additionalTrustBundleFile,
+		})
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Expected a valid additional trust bundle file: %s", err)
+		}
+	}
+	err = clusterspec.ValidateProxy(existingVPC, httpProxy, httpsProxy, noProxy,
+		additionalTrustBundleFile)
+	if err != nil {
+		return ocm.Spec{}, err
+	}
+	additionalTrustBundle := ""
+	if additionalTrustBundleFile != "" {
+		err = interactive.ValidateCertFile(additionalTrustBundleFile)
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Expected a valid additional trust bundle file: %s", err)
+		}
+		additionalTrustBundle, err = ocm.ReadTrustBundle(additionalTrustBundleFile)
+		if err != nil {
+			return ocm.Spec{}, err
+		}
+	}
+	// The spec only contains the content of the bundle, so keep the path for the review step:
+	args.additionalTrustBundleFile = additionalTrustBundleFile
+
 	etcdEncryption := args.etcdEncryption
-	if interactive.Enabled() {
+	if ask("etcd-encryption") {
 		etcdEncryption, err = interactive.GetBool(interactive.Input{
 			Question: "Enable etcd encryption",
 			Help:     cmd.Flags().Lookup("etcd-encryption").Usage,
@@ -848,19 +1222,47 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: false,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid value for etcd-encryption: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid value for etcd-encryption: %s", err)
 		}
 	}
 
+	kmsKeyARN := args.kmsKeyARN
+	if ask("kms-key-arn") {
+		kmsKeyARN, err = interactive.GetString(interactive.Input{
+			Question: "KMS key ARN",
+			Help:     cmd.Flags().Lookup("kms-key-arn").Usage,
+			Default:  kmsKeyARN,
+			Required: false,
+		})
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Expected a valid value for kms-key-arn: %s", err)
+		}
+	}
+	kmsKeyARN = strings.TrimSpace(kmsKeyARN)
+	if kmsKeyARN != "" {
+		err = aws.ValidateKMSKeyARN(kmsKeyARN, region)
+		if err != nil {
+			return ocm.Spec{}, err
+		}
+		key, err := awsClient.GetKMSKey(kmsKeyARN)
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Failed to get KMS key '%s': %v", kmsKeyARN, err)
+		}
+		failures := aws.ValidateKMSKey(key, kmsKeyPrincipals(awsCreator, roleARN, workerRoleARN))
+		if len(failures) > 0 {
+			return ocm.Spec{}, fmt.Errorf("KMS key '%s' can't be used by the cluster:\n  - %s",
+				kmsKeyARN, strings.Join(failures, "\n  - "))
+		}
+		reporter.Debugf("KMS key '%s' can be used by the cluster", kmsKeyARN)
+	}
+
 	// Compute node instance type:
 	computeMachineType := args.computeMachineType
 	computeMachineTypeList, err := ocmClient.GetAvailableMachineTypes()
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return ocm.Spec{}, err
 	}
-	if interactive.Enabled() {
+	if ask("compute-machine-type") {
 		computeMachineType, err = interactive.GetOption(interactive.Input{
 			Question: "Compute nodes instance type",
 			Help:     cmd.Flags().Lookup("compute-machine-type").Usage,
@@ -868,14 +1270,27 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  computeMachineType,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid machine type: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid machine type: %s", err)
 		}
 	}
 	computeMachineType, err = ocm.ValidateMachineType(computeMachineType, computeMachineTypeList, multiAZ)
 	if err != nil {
-		reporter.Errorf("Expected a valid machine type: %s", err)
-		os.Exit(1)
+		return ocm.Spec{}, fmt.Errorf("Expected a valid machine type: %s", err)
+	}
+
+	// Check that EC2 offers the instance type in the zones of the cluster, as otherwise the
+	// installation fails when it tries to create the compute nodes:
+	if computeMachineType != "" {
+		offerings, err := awsClient.GetInstanceTypeZones([]string{computeMachineType})
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Failed to get the availability zones of instance type '%s': %v",
+				computeMachineType, err)
+		}
+		err = aws.ValidateInstanceTypeZones(computeMachineType, offerings[computeMachineType],
+			availabilityZones, multiAZ)
+		if err != nil {
+			return ocm.Spec{}, err
+		}
 	}
 
 	isAutoscalingSet := cmd.Flags().Changed("enable-autoscaling")
@@ -883,7 +1298,7 @@ func run(cmd *cobra.Command, _ []string) {
 
 	// Autoscaling
 	autoscaling := args.autoscalingEnabled
-	if !isReplicasSet && !autoscaling && !isAutoscalingSet && interactive.Enabled() {
+	if !isReplicasSet && !isAutoscalingSet && ask("enable-autoscaling") {
 		autoscaling, err = interactive.GetBool(interactive.Input{
 			Question: "Enable autoscaling",
 			Help:     cmd.Flags().Lookup("enable-autoscaling").Usage,
@@ -891,8 +1306,7 @@ func run(cmd *cobra.Command, _ []string) {
 			Required: false,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid value for enable-autoscaling: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid value for enable-autoscaling: %s", err)
 		}
 	}
 
@@ -904,19 +1318,20 @@ func run(cmd *cobra.Command, _ []string) {
 	if autoscaling {
 		// if the user set compute-nodes and enabled autoscaling
 		if isReplicasSet {
-			reporter.Errorf("Compute-nodes can't be set when autoscaling is enabled")
-			os.Exit(1)
+			return ocm.Spec{}, errors.New("Compute-nodes can't be set when autoscaling is enabled")
 		}
 
 		if multiAZ {
 			if !isMinReplicasSet {
-				minReplicas = 3
+				minReplicas = clusterspec.MinComputeNodes(multiAZ)
 			}
 			if !isMaxReplicasSet {
 				maxReplicas = minReplicas
 			}
 		}
-		if interactive.Enabled() || !isMinReplicasSet {
+		// Replicas that weren't given are asked for even without interactive mode, but a review
+		// step only asks its own questions:
+		if ask("min-replicas") || (only == nil && !isMinReplicasSet) {
 			minReplicas, err = interactive.GetInt(interactive.Input{
 				Question: "Min replicas",
 				Help:     cmd.Flags().Lookup("min-replicas").Usage,
@@ -924,11 +1339,10 @@ func run(cmd *cobra.Command, _ []string) {
 				Required: true,
 			})
 			if err != nil {
-				reporter.Errorf("Expected a valid number of min replicas: %s", err)
-				os.Exit(1)
+				return ocm.Spec{}, fmt.Errorf("Expected a valid number of min replicas: %s", err)
 			}
 		}
-		if interactive.Enabled() || !isMaxReplicasSet {
+		if ask("max-replicas") || (only == nil && !isMaxReplicasSet) {
 			maxReplicas, err = interactive.GetInt(interactive.Input{
 				Question: "Max replicas",
 				Help:     cmd.Flags().Lookup("max-replicas").Usage,
@@ -936,28 +1350,13 @@ func run(cmd *cobra.Command, _ []string) {
 				Required: true,
 			})
 			if err != nil {
-				reporter.Errorf("Expected a valid number of max replicas: %s", err)
-				os.Exit(1)
+				return ocm.Spec{}, fmt.Errorf("Expected a valid number of max replicas: %s", err)
 			}
 		}
 
-		if multiAZ && minReplicas < 3 {
-			reporter.Errorf("Multi AZ cluster requires at least 3 compute nodes")
-			os.Exit(1)
-		}
-		if !multiAZ && minReplicas < 2 {
-			reporter.Errorf("Cluster requires at least 2 compute nodes")
-			os.Exit(1)
-		}
-
-		if minReplicas > maxReplicas {
-			reporter.Errorf("max-replicas must be greater or equal to min-replicas")
-			os.Exit(1)
-		}
-
-		if multiAZ && (minReplicas%3 != 0 || maxReplicas%3 != 0) {
-			reporter.Errorf("Multi AZ clusters require that the number of compute nodes be a multiple of 3")
-			os.Exit(1)
+		err = clusterspec.ValidateAutoscaling(multiAZ, minReplicas, maxReplicas)
+		if err != nil {
+			return ocm.Spec{}, err
 		}
 	}
 
@@ -965,48 +1364,34 @@ func run(cmd *cobra.Command, _ []string) {
 	computeNodes := args.computeNodes
 	// Compute node requirements for multi-AZ clusters are higher
 	if multiAZ && !autoscaling && !isReplicasSet {
-		computeNodes = 3
+		computeNodes = clusterspec.MinComputeNodes(multiAZ)
 	}
 	if !autoscaling {
 		// if the user set min/max replicas and hasn't enabled autoscaling
 		if isMinReplicasSet || isMaxReplicasSet {
-			reporter.Errorf("Autoscaling must be enabled in order to set min and max replicas")
-			os.Exit(1)
+			return ocm.Spec{}, errors.New("Autoscaling must be enabled in order to set min and max replicas")
 		}
 
-		if interactive.Enabled() {
+		if ask("compute-nodes") {
 			computeNodes, err = interactive.GetInt(interactive.Input{
 				Question: "Compute nodes",
 				Help:     cmd.Flags().Lookup("compute-nodes").Usage,
 				Default:  computeNodes,
 			})
 			if err != nil {
-				reporter.Errorf("Expected a valid number of compute nodes: %s", err)
-				os.Exit(1)
+				return ocm.Spec{}, fmt.Errorf("Expected a valid number of compute nodes: %s", err)
 			}
 		}
-		if multiAZ {
-			if computeNodes < 3 {
-				reporter.Errorf("The number of compute nodes needs to be at least 3")
-				os.Exit(1)
-			}
-			if computeNodes%3 != 0 {
-				reporter.Errorf("Multi AZ clusters require that the number of compute nodes be a multiple of 3")
-				os.Exit(1)
-			}
-		} else {
-			if computeNodes < 2 {
-				reporter.Errorf("The number of compute nodes needs to be at least 2")
-				os.Exit(1)
-			}
+		err = clusterspec.ValidateComputeNodes(multiAZ, computeNodes)
+		if err != nil {
+			return ocm.Spec{}, err
 		}
 	}
 
 	// Validate all remaining flags:
 	expiration, err := validateExpiration()
 	if err != nil {
-		reporter.Errorf(fmt.Sprintf("%s", err))
-		os.Exit(1)
+		return ocm.Spec{}, err
 	}
 	var dMachinecidr *net.IPNet
 	var dPodcidr *net.IPNet
@@ -1015,7 +1400,7 @@ func run(cmd *cobra.Command, _ []string) {
 
 	// Machine CIDR:
 	machineCIDR := args.machineCIDR
-	if interactive.Enabled() {
+	if ask("machine-cidr") {
 		if ocm.IsEmptyCIDR(machineCIDR) {
 			machineCIDR = *dMachinecidr
 		}
@@ -1025,14 +1410,13 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  machineCIDR,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid CIDR value: %s", err)
 		}
 	}
 
 	// Service CIDR:
 	serviceCIDR := args.serviceCIDR
-	if interactive.Enabled() {
+	if ask("service-cidr") {
 		if ocm.IsEmptyCIDR(serviceCIDR) {
 			serviceCIDR = *dServicecidr
 		}
@@ -1042,13 +1426,12 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  serviceCIDR,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid CIDR value: %s", err)
 		}
 	}
 	// Pod CIDR:
 	podCIDR := args.podCIDR
-	if interactive.Enabled() {
+	if ask("pod-cidr") {
 		if ocm.IsEmptyCIDR(podCIDR) {
 			podCIDR = *dPodcidr
 		}
@@ -1058,14 +1441,13 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  podCIDR,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid CIDR value: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid CIDR value: %s", err)
 		}
 	}
 
 	// Host prefix:
 	hostPrefix := args.hostPrefix
-	if interactive.Enabled() {
+	if ask("host-prefix") {
 		if hostPrefix == 0 {
 			hostPrefix = dhostPrefix
 		}
@@ -1075,8 +1457,62 @@ func run(cmd *cobra.Command, _ []string) {
 			Default:  hostPrefix,
 		})
 		if err != nil {
-			reporter.Errorf("Expected a valid host prefix value: %s", err)
-			os.Exit(1)
+			return ocm.Spec{}, fmt.Errorf("Expected a valid host prefix value: %s", err)
+		}
+	}
+
+	// Validate the network plan, using the defaults of the service for the values that weren't
+	// given:
+	plan := network.DefaultPlan()
+	plan.MultiAZ = multiAZ
+	plan.MaxReplicas = computeNodes
+	if autoscaling {
+		plan.MaxReplicas = maxReplicas
+	}
+	plan.MachineCIDR = planCIDR(machineCIDR, dMachinecidr, plan.MachineCIDR)
+	plan.ServiceCIDR = planCIDR(serviceCIDR, dServicecidr, plan.ServiceCIDR)
+	plan.PodCIDR = planCIDR(podCIDR, dPodcidr, plan.PodCIDR)
+	if hostPrefix != 0 {
+		plan.HostPrefix = hostPrefix
+	} else if dhostPrefix != 0 {
+		plan.HostPrefix = dhostPrefix
+	}
+	planResult := network.ValidatePlan(plan)
+	if len(planResult.Errors) > 0 {
+		return ocm.Spec{}, fmt.Errorf("Invalid network plan:\n  - %s",
+			strings.Join(planResult.Errors, "\n  - "))
+	}
+	for _, message := range planResult.Warnings {
+		reporter.Warnf("%s", message)
+	}
+	reporter.Debugf("Network plan allows up to %d nodes, %d pods per node and %d machine IPs per "+
+		"availability zone", planResult.MaxNodes, planResult.PodsPerNode, planResult.MachineIPsPerZone)
+
+	// Validate the layout of the existing subnets, as a wrong layout only fails late in the
+	// installation:
+	if len(subnetIDs) > 0 {
+		layout, err := awsClient.GetSubnetLayout(subnetIDs)
+		if err != nil {
+			return ocm.Spec{}, fmt.Errorf("Failed to get the layout of the subnets: %v", err)
+		}
+		report := aws.ValidateSubnetLayout(layout, multiAZ, privateLink, plan.MachineCIDR)
+		if !report.Valid() {
+			return ocm.Spec{}, fmt.Errorf("The subnets aren't valid for this cluster:\n%s", report)
+		}
+		reporter.Debugf("Subnets are valid for this cluster:\n%s", report)
+
+		// Run the PrivateLink preflight, as missing endpoints or DNS settings would otherwise
+		// only be detected when the installation fails:
+		if privateLink {
+			privateLinkLayout, err := awsClient.GetPrivateLinkLayout(layout)
+			if err != nil {
+				return ocm.Spec{}, fmt.Errorf("Failed to get the PrivateLink layout of the subnets: %v", err)
+			}
+			privateLinkReport := aws.ValidatePrivateLink(privateLinkLayout)
+			if !privateLinkReport.Passed() {
+				return ocm.Spec{}, fmt.Errorf("The subnets aren't ready for a PrivateLink cluster:\n%s", privateLinkReport)
+			}
+			reporter.Debugf("Subnets are ready for a PrivateLink cluster:\n%s", privateLinkReport)
 		}
 	}
 
@@ -1087,17 +1523,16 @@ func run(cmd *cobra.Command, _ []string) {
 	} else {
 		privateWarning := "You will not be able to access your cluster until " +
 			"you edit network settings in your cloud provider."
-		if interactive.Enabled() {
+		if ask("private") {
 			private, err = interactive.GetBool(interactive.Input{
 				Question: "Private cluster",
 				Help:     fmt.Sprintf("%s %s", cmd.Flags().Lookup("private").Usage, privateWarning),
 				Default:  private,
 			})
 			if err != nil {
-				reporter.Errorf("Expected a valid private value: %s", err)
-				os.Exit(1)
+				return ocm.Spec{}, fmt.Errorf("Expected a valid private value: %s", err)
 			}
-		} else if private {
+		} else if private && !interactive.Enabled() {
 			reporter.Warnf("You are choosing to make your cluster private. %s", privateWarning)
 			if !confirm.Confirm("set cluster '%s' as private", clusterName) {
 				os.Exit(0)
@@ -1113,6 +1548,7 @@ func run(cmd *cobra.Command, _ []string) {
 		ChannelGroup:       channelGroup,
 		Flavour:            args.flavour,
 		EtcdEncryption:     etcdEncryption,
+		KMSKeyArn:          kmsKeyARN,
 		Expiration:         expiration,
 		ComputeMachineType: computeMachineType,
 		ComputeNodes:       computeNodes,
@@ -1142,51 +1578,47 @@ func run(cmd *cobra.Command, _ []string) {
 		clusterConfig.CustomProperties = map[string]string{}
 		clusterConfig.CustomProperties[properties.FakeCluster] = "true"
 	}
-
-	reporter.Infof("Creating cluster '%s'", clusterName)
-	if interactive.Enabled() {
-		command := buildCommand(clusterConfig)
-		reporter.Infof("To create this cluster again in the future, you can run:\n   %s", command)
+	if httpProxy != "" {
+		clusterConfig.HTTPProxy = &httpProxy
 	}
-	reporter.Infof("To view a list of clusters and their status, run 'rosa list clusters'")
-
-	_, err = ocmClient.CreateCluster(clusterConfig)
-	if err != nil {
-		if args.dryRun {
-			reporter.Errorf("Creating cluster '%s' should fail: %s", clusterName, err)
-		} else {
-			reporter.Errorf("Failed to create cluster: %s", err)
-		}
-		os.Exit(1)
+	if httpsProxy != "" {
+		clusterConfig.HTTPSProxy = &httpsProxy
 	}
-
-	if args.dryRun {
-		reporter.Infof(
-			"Creating cluster '%s' should succeed. Run without the '--dry-run' flag to create the cluster.",
-			clusterName)
-		os.Exit(0)
+	if noProxy != "" {
+		clusterConfig.NoProxy = &noProxy
+	}
+	if additionalTrustBundle != "" {
+		clusterConfig.AdditionalTrustBundle = &additionalTrustBundle
 	}
 
-	reporter.Infof("Cluster '%s' has been created.", clusterName)
-	reporter.Infof(
-		"Once the cluster is installed you will need to add an Identity Provider " +
-			"before you can login into the cluster. See 'rosa create idp --help' " +
-			"for more information.")
+	return clusterConfig, nil
+}
 
-	if args.watch {
-		installLogs.Cmd.Run(installLogs.Cmd, []string{clusterName})
-	} else {
-		reporter.Infof(
-			"To determine when your cluster is Ready, run 'rosa describe cluster -c %s'.",
-			clusterName,
-		)
-		reporter.Infof(
-			"To watch your cluster installation logs, run 'rosa logs install -c %s --watch'.",
-			clusterName,
-		)
+// planCIDR returns the CIDR given by the user, or the default of the flavour if it is empty,
+// or the fallback if the flavour doesn't have one.
+func planCIDR(value net.IPNet, flavourDefault *net.IPNet, fallback *net.IPNet) *net.IPNet {
+	if !ocm.IsEmptyCIDR(value) {
+		return &value
 	}
+	if flavourDefault != nil {
+		return flavourDefault
+	}
+	return fallback
+}
 
-	clusterdescribe.Cmd.Run(clusterdescribe.Cmd, []string{clusterName})
+// kmsKeyPrincipals returns the principals that need to be able to use the KMS key: the installer
+// and worker roles for STS clusters, or the admin user of the account otherwise.
+func kmsKeyPrincipals(creator *aws.Creator, roleARN string, workerRoleARN string) []string {
+	if roleARN != "" {
+		principals := []string{roleARN}
+		if workerRoleARN != "" {
+			principals = append(principals, workerRoleARN)
+		}
+		return principals
+	}
+	return []string{
+		fmt.Sprintf("arn:%s:iam::%s:user/%s", creator.Partition(), creator.AccountID, aws.AdminUserName),
+	}
 }
 
 // Validate OpenShift versions
@@ -1283,96 +1715,138 @@ func parseSubnet(subnetOption string) string {
 	return strings.Split(subnetOption, " ")[0]
 }
 
-func buildCommand(spec ocm.Spec) string {
-	command := "rosa create cluster"
-	command += fmt.Sprintf(" --cluster-name %s", spec.Name)
-	if spec.RoleARN != "" {
-		command += fmt.Sprintf(" --role-arn %s", spec.RoleARN)
-	}
-	if spec.ExternalID != "" {
-		command += fmt.Sprintf(" --external-id %s", spec.ExternalID)
+// Sets the flags that weren't explicitly given in the command line to the values read from a
+// cluster spec file or profile, so that they go through exactly the same validations as regular
+// flags. Flags set by an earlier call take precedence too, and values that conflict with the flags
+// already set are ignored. The source is only used to report errors.
+func applySpec(cmd *cobra.Command, file *clusterspec.File, source string) error {
+	flags := cmd.Flags()
+	set := map[string]string{}
+	flags.Visit(func(flag *pflag.Flag) {
+		set[flag.Name] = flag.Value.String()
+	})
+	// The deprecated '--name' flag is an alias for '--cluster-name':
+	if name, ok := set["name"]; ok {
+		set["cluster-name"] = name
 	}
-	if spec.SupportRoleARN != "" {
-		command += fmt.Sprintf(" --support-role-arn %s", spec.SupportRoleARN)
+
+	if file.Network != nil && len(args.availabilityZones) == 0 {
+		args.availabilityZones = file.Network.AvailabilityZones
 	}
-	if len(spec.OperatorIAMRoles) > 0 {
-		for _, role := range spec.OperatorIAMRoles {
-			command += fmt.Sprintf(" --operator-iam-roles %s,%s,%s", role.Name, role.Namespace, role.RoleARN)
+
+	for _, value := range file.MissingFlagValues(set) {
+		err := flags.Set(value.Name, value.Value)
+		if err != nil {
+			return fmt.Errorf("Invalid value '%s' for '%s' in %s: %v",
+				value.Value, value.Name, source, err)
 		}
 	}
-	if spec.MasterRoleARN != "" {
-		command += fmt.Sprintf(" --master-iam-role %s", spec.MasterRoleARN)
+
+	return nil
+}
+
+// validateFlags collects the values of the flags in a spec file and returns all the problems found
+// by the checks that don't need to contact OCM or AWS.
+func validateFlags(cmd *cobra.Command) []string {
+	problems := []string{}
+	flags := cmd.Flags()
+
+	file := &clusterspec.File{
+		Version:          clusterspec.CurrentVersion,
+		Name:             strings.Trim(args.clusterName, " \t"),
+		Region:           arguments.GetRegion(),
+		MultiAZ:          &args.multiAZ,
+		OpenShiftVersion: args.version,
+		ChannelGroup:     args.channelGroup,
+		EtcdEncryption:   &args.etcdEncryption,
+		KMSKeyARN:        args.kmsKeyARN,
+		Compute:          &clusterspec.Compute{},
+		Network: &clusterspec.Network{
+			HostPrefix:        args.hostPrefix,
+			Private:           &args.private,
+			PrivateLink:       &args.privateLink,
+			SubnetIDs:         args.subnetIDs,
+			AvailabilityZones: args.availabilityZones,
+			HTTPProxy:         args.httpProxy,
+			HTTPSProxy:        args.httpsProxy,
+			NoProxy:           args.noProxy,
+			TrustBundleFile:   args.additionalTrustBundleFile,
+		},
+	}
+
+	tags, err := awstags.Parse(args.tags)
+	if err != nil {
+		problems = append(problems, err.Error())
 	}
-	if spec.WorkerRoleARN != "" {
-		command += fmt.Sprintf(" --worker-iam-role %s", spec.WorkerRoleARN)
+	file.Tags = tags
+
+	if flags.Changed("compute-nodes") {
+		file.Compute.Nodes = args.computeNodes
 	}
-	if len(spec.Tags) > 0 {
-		tags := []string{}
-		for k, v := range spec.Tags {
-			tags = append(tags, fmt.Sprintf("%s:%s", k, v))
+	if args.autoscalingEnabled {
+		file.Compute.Autoscaling = &clusterspec.Autoscaling{}
+		if flags.Changed("min-replicas") {
+			file.Compute.Autoscaling.MinReplicas = args.minReplicas
 		}
-		command += fmt.Sprintf(" --tags %s", strings.Join(tags, ","))
-	}
-	if spec.MultiAZ {
-		command += " --multi-az"
-	}
-	if spec.Region != "" {
-		command += fmt.Sprintf(" --region %s", spec.Region)
-	}
-	if spec.DisableSCPChecks != nil && *spec.DisableSCPChecks {
-		command += " --disable-scp-checks"
-	}
-	if spec.Version != "" {
-		if spec.ChannelGroup != ocm.DefaultChannelGroup {
-			command += fmt.Sprintf(" --channel-group %s", spec.ChannelGroup)
+		if flags.Changed("max-replicas") {
+			file.Compute.Autoscaling.MaxReplicas = args.maxReplicas
 		}
-		command += fmt.Sprintf(" --version %s", strings.TrimPrefix(spec.Version, "openshift-v"))
+	} else if flags.Changed("min-replicas") || flags.Changed("max-replicas") {
+		problems = append(problems, "Autoscaling must be enabled in order to set min and max replicas")
 	}
 
-	// Only account for expiration duration, as a fixed date may be obsolete if command is re-run later
-	if args.expirationDuration != 0 {
-		command += fmt.Sprintf(" --expiration %s", args.expirationDuration)
+	for _, cidr := range []struct {
+		value  net.IPNet
+		target *string
+	}{
+		{args.machineCIDR, &file.Network.MachineCIDR},
+		{args.serviceCIDR, &file.Network.ServiceCIDR},
+		{args.podCIDR, &file.Network.PodCIDR},
+	} {
+		if !ocm.IsEmptyCIDR(cidr.value) {
+			*cidr.target = cidr.value.String()
+		}
 	}
 
-	if spec.Autoscaling {
-		command += " --enable-autoscaling"
-		if spec.MinReplicas > 0 {
-			command += fmt.Sprintf(" --min-replicas %d", spec.MinReplicas)
+	if args.roleARN != "" || args.supportRoleARN != "" || args.masterRoleARN != "" ||
+		args.workerRoleARN != "" || len(args.operatorIAMRoles) > 0 {
+		file.STS = &clusterspec.STS{
+			RoleARN:        args.roleARN,
+			ExternalID:     args.externalID,
+			SupportRoleARN: args.supportRoleARN,
+			MasterRoleARN:  args.masterRoleARN,
+			WorkerRoleARN:  args.workerRoleARN,
 		}
-		if spec.MaxReplicas > 0 {
-			command += fmt.Sprintf(" --max-replicas %d", spec.MaxReplicas)
-		}
-	} else {
-		if spec.ComputeNodes != 0 {
-			command += fmt.Sprintf(" --compute-nodes %d", spec.ComputeNodes)
+		for _, role := range args.operatorIAMRoles {
+			roleData := strings.Split(role, ",")
+			if len(roleData) != 3 {
+				problems = append(problems, fmt.Sprintf("Expected operator IAM role '%s' to be a "+
+					"comma-separated list of name,namespace,role_arn", role))
+				continue
+			}
+			file.STS.OperatorRoles = append(file.STS.OperatorRoles, clusterspec.OperatorRole{
+				Name:      roleData[0],
+				Namespace: roleData[1],
+				RoleARN:   roleData[2],
+			})
 		}
 	}
-	if spec.ComputeMachineType != "" {
-		command += fmt.Sprintf(" --compute-machine-type %s", spec.ComputeMachineType)
-	}
 
-	if !ocm.IsEmptyCIDR(spec.MachineCIDR) {
-		command += fmt.Sprintf(" --machine-cidr %s", spec.MachineCIDR.String())
-	}
-	if !ocm.IsEmptyCIDR(spec.ServiceCIDR) {
-		command += fmt.Sprintf(" --service-cidr %s", spec.ServiceCIDR.String())
-	}
-	if !ocm.IsEmptyCIDR(spec.PodCIDR) {
-		command += fmt.Sprintf(" --pod-cidr %s", spec.PodCIDR.String())
-	}
-	if spec.HostPrefix != 0 {
-		command += fmt.Sprintf(" --host-prefix %d", spec.HostPrefix)
-	}
-	if spec.PrivateLink != nil && *spec.PrivateLink {
-		command += " --private-link"
-	} else if spec.Private != nil && *spec.Private {
-		command += " --private"
-	}
-	if len(spec.SubnetIds) > 0 {
-		command += fmt.Sprintf(" --subnet-ids %s", strings.Join(spec.SubnetIds, ","))
+	_, err = validateExpiration()
+	if err != nil {
+		problems = append(problems, err.Error())
 	}
-	if spec.EtcdEncryption {
-		command += " --etcd-encryption"
+
+	return append(problems, file.Validate()...)
+}
+
+func buildCommand(spec ocm.Spec) string {
+	command := clusterspec.BuildCommand(spec)
+
+	// Only account for expiration duration, as a fixed date may be obsolete if command is re-run later
+	if args.expirationDuration != 0 {
+		command += fmt.Sprintf(" --expiration %s", args.expirationDuration)
 	}
+
 	return command
 }
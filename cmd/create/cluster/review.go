@@ -0,0 +1,365 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the review step of the interactive mode, which shows all the answers and
+// lets the user change some of them before creating the cluster.
+
+package cluster
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// reviewCreate is the option of the review step that accepts the answers.
+const reviewCreate = "Create the cluster"
+
+// wizardStep is a group of questions that can be asked again from the review step. The questions
+// are those of the flags in 'keep' and 'reset'. The answers given to the flags in 'keep' are used
+// as the defaults of their questions, while the flags in 'reset' go back to their defaults,
+// because changing the step can make their values invalid.
+type wizardStep struct {
+	title string
+	keep  []string
+	reset []string
+}
+
+// flags returns the names of the flags whose questions are asked again by the step.
+func (s wizardStep) flags() []string {
+	return append(append([]string{}, s.keep...), s.reset...)
+}
+
+var wizardSteps = []wizardStep{
+	{
+		title: "Cluster name",
+		keep:  []string{"cluster-name"},
+	},
+	{
+		title: "STS roles",
+		keep: []string{
+			"role-arn",
+			"external-id",
+			"support-role-arn",
+			"operator-iam-roles",
+			"master-iam-role",
+			"worker-iam-role",
+		},
+		// Not all the versions support STS:
+		reset: []string{"version"},
+	},
+	{
+		title: "OpenShift version",
+		keep:  []string{"version"},
+	},
+	{
+		title: "Tags",
+		keep:  []string{"tags"},
+	},
+	{
+		title: "Availability zones and region",
+		keep: []string{
+			"multi-az",
+			"region",
+			"compute-machine-type",
+		},
+		// The subnets, the KMS key and the number of nodes depend on the zones and region:
+		reset: []string{
+			"subnet-ids",
+			"http-proxy",
+			"https-proxy",
+			"no-proxy",
+			"additional-trust-bundle-file",
+			"kms-key-arn",
+			"enable-autoscaling",
+			"min-replicas",
+			"max-replicas",
+			"compute-nodes",
+		},
+	},
+	{
+		title: "PrivateLink and subnets",
+		keep: []string{
+			"private-link",
+			"subnet-ids",
+			"private",
+		},
+		// The proxy can only be used with existing subnets:
+		reset: []string{
+			"http-proxy",
+			"https-proxy",
+			"no-proxy",
+			"additional-trust-bundle-file",
+		},
+	},
+	{
+		title: "Cluster-wide proxy",
+		keep: []string{
+			"http-proxy",
+			"https-proxy",
+			"no-proxy",
+			"additional-trust-bundle-file",
+		},
+	},
+	{
+		title: "Encryption",
+		keep:  []string{"etcd-encryption", "kms-key-arn"},
+	},
+	{
+		title: "Compute nodes",
+		keep: []string{
+			"compute-machine-type",
+			"enable-autoscaling",
+			"min-replicas",
+			"max-replicas",
+			"compute-nodes",
+		},
+	},
+	{
+		title: "Network CIDRs",
+		keep:  []string{"machine-cidr", "service-cidr", "pod-cidr", "host-prefix"},
+	},
+	{
+		title: "Private cluster",
+		keep:  []string{"private"},
+	},
+}
+
+// answerFlags are the flags that the answers are written back to before asking the questions of
+// a step again. The trust bundle file isn't included because the spec only contains its content,
+// its path is kept by askClusterSpec.
+var answerFlags = []string{
+	"cluster-name",
+	"role-arn",
+	"external-id",
+	"support-role-arn",
+	"operator-iam-roles",
+	"master-iam-role",
+	"worker-iam-role",
+	"version",
+	"channel-group",
+	"tags",
+	"multi-az",
+	"region",
+	"private-link",
+	"subnet-ids",
+	"http-proxy",
+	"https-proxy",
+	"no-proxy",
+	"etcd-encryption",
+	"kms-key-arn",
+	"compute-machine-type",
+	"enable-autoscaling",
+	"min-replicas",
+	"max-replicas",
+	"compute-nodes",
+	"machine-cidr",
+	"service-cidr",
+	"pod-cidr",
+	"host-prefix",
+	"private",
+}
+
+// reviewClusterSpec shows the answers and asks the user to either accept them or select a step to
+// change. The questions of the selected step are asked again, and all the validations run again
+// with the new answers, until the user accepts them. When the new answers aren't valid the error
+// is shown and the previous answers are reviewed again.
+func reviewClusterSpec(cmd *cobra.Command, reporter *rprtr.Object, logger *logrus.Logger,
+	ocmClient *ocm.Client, awsCreator *aws.Creator, spec ocm.Spec) ocm.Spec {
+	options := []string{reviewCreate}
+	for _, step := range wizardSteps {
+		options = append(options, step.title)
+	}
+
+	for {
+		reporter.Infof("Review the options of cluster '%s':", spec.Name)
+		printReview(spec)
+		answer, err := interactive.GetOption(interactive.Input{
+			Question: "Change an answer or create the cluster",
+			Help: "Select a group of questions to answer them again, the answers that depend on them " +
+				"will be asked and validated again too.",
+			Options:  options,
+			Default:  reviewCreate,
+			Required: true,
+		})
+		if err == terminal.InterruptErr {
+			os.Exit(1)
+		}
+		if err != nil {
+			reporter.Errorf("Expected a valid option: %s", err)
+			continue
+		}
+		if answer == reviewCreate {
+			return spec
+		}
+		for _, step := range wizardSteps {
+			if step.title != answer {
+				continue
+			}
+			err = setAnswers(cmd.Flags(), spec, step)
+			if err != nil {
+				reporter.Errorf("%s", err)
+				break
+			}
+			changed, err := askClusterSpec(cmd, reporter, logger, ocmClient, awsCreator, step.flags())
+			if err != nil {
+				reporter.Errorf("%s", err)
+				break
+			}
+			spec = changed
+		}
+	}
+}
+
+// setAnswers writes the answers contained in the spec back to the flags, so that they are used as
+// the defaults when the questions are asked again, and prepares the flags of the given step.
+func setAnswers(flags *pflag.FlagSet, spec ocm.Spec, step wizardStep) error {
+	for _, name := range answerFlags {
+		resetFlag(flags.Lookup(name))
+	}
+	for _, value := range clusterspec.FromSpec(spec).FlagValues() {
+		if !contains(answerFlags, value.Name) {
+			continue
+		}
+		err := flags.Set(value.Name, value.Value)
+		if err != nil {
+			return fmt.Errorf("Failed to keep answer '%s' for '%s': %v", value.Value, value.Name, err)
+		}
+	}
+	for _, name := range step.reset {
+		resetFlag(flags.Lookup(name))
+	}
+	// The questions of the step are only asked for flags that weren't given explicitly:
+	for _, name := range step.keep {
+		flags.Lookup(name).Changed = false
+	}
+	return nil
+}
+
+// resetFlag sets the flag back to its default value, as if it hadn't been given. CIDRs keep their
+// value, as the default of their flags can't be parsed, but they are always written back.
+func resetFlag(flag *pflag.Flag) {
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		slice.Replace([]string{})
+	} else if flag.Value.Type() != "ipNet" {
+		flag.Value.Set(flag.DefValue)
+	}
+	flag.Changed = false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// printReview prints all the answers of the interactive mode.
+func printReview(spec ocm.Spec) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	line := func(name string, value string) {
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(writer, "  %s:\t%s\n", name, value)
+	}
+	yesNo := func(value bool) string {
+		if value {
+			return "yes"
+		}
+		return "no"
+	}
+
+	line("Cluster name", spec.Name)
+	if spec.RoleARN != "" {
+		line("Role ARN", spec.RoleARN)
+		line("External ID", spec.ExternalID)
+		line("Support role ARN", spec.SupportRoleARN)
+		for _, role := range spec.OperatorIAMRoles {
+			line("Operator IAM role", fmt.Sprintf("%s/%s: %s", role.Namespace, role.Name, role.RoleARN))
+		}
+		line("Master IAM role ARN", spec.MasterRoleARN)
+		line("Worker IAM role ARN", spec.WorkerRoleARN)
+	}
+	line("OpenShift version", strings.TrimPrefix(spec.Version, "openshift-v"))
+	tags := []string{}
+	for key, value := range spec.Tags {
+		tags = append(tags, fmt.Sprintf("%s:%s", key, value))
+	}
+	sort.Strings(tags)
+	line("Tags", strings.Join(tags, ", "))
+	line("Multiple availability zones", yesNo(spec.MultiAZ))
+	line("AWS region", spec.Region)
+	line("PrivateLink", yesNo(spec.PrivateLink != nil && *spec.PrivateLink))
+	line("Subnet IDs", strings.Join(spec.SubnetIds, ", "))
+	if len(spec.SubnetIds) > 0 {
+		if spec.HTTPProxy != nil {
+			line("HTTP proxy", *spec.HTTPProxy)
+		}
+		if spec.HTTPSProxy != nil {
+			line("HTTPS proxy", *spec.HTTPSProxy)
+		}
+		if spec.NoProxy != nil {
+			line("No proxy", *spec.NoProxy)
+		}
+		line("Additional trust bundle", args.additionalTrustBundleFile)
+	}
+	line("Etcd encryption", yesNo(spec.EtcdEncryption))
+	line("KMS key ARN", spec.KMSKeyArn)
+	line("Compute nodes instance type", spec.ComputeMachineType)
+	if spec.Autoscaling {
+		line("Compute nodes", fmt.Sprintf("autoscaling from %d to %d", spec.MinReplicas, spec.MaxReplicas))
+	} else {
+		line("Compute nodes", fmt.Sprintf("%d", spec.ComputeNodes))
+	}
+	for _, cidr := range []struct {
+		name  string
+		value net.IPNet
+	}{
+		{"Machine CIDR", spec.MachineCIDR},
+		{"Service CIDR", spec.ServiceCIDR},
+		{"Pod CIDR", spec.PodCIDR},
+	} {
+		if ocm.IsEmptyCIDR(cidr.value) {
+			line(cidr.name, "default")
+		} else {
+			line(cidr.name, cidr.value.String())
+		}
+	}
+	if spec.HostPrefix != 0 {
+		line("Host prefix", fmt.Sprintf("%d", spec.HostPrefix))
+	} else {
+		line("Host prefix", "default")
+	}
+	line("Private cluster", yesNo(spec.Private != nil && *spec.Private))
+	writer.Flush()
+}
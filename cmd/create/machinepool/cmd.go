@@ -20,13 +20,16 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/rosa/pkg/aws"
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/kube"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	c "github.com/openshift/rosa/pkg/ocm"
@@ -47,6 +50,11 @@ var args struct {
 	maxReplicas        int
 	labels             string
 	taints             string
+	tags               []string
+	useSpotInstances   bool
+	spotMaxPrice       string
+	availabilityZones  []string
+	subnets            []string
 }
 
 var Cmd = &cobra.Command{
@@ -65,7 +73,17 @@ var Cmd = &cobra.Command{
 	--min-replicas=3 --max-replicas=6 --instance-type=m5.xlarge
 
   # Add a machine pool with labels to a cluster
-  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --instance-type=r5.2xlarge --labels=foo=bar,bar=baz`,
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --instance-type=r5.2xlarge --labels=foo=bar,bar=baz
+
+  # Add a machine pool whose instances have an additional AWS tag
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --tags=cost-center:gpu
+
+  # Add a machine pool of spot instances that costs at most $0.10 per instance and hour
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --use-spot-instances \
+	--spot-max-price=0.10
+
+  # Add a machine pool of 2 replicas pinned to one of the availability zones of a multi AZ cluster
+  rosa create machinepool -c mycluster --name=mp-1 --replicas=2 --availability-zone=us-east-1a`,
 	Run: run,
 }
 
@@ -123,6 +141,38 @@ func init() {
 		"Instance type that should be used.",
 	)
 
+	flags.BoolVar(
+		&args.useSpotInstances,
+		"use-spot-instances",
+		false,
+		"Use spot instances for the machine pool. The instance type needs to support spot "+
+			"instances in the region of the cluster.",
+	)
+
+	flags.StringVar(
+		&args.spotMaxPrice,
+		"spot-max-price",
+		"on-demand",
+		"Maximum hourly price, in US dollars, of each spot instance. The default is the "+
+			"on-demand price of the instance type.",
+	)
+
+	flags.StringSliceVar(
+		&args.availabilityZones,
+		"availability-zone",
+		nil,
+		"Availability zones of the cluster that the machine pool is pinned to. By default the machine "+
+			"pool is spread across all the availability zones of the cluster.",
+	)
+
+	flags.StringSliceVar(
+		&args.subnets,
+		"subnet",
+		nil,
+		"Private subnets of the cluster that the machine pool is pinned to, for clusters installed "+
+			"into existing subnets. Only one subnet can be used in each availability zone.",
+	)
+
 	flags.StringVar(
 		&args.labels,
 		"labels",
@@ -135,10 +185,19 @@ func init() {
 		&args.taints,
 		"taints",
 		"",
-		"Taints for machine pool. Format should be a comma-separated list of 'key=value:ScheduleType'. "+
+		"Taints for machine pool. Format should be a comma-separated list of 'key=value:Effect', where "+
+			"the effect is one of NoSchedule, PreferNoSchedule or NoExecute. "+
 			"This list will overwrite any modifications made to Node taints on an ongoing basis.",
 	)
 
+	flags.StringSliceVar(
+		&args.tags,
+		"tags",
+		nil,
+		"Apply user defined tags to the AWS resources of the machine pool, in addition to the tags "+
+			"of the cluster. Tags are comma separated 'key:value' pairs, for example: --tags=foo:bar,bar:baz.",
+	)
+
 	interactive.AddFlag(flags)
 }
 
@@ -225,6 +284,10 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	// Availability zones and subnets:
+	zones, subnets := getZones(cmd, reporter, logger, cluster)
+	zonesCount := zoneCount(cluster, zones)
+
 	isMinReplicasSet := cmd.Flags().Changed("min-replicas")
 	isMaxReplicasSet := cmd.Flags().Changed("max-replicas")
 	isAutoscalingSet := cmd.Flags().Changed("enable-autoscaling")
@@ -271,10 +334,7 @@ func run(cmd *cobra.Command, _ []string) {
 			reporter.Errorf("min-replicas must be a non-negative integer.")
 			os.Exit(1)
 		}
-		if cluster.MultiAZ() && minReplicas%3 != 0 {
-			reporter.Errorf("Multi AZ clusters require that the replicas be a multiple of 3")
-			os.Exit(1)
-		}
+		checkReplicas(reporter, minReplicas, zonesCount)
 
 		if interactive.Enabled() || !isMaxReplicasSet {
 			maxReplicas, err = interactive.GetInt(interactive.Input{
@@ -292,10 +352,7 @@ func run(cmd *cobra.Command, _ []string) {
 			reporter.Errorf("max-replicas must be greater or equal to min-replicas")
 			os.Exit(1)
 		}
-		if cluster.MultiAZ() && maxReplicas%3 != 0 {
-			reporter.Errorf("Multi AZ clusters require that the replicas be a multiple of 3")
-			os.Exit(1)
-		}
+		checkReplicas(reporter, maxReplicas, zonesCount)
 	} else {
 		// if the user set min/max replicas and hasn't enabled autoscaling
 		if isMinReplicasSet || isMaxReplicasSet {
@@ -314,10 +371,7 @@ func run(cmd *cobra.Command, _ []string) {
 				os.Exit(1)
 			}
 		}
-		if cluster.MultiAZ() && replicas%3 != 0 {
-			reporter.Errorf("Multi AZ clusters require that the replicas be a multiple of 3")
-			os.Exit(1)
-		}
+		checkReplicas(reporter, replicas, zonesCount)
 	}
 	// Machine pool instance type:
 	instanceType := args.instanceType
@@ -352,8 +406,79 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	// Check that EC2 offers the instance type in all the zones where the machine pool will
+	// create nodes:
+	regionClient := clusterAWSClient(reporter, logger, cluster)
+	offerings, err := regionClient.GetInstanceTypeZones([]string{instanceType})
+	if err != nil {
+		reporter.Errorf("Failed to get the availability zones of instance type '%s': %v", instanceType, err)
+		os.Exit(1)
+	}
+	poolZones := zones
+	if len(poolZones) == 0 {
+		poolZones = cluster.Nodes().AvailabilityZones()
+	}
+	err = aws.ValidateInstanceTypeZones(instanceType, offerings[instanceType], poolZones, cluster.MultiAZ())
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	// Spot instances:
+	isSpotSet := cmd.Flags().Changed("use-spot-instances")
+	isSpotMaxPriceSet := cmd.Flags().Changed("spot-max-price")
+	useSpotInstances := args.useSpotInstances
+	spotMaxPrice := args.spotMaxPrice
+	if !isSpotSet && interactive.Enabled() {
+		// A max price given on the command line suggests that the user wants spot instances:
+		useSpotInstances, err = interactive.GetBool(interactive.Input{
+			Question: "Use spot instances",
+			Help:     cmd.Flags().Lookup("use-spot-instances").Usage,
+			Default:  useSpotInstances || isSpotMaxPriceSet,
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid value for use spot instances: %s", err)
+			os.Exit(1)
+		}
+	}
+	if isSpotMaxPriceSet && !useSpotInstances {
+		reporter.Errorf("Spot max price can only be set when using spot instances")
+		os.Exit(1)
+	}
+	var spot *ocm.MachinePoolSpot
+	if useSpotInstances {
+		if !isSpotMaxPriceSet && interactive.Enabled() {
+			spotMaxPrice, err = interactive.GetString(interactive.Input{
+				Question: "Spot instance max price",
+				Help:     cmd.Flags().Lookup("spot-max-price").Usage,
+				Default:  spotMaxPrice,
+				Required: true,
+			})
+			if err != nil {
+				reporter.Errorf("Expected a valid spot max price: %s", err)
+				os.Exit(1)
+			}
+		}
+		spot, err = parseSpot(spotMaxPrice)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+
+		supported, err := regionClient.SupportsSpotInstances(instanceType)
+		if err != nil {
+			reporter.Errorf("Failed to check if instance type '%s' supports spot instances: %v",
+				instanceType, err)
+			os.Exit(1)
+		}
+		if !supported {
+			reporter.Errorf("Instance type '%s' doesn't support spot instances in region '%s'",
+				instanceType, cluster.Region().ID())
+			os.Exit(1)
+		}
+	}
+
 	labels := args.labels
-	labelMap := make(map[string]string)
 	if interactive.Enabled() {
 		labels, err = interactive.GetString(interactive.Input{
 			Question: "Labels",
@@ -365,19 +490,13 @@ func run(cmd *cobra.Command, _ []string) {
 			os.Exit(1)
 		}
 	}
-	if labels != "" {
-		for _, label := range strings.Split(labels, ",") {
-			if !strings.Contains(label, "=") {
-				reporter.Errorf("Expected key=value format for labels")
-				os.Exit(1)
-			}
-			tokens := strings.Split(label, "=")
-			labelMap[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
-		}
+	labelMap, err := kube.ParseLabels(strings.Split(labels, ","))
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
 	}
 
 	taints := args.taints
-	taintBuilders := []*cmv1.TaintBuilder{}
 	if interactive.Enabled() {
 		taints, err = interactive.GetString(interactive.Input{
 			Question: "Taints",
@@ -389,22 +508,56 @@ func run(cmd *cobra.Command, _ []string) {
 			os.Exit(1)
 		}
 	}
-	if taints != "" {
-		for _, taint := range strings.Split(taints, ",") {
-			if !strings.Contains(taint, "=") || !strings.Contains(taint, ":") {
-				reporter.Errorf("Expected key=value:scheduleType format for taints")
-				os.Exit(1)
-			}
-			tokens := strings.FieldsFunc(taint, Split)
-			taintBuilders = append(taintBuilders, cmv1.NewTaint().Key(tokens[0]).Value(tokens[1]).Effect(tokens[2]))
+	taintList, err := kube.ParseTaints(strings.Split(taints, ","))
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	tags := args.tags
+	if interactive.Enabled() {
+		tagsInput, err := interactive.GetString(interactive.Input{
+			Question: "Tags",
+			Help:     cmd.Flags().Lookup("tags").Usage,
+			Default:  strings.Join(tags, ","),
+		})
+		if err != nil {
+			reporter.Errorf("Expected a valid set of tags: %s", err)
+			os.Exit(1)
 		}
+		tags = strings.Split(tagsInput, ",")
+	}
+	tagsList, err := awstags.Parse(tags)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	err = awstags.Validate(tagsList)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	// The tags of the machine pool are merged with the tags of the cluster, so they can't change
+	// them and together they need to be within the limits:
+	mergedTags, err := awstags.Merge(cluster.AWS().Tags(), tagsList)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if len(mergedTags) > awstags.MaxTags {
+		reporter.Errorf("The machine pool would have %d tags including the tags of the cluster, "+
+			"but at most %d are allowed", len(mergedTags), awstags.MaxTags)
+		os.Exit(1)
 	}
 
 	mpBuilder := cmv1.NewMachinePool().
 		ID(name).
 		InstanceType(instanceType).
 		Labels(labelMap).
-		Taints(taintBuilders...)
+		Taints(kube.Builders(taintList)...)
+	if len(zones) > 0 {
+		mpBuilder = mpBuilder.AvailabilityZones(zones...)
+	}
 
 	if autoscaling {
 		mpBuilder = mpBuilder.Autoscaling(
@@ -421,7 +574,11 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
-	_, err = ocmClient.CreateMachinePool(cluster.ID(), machinePool)
+	_, err = ocmClient.CreateMachinePoolWithAWS(cluster.ID(), machinePool, &ocm.MachinePoolAWS{
+		Tags:    tagsList,
+		Spot:    spot,
+		Subnets: subnets,
+	})
 	if err != nil {
 		reporter.Errorf("Failed to add machine pool to cluster '%s': %v", clusterKey, err)
 		os.Exit(1)
@@ -431,6 +588,29 @@ func run(cmd *cobra.Command, _ []string) {
 	reporter.Infof("To view all machine pools, run 'rosa list machinepools -c %s'", clusterKey)
 }
 
-func Split(r rune) bool {
-	return r == '=' || r == ':'
+// checkReplicas checks that the replicas can be evenly spread across the availability zones of the
+// machine pool.
+func checkReplicas(reporter *rprtr.Object, replicas int, zones int) {
+	if replicas%zones != 0 {
+		reporter.Errorf("Machine pools in %d availability zones require that the replicas be a multiple of %d",
+			zones, zones)
+		os.Exit(1)
+	}
+}
+
+// parseSpot returns the spot settings for the given max price, which is either a number of US
+// dollars or 'on-demand' to pay at most the on-demand price of the instance type.
+func parseSpot(maxPrice string) (*ocm.MachinePoolSpot, error) {
+	maxPrice = strings.TrimSpace(maxPrice)
+	if maxPrice == "" || maxPrice == "on-demand" {
+		return &ocm.MachinePoolSpot{}, nil
+	}
+	price, err := strconv.ParseFloat(maxPrice, 64)
+	if err != nil || price <= 0 {
+		return nil, fmt.Errorf("Expected a positive number or 'on-demand' as spot max price, "+
+			"but got '%s'", maxPrice)
+	}
+	return &ocm.MachinePoolSpot{
+		MaxPrice: &price,
+	}, nil
 }
@@ -0,0 +1,161 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// getZones returns the availability zones and the subnets that the machine pool is pinned to.
+// Both are empty when the machine pool is spread across all the availability zones of the
+// cluster. The subnets are only set when the user chooses them, and their zones are computed.
+func getZones(cmd *cobra.Command, reporter *rprtr.Object, logger *logrus.Logger,
+	cluster *cmv1.Cluster) (zones []string, subnets []string) {
+	var err error
+	clusterZones := cluster.Nodes().AvailabilityZones()
+	zones = trimList(args.availabilityZones)
+	subnets = trimList(args.subnets)
+
+	if len(zones) > 0 && len(subnets) > 0 {
+		reporter.Errorf("Availability zones and subnets can't be set at the same time")
+		os.Exit(1)
+	}
+
+	if len(subnets) > 0 {
+		clusterSubnets := cluster.AWS().SubnetIDs()
+		if len(clusterSubnets) == 0 {
+			reporter.Errorf("Subnets can only be set for clusters installed into existing subnets, " +
+				"use '--availability-zone' instead")
+			os.Exit(1)
+		}
+		for _, subnet := range subnets {
+			if !contains(clusterSubnets, subnet) {
+				reporter.Errorf("Subnet '%s' isn't one of the subnets of cluster '%s': %s",
+					subnet, cluster.Name(), strings.Join(clusterSubnets, ", "))
+				os.Exit(1)
+			}
+		}
+		layout, err := clusterAWSClient(reporter, logger, cluster).GetSubnetLayout(subnets)
+		if err != nil {
+			reporter.Errorf("Failed to get subnets: %v", err)
+			os.Exit(1)
+		}
+		privateZones := layout.PrivateSubnetZones()
+		for _, subnet := range subnets {
+			zone, ok := privateZones[subnet]
+			if !ok {
+				reporter.Errorf("Subnet '%s' is a public subnet, machine pools can only use private subnets",
+					subnet)
+				os.Exit(1)
+			}
+			if contains(zones, zone) {
+				reporter.Errorf("Machine pools can only use one subnet in each availability zone, "+
+					"but more than one subnet is in '%s'", zone)
+				os.Exit(1)
+			}
+			zones = append(zones, zone)
+		}
+		return zones, subnets
+	}
+
+	if len(zones) == 0 && cluster.MultiAZ() && interactive.Enabled() {
+		zones, err = interactive.GetMultipleOptions(interactive.Input{
+			Question: "Availability zones",
+			Help:     cmd.Flags().Lookup("availability-zone").Usage,
+			Options:  clusterZones,
+			Default:  clusterZones,
+			Required: true,
+		})
+		if err != nil {
+			reporter.Errorf("Expected valid availability zones: %s", err)
+			os.Exit(1)
+		}
+	}
+	selected := []string{}
+	for _, zone := range zones {
+		if !contains(clusterZones, zone) {
+			reporter.Errorf("Availability zone '%s' isn't one of the availability zones of cluster '%s': %s",
+				zone, cluster.Name(), strings.Join(clusterZones, ", "))
+			os.Exit(1)
+		}
+		if contains(selected, zone) {
+			reporter.Errorf("Availability zone '%s' is given more than once", zone)
+			os.Exit(1)
+		}
+		selected = append(selected, zone)
+	}
+
+	// Choosing all the zones of the cluster is the same as not pinning the machine pool:
+	if len(zones) == len(clusterZones) {
+		return nil, nil
+	}
+	return zones, nil
+}
+
+// zoneCount returns the number of availability zones that the machine pool is spread across.
+func zoneCount(cluster *cmv1.Cluster, zones []string) int {
+	if len(zones) > 0 {
+		return len(zones)
+	}
+	if cluster.MultiAZ() {
+		return 3
+	}
+	return 1
+}
+
+// clusterAWSClient creates an AWS client for the region of the cluster, which may not be the
+// default region of the user.
+func clusterAWSClient(reporter *rprtr.Object, logger *logrus.Logger, cluster *cmv1.Cluster) aws.Client {
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Region(cluster.Region().ID()).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create AWS client: %v", err)
+		os.Exit(1)
+	}
+	return awsClient
+}
+
+func trimList(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,180 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package network
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/logging"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	stackName         string
+	multiAZ           bool
+	privateLink       bool
+	availabilityZones []string
+	vpcCIDR           string
+	tags              []string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "network",
+	Short: "Create a VPC for clusters that use existing subnets",
+	Long: "Create, using a CloudFormation stack, a VPC with DNS hostnames enabled and private and public " +
+		"subnets, NAT gateways and route tables in one or three availability zones, ready to be used " +
+		"with 'rosa create cluster --subnet-ids'.",
+	Example: `  # Create a network for single AZ clusters
+  rosa create network --name=mynetwork
+
+  # Create a network for multi AZ clusters in the us-east-2 region
+  rosa create network --name=mynetwork --multi-az --region=us-east-2
+
+  # Create a network with private subnets only, for PrivateLink clusters
+  rosa create network --name=mynetwork --multi-az --private-link`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.stackName,
+		"name",
+		"",
+		"Name of the CloudFormation stack that creates the network, also used to name its resources.",
+	)
+	Cmd.MarkFlagRequired("name")
+
+	arguments.AddRegionFlag(flags)
+
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Create subnets in three availability zones, as needed by multi AZ clusters.",
+	)
+	flags.BoolVar(
+		&args.privateLink,
+		"private-link",
+		false,
+		"Create only private subnets, without internet or NAT gateways, as used by PrivateLink "+
+			"clusters. Egress has to be provided by other means, for example a transit gateway.",
+	)
+	flags.StringSliceVar(
+		&args.availabilityZones,
+		"availability-zones",
+		nil,
+		"Availability zones of the subnets. Defaults to the first zones of the region.",
+	)
+	flags.StringVar(
+		&args.vpcCIDR,
+		"vpc-cidr",
+		"10.0.0.0/16",
+		"CIDR block of the VPC, between /16 and /24. It must contain the machine CIDR of the clusters.",
+	)
+	flags.StringSliceVar(
+		&args.tags,
+		"tags",
+		nil,
+		"Apply user defined tags to the network resources. "+
+			"Tags are comma separated, for example: --tags=foo:bar,bar:baz",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	tagsList, err := awstags.Parse(args.tags)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	err = awstags.Validate(tagsList)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	zoneCount := 1
+	if args.multiAZ {
+		zoneCount = 3
+	}
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+
+	availabilityZones := args.availabilityZones
+	if len(availabilityZones) == 0 {
+		regionZones, err := awsClient.GetAvailabilityZones()
+		if err != nil {
+			reporter.Errorf("Failed to get availability zones of region '%s': %v", awsClient.GetRegion(), err)
+			os.Exit(1)
+		}
+		if len(regionZones) < zoneCount {
+			reporter.Errorf("Region '%s' has only %d availability zones, %d are needed",
+				awsClient.GetRegion(), len(regionZones), zoneCount)
+			os.Exit(1)
+		}
+		availabilityZones = regionZones[:zoneCount]
+	}
+	if len(availabilityZones) != zoneCount {
+		reporter.Errorf("Expected %d availability zones, got %d", zoneCount, len(availabilityZones))
+		os.Exit(1)
+	}
+
+	config := aws.NetworkConfig{
+		VPCCIDR:           args.vpcCIDR,
+		AvailabilityZones: availabilityZones,
+		PrivateOnly:       args.privateLink,
+		Tags:              tagsList,
+	}
+	_, err = aws.NetworkStackParameters(config)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	reporter.Infof("Creating network '%s' in region '%s', availability zones %s. This may take a few minutes",
+		args.stackName, awsClient.GetRegion(), strings.Join(availabilityZones, ", "))
+	network, err := awsClient.CreateNetworkStack(args.stackName, config)
+	if err != nil {
+		reporter.Errorf("Failed to create network '%s': %v", args.stackName, err)
+		os.Exit(1)
+	}
+	reporter.Infof("Created VPC '%s'", network.VPCID)
+
+	flagsLine := fmt.Sprintf("--region=%s --subnet-ids=%s", awsClient.GetRegion(),
+		strings.Join(network.SubnetIDs(), ","))
+	if args.multiAZ {
+		flagsLine += " --multi-az"
+	}
+	if args.privateLink {
+		flagsLine += " --private-link"
+	}
+	reporter.Infof("To create a cluster in this network, add the following flags to 'rosa create cluster', "+
+		"with a machine CIDR contained in '%s':\n\n"+
+		"  %s\n", args.vpcCIDR, flagsLine)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcprovider
+
+import (
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	clusterKey      string
+	oidcEndpointURL string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-provider",
+	Aliases: []string{"oidcprovider"},
+	Short:   "Create OIDC provider for an STS cluster",
+	Long: "Create or update the IAM OpenID Connect provider that allows the operators of an STS " +
+		"cluster to assume their IAM roles.",
+	Example: `  # Create the OIDC provider of the cluster named "mycluster"
+  rosa create oidc-provider --cluster=mycluster
+
+  # Create the OIDC provider of an issuer
+  rosa create oidc-provider --oidc-endpoint-url=https://oidc.example.com/mycluster`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the STS cluster whose OIDC provider will be created.",
+	)
+	flags.StringVar(
+		&args.oidcEndpointURL,
+		"oidc-endpoint-url",
+		"",
+		"URL of the OIDC issuer, used instead of the one of the cluster.",
+	)
+}
+
+// Timeout of the requests sent to the OIDC issuer:
+const fetchTimeout = 30 * time.Second
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+
+	oidcEndpointURL := GetOIDCEndpointURL(reporter, logger, awsClient, args.clusterKey, args.oidcEndpointURL)
+
+	reporter.Debugf("Fetching OIDC configuration of '%s'", oidcEndpointURL)
+	config, err := aws.FetchOIDCProviderConfig(&http.Client{Timeout: fetchTimeout}, oidcEndpointURL)
+	if err != nil {
+		reporter.Errorf("Failed to fetch OIDC configuration: %v", err)
+		os.Exit(1)
+	}
+	reporter.Debugf("Thumbprint of '%s' is '%s'", config.JWKSURL, config.Thumbprint)
+
+	providerARN, created, err := awsClient.EnsureOpenIDConnectProvider(config.IssuerURL, config.Thumbprint,
+		aws.OIDCClientIDs)
+	if err != nil {
+		reporter.Errorf("Failed to create OIDC provider for '%s': %v", config.IssuerURL, err)
+		os.Exit(1)
+	}
+	if created {
+		reporter.Infof("Created OIDC provider with ARN '%s'", providerARN)
+	} else {
+		reporter.Infof("OIDC provider with ARN '%s' is up to date", providerARN)
+	}
+}
+
+// GetOIDCEndpointURL returns the given OIDC endpoint URL or, if it is empty, the one of the
+// given cluster. It exits if neither of them is available.
+func GetOIDCEndpointURL(reporter *rprtr.Object, logger *logrus.Logger, awsClient aws.Client,
+	clusterKey string, oidcEndpointURL string) string {
+	if oidcEndpointURL != "" {
+		return oidcEndpointURL
+	}
+	if clusterKey == "" {
+		reporter.Errorf("Either '--cluster' or '--oidc-endpoint-url' must be given")
+		os.Exit(1)
+	}
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	if cluster.AWS().STS().OIDCEndpointURL() == "" {
+		reporter.Errorf("Cluster '%s' doesn't have an OIDC endpoint, only STS clusters have one", clusterKey)
+		os.Exit(1)
+	}
+	return cluster.AWS().STS().OIDCEndpointURL()
+}
@@ -0,0 +1,223 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package operatorroles
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	prefix              string
+	oidcEndpointURL     string
+	manifestsDir        string
+	version             string
+	permissionsBoundary string
+	tags                []string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "operator-roles",
+	Aliases: []string{"operatorroles"},
+	Short:   "Create IAM roles for the operators of STS clusters",
+	Long: "Create one IAM role per CredentialsRequest manifest, trusting the service accounts of the " +
+		"operator through the OIDC provider of the cluster and granting the permissions requested " +
+		"in the manifest.",
+	Example: `  # Create the operator roles known for OpenShift 4.7
+  rosa create operator-roles --prefix=mycluster --version=4.7.2 \
+    --oidc-endpoint-url=https://oidc.example.com/mycluster
+
+  # Create the operator roles from manifests extracted from the release image
+  oc adm release extract quay.io/openshift-release-dev/ocp-release:4.7.2-x86_64 \
+    --credentials-requests --cloud=aws --to=manifests
+  rosa create operator-roles --prefix=mycluster --manifests-dir=manifests \
+    --oidc-endpoint-url=https://oidc.example.com/mycluster`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.prefix,
+		"prefix",
+		"",
+		"User-defined prefix for the names of the roles and of their policies. Operator roles trust "+
+			"the OIDC provider of a single cluster, so each cluster needs its own prefix, for example "+
+			"the name of the cluster.",
+	)
+	Cmd.MarkFlagRequired("prefix")
+	flags.StringVar(
+		&args.oidcEndpointURL,
+		"oidc-endpoint-url",
+		"",
+		"URL of the OIDC provider that issues the tokens of the service accounts of the cluster.",
+	)
+	Cmd.MarkFlagRequired("oidc-endpoint-url")
+	flags.StringVar(
+		&args.manifestsDir,
+		"manifests-dir",
+		"",
+		"Directory containing the CredentialsRequest manifests of the release, as extracted by "+
+			"'oc adm release extract --credentials-requests --cloud=aws'.",
+	)
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version of OpenShift whose known CredentialsRequests will be used, for example \"4.7.2\". "+
+			"Ignored if '--manifests-dir' is given.",
+	)
+	flags.StringVar(
+		&args.permissionsBoundary,
+		"permissions-boundary",
+		"",
+		"The ARN of the policy that is used to set the permissions boundary of the roles.",
+	)
+	flags.StringSliceVar(
+		&args.tags,
+		"tags",
+		nil,
+		"Apply user defined tags to the roles. "+
+			"Tags are comma separated, for example: --tags=foo:bar,bar:baz",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	err := aws.ValidatePrefix(args.prefix)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if args.permissionsBoundary != "" {
+		err = aws.ValidatePolicyARN(args.permissionsBoundary)
+		if err != nil {
+			reporter.Errorf("Expected a valid permissions boundary: %s", err)
+			os.Exit(1)
+		}
+	}
+
+	oidcEndpointURL, err := url.ParseRequestURI(args.oidcEndpointURL)
+	if err != nil || oidcEndpointURL.Scheme != "https" || oidcEndpointURL.Host == "" {
+		reporter.Errorf("Expected a valid HTTPS OIDC endpoint URL, got '%s'", args.oidcEndpointURL)
+		os.Exit(1)
+	}
+
+	tagsList, err := awstags.Parse(args.tags)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	err = awstags.Validate(tagsList)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	var credRequests []*aws.CredentialsRequest
+	switch {
+	case args.manifestsDir != "":
+		credRequests, err = aws.ReadCredentialsRequests(args.manifestsDir)
+	case args.version != "":
+		credRequests, err = aws.GetCredentialsRequests(args.version)
+	default:
+		reporter.Errorf("Either '--manifests-dir' or '--version' must be given")
+		os.Exit(1)
+	}
+	if err != nil {
+		reporter.Errorf("Failed to read CredentialsRequests: %v", err)
+		os.Exit(1)
+	}
+	if len(credRequests) == 0 {
+		reporter.Errorf("No AWS CredentialsRequests were found")
+		os.Exit(1)
+	}
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Unable to get IAM credentials: %v", err)
+		os.Exit(1)
+	}
+
+	// Roles that already exist must trust the OIDC provider of this cluster, otherwise they belong
+	// to another cluster and updating their trust policy would break it:
+	providerARN := aws.OIDCProviderARN(awsCreator, args.oidcEndpointURL)
+	for _, credRequest := range credRequests {
+		roleName := credRequest.RoleName(args.prefix)
+		role, err := awsClient.GetRole(roleName)
+		if err != nil {
+			reporter.Errorf("Failed to get role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		if role == nil {
+			continue
+		}
+		err = aws.CheckOperatorRoleProvider(role, providerARN)
+		if err != nil {
+			reporter.Errorf("%v. Use a different '--prefix' for this cluster", err)
+			os.Exit(1)
+		}
+	}
+
+	operatorIAMRoles := []ocm.OperatorIAMRole{}
+	for _, credRequest := range credRequests {
+		roleName := credRequest.RoleName(args.prefix)
+		trustPolicy, err := credRequest.OperatorRoleTrustPolicy(awsCreator, args.oidcEndpointURL)
+		if err != nil {
+			reporter.Errorf("Failed to build trust policy of role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		permissionPolicy, err := credRequest.PermissionPolicy()
+		if err != nil {
+			reporter.Errorf("Failed to build permission policy of role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+
+		roleARN, err := aws.EnsureRole(reporter, awsClient, roleName, roleName+"-Policy",
+			trustPolicy, permissionPolicy, args.permissionsBoundary, tagsList)
+		if err != nil {
+			reporter.Errorf("Failed to create role '%s': %v", roleName, err)
+			os.Exit(1)
+		}
+		operatorIAMRoles = append(operatorIAMRoles, ocm.OperatorIAMRole{
+			Name:      credRequest.Spec.SecretRef.Name,
+			Namespace: credRequest.Spec.SecretRef.Namespace,
+			RoleARN:   roleARN,
+		})
+	}
+
+	flagsLine := ""
+	for _, role := range operatorIAMRoles {
+		flagsLine += fmt.Sprintf(" --operator-iam-roles %s,%s,%s", role.Name, role.Namespace, role.RoleARN)
+	}
+	reporter.Infof("To use these roles, add the following flags to 'rosa create cluster':\n\n"+
+		"  %s\n", flagsLine)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	region             string
+	multiAZ            bool
+	version            string
+	channelGroup       string
+	etcdEncryption     bool
+	tags               []string
+	computeMachineType string
+	computeNodes       int
+	autoscalingEnabled bool
+	minReplicas        int
+	maxReplicas        int
+}
+
+var Cmd = &cobra.Command{
+	Use:   "profile NAME",
+	Short: "Create cluster profile",
+	Long: "Save a named set of defaults for 'rosa create cluster'. The profile is applied with " +
+		"'rosa create cluster --cluster-profile NAME', and flags given explicitly take precedence over it.",
+	Example: `  # Create a profile for production clusters
+  rosa create profile prod --multi-az --compute-machine-type=m5.2xlarge --compute-nodes=6
+
+  # Create a cluster using the profile
+  rosa create cluster --cluster-name=mycluster --cluster-profile=prod`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 1 {
+			return fmt.Errorf("Expected exactly one command line argument containing the name of the profile")
+		}
+		return nil
+	},
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVar(
+		&args.region,
+		"region",
+		"",
+		"AWS region where the clusters will be created.",
+	)
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Deploy the clusters to multiple availability zones in the region.",
+	)
+	flags.StringVar(
+		&args.version,
+		"version",
+		"",
+		"Version of OpenShift that will be used to install the clusters, for example \"4.3.10\".",
+	)
+	flags.StringVar(
+		&args.channelGroup,
+		"channel-group",
+		"",
+		"Channel group is the name of the group where this image belongs, for example \"stable\" or \"fast\".",
+	)
+	flags.BoolVar(
+		&args.etcdEncryption,
+		"etcd-encryption",
+		false,
+		clusterspec.EtcdEncryptionUsage,
+	)
+	flags.StringSliceVar(
+		&args.tags,
+		"tags",
+		nil,
+		"Apply user defined tags to all resources created by ROSA in AWS. "+
+			"Tags are comma separated, for example: --tags=foo:bar,bar:baz",
+	)
+	flags.StringVar(
+		&args.computeMachineType,
+		"compute-machine-type",
+		"",
+		"Instance type for the compute nodes.",
+	)
+	flags.IntVar(
+		&args.computeNodes,
+		"compute-nodes",
+		0,
+		"Number of worker nodes to provision.",
+	)
+	flags.BoolVar(
+		&args.autoscalingEnabled,
+		"enable-autoscaling",
+		false,
+		"Enable autoscaling of compute nodes.",
+	)
+	flags.IntVar(
+		&args.minReplicas,
+		"min-replicas",
+		0,
+		"Minimum number of compute nodes.",
+	)
+	flags.IntVar(
+		&args.maxReplicas,
+		"max-replicas",
+		0,
+		"Maximum number of compute nodes.",
+	)
+}
+
+func run(cmd *cobra.Command, argv []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	name := argv[0]
+	err := clusterspec.ValidateProfileName(name)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	profile := &clusterspec.File{
+		Version:          clusterspec.CurrentVersion,
+		Region:           args.region,
+		OpenShiftVersion: args.version,
+		ChannelGroup:     args.channelGroup,
+	}
+	if cmd.Flags().Changed("multi-az") {
+		profile.MultiAZ = &args.multiAZ
+	}
+	if cmd.Flags().Changed("etcd-encryption") {
+		profile.EtcdEncryption = &args.etcdEncryption
+	}
+	tags, err := awstags.Parse(args.tags)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	err = awstags.Validate(tags)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if len(tags) > 0 {
+		profile.Tags = tags
+	}
+	if args.computeMachineType != "" || args.computeNodes != 0 || args.autoscalingEnabled {
+		profile.Compute = &clusterspec.Compute{
+			MachineType: args.computeMachineType,
+			Nodes:       args.computeNodes,
+		}
+	}
+	if args.autoscalingEnabled {
+		if args.computeNodes != 0 {
+			reporter.Errorf("Compute-nodes can't be set when autoscaling is enabled")
+			os.Exit(1)
+		}
+		profile.Compute.Autoscaling = &clusterspec.Autoscaling{
+			MinReplicas: args.minReplicas,
+			MaxReplicas: args.maxReplicas,
+		}
+	} else if cmd.Flags().Changed("min-replicas") || cmd.Flags().Changed("max-replicas") {
+		reporter.Errorf("Autoscaling must be enabled in order to set min and max replicas")
+		os.Exit(1)
+	}
+	if len(profile.FlagValues()) == 0 {
+		reporter.Errorf("Expected at least one setting for profile '%s'", name)
+		os.Exit(1)
+	}
+
+	path, err := ocm.ProfilesLocation()
+	if err != nil {
+		reporter.Errorf("Failed to get location of profiles: %v", err)
+		os.Exit(1)
+	}
+	profiles, err := clusterspec.LoadProfiles(path)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	if _, ok := profiles[name]; ok && !confirm.Confirm("replace existing profile '%s'", name) {
+		os.Exit(0)
+	}
+	profiles[name] = profile
+	err = clusterspec.SaveProfiles(path, profiles)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	reporter.Infof("Saved profile '%s'. To use it, run 'rosa create cluster --cluster-profile %s'", name, name)
+}
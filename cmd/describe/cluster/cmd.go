@@ -19,13 +19,17 @@ package cluster
 import (
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/aws/aws-sdk-go/aws/arn"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift/rosa/pkg/arguments"
 	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/cost"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
@@ -41,15 +45,35 @@ const (
 )
 
 var args struct {
-	clusterKey string
+	clusterKey   string
+	export       string
+	estimateCost bool
+	pricingFile  string
 }
 
+const exportFlags = "flags"
+
+var exportFormats = []string{exportFlags, clusterspec.FormatYAML, clusterspec.FormatJSON, clusterspec.FormatTerraform}
+
 var Cmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Show details of a cluster",
 	Long:  "Show details of a cluster",
 	Example: `  # Describe a cluster named "mycluster"
-  rosa describe cluster --cluster=mycluster`,
+  rosa describe cluster --cluster=mycluster
+
+  # Print the command that creates a copy of the cluster named "mycluster"
+  rosa describe cluster --cluster=mycluster --export
+
+  # Save the configuration of the cluster named "mycluster" as a spec file
+  rosa describe cluster --cluster=mycluster --export=yaml > mycluster.yaml
+
+  # Export the cluster named "mycluster" and its machine pools, identity providers, ingresses
+  # and add-ons as Terraform resources
+  rosa describe cluster --cluster=mycluster --export=terraform > mycluster.tf
+
+  # Show the estimated monthly AWS cost of the cluster named "mycluster"
+  rosa describe cluster --cluster=mycluster --estimate-cost`,
 	Run: run,
 }
 
@@ -67,6 +91,33 @@ func init() {
 		"Name or ID of the cluster to describe.",
 	)
 	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.export,
+		"export",
+		"",
+		fmt.Sprintf("Export the configuration of the cluster as the input accepted by 'rosa create cluster', "+
+			"or as Terraform resources. Allowed formats are %s", exportFormats),
+	)
+	flags.Lookup("export").NoOptDefVal = exportFlags
+
+	flags.BoolVar(
+		&args.estimateCost,
+		"estimate-cost",
+		false,
+		"Show a rough estimate of the monthly AWS cost of the cluster, including its machine pools.",
+	)
+	AddPricingFileFlag(flags, &args.pricingFile)
+}
+
+// AddPricingFileFlag adds the flag that selects the pricing table used by the cost estimates.
+func AddPricingFileFlag(flags *pflag.FlagSet, value *string) {
+	flags.StringVar(
+		value,
+		"pricing-file",
+		"",
+		"Path of a JSON pricing table to use for the cost estimate instead of the built-in one.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -137,6 +188,16 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	if args.export != "" {
+		str, err := exportCluster(ocmClient, cluster, args.export)
+		if err != nil {
+			reporter.Errorf("Failed to export cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		fmt.Print(str)
+		os.Exit(0)
+	}
+
 	var str string
 	if output.HasFlag() {
 		err = output.Print(cluster)
@@ -175,6 +236,15 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 	}
 
+	switch cluster.State() {
+	case cmv1.ClusterStatePoweringDown:
+		phase = "(Stopping nodes for hibernation)"
+	case cmv1.ClusterStateHibernating:
+		phase = fmt.Sprintf("(Run 'rosa resume cluster -c %s' to start it)", clusterKey)
+	case cmv1.ClusterStateResuming:
+		phase = "(Starting nodes after hibernation)"
+	}
+
 	clusterName := cluster.DisplayName()
 	if clusterName == "" {
 		clusterName = cluster.Name()
@@ -280,6 +350,37 @@ func run(cmd *cobra.Command, argv []string) {
 		cluster.Network().HostPrefix(),
 	)
 
+	// Only clusters installed into existing subnets can use a cluster-wide proxy:
+	if len(cluster.AWS().SubnetIDs()) > 0 {
+		proxy, err := ocmClient.GetClusterProxy(cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get proxy of cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		if !proxy.Empty() {
+			str = fmt.Sprintf("%sProxy:\n", str)
+			if proxy.HTTPProxy != "" {
+				str = fmt.Sprintf("%s"+
+					" - HTTP Proxy:              %s\n", str,
+					proxy.HTTPProxy)
+			}
+			if proxy.HTTPSProxy != "" {
+				str = fmt.Sprintf("%s"+
+					" - HTTPS Proxy:             %s\n", str,
+					proxy.HTTPSProxy)
+			}
+			if proxy.NoProxy != "" {
+				str = fmt.Sprintf("%s"+
+					" - No Proxy:                %s\n", str,
+					proxy.NoProxy)
+			}
+			if proxy.AdditionalTrustBundle {
+				str = fmt.Sprintf("%s"+
+					" - Additional Trust Bundle: REDACTED\n", str)
+			}
+		}
+	}
+
 	if cluster.AWS().STS().RoleARN() != "" {
 		str = fmt.Sprintf("%s"+
 			"STS Role ARN:               %s\n", str,
@@ -318,6 +419,20 @@ func run(cmd *cobra.Command, argv []string) {
 		}
 	}
 
+	if len(cluster.AWS().Tags()) > 0 {
+		str = fmt.Sprintf("%sTags:\n", str)
+		keys := make([]string, 0, len(cluster.AWS().Tags()))
+		for key := range cluster.AWS().Tags() {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			str = fmt.Sprintf("%s"+
+				" - %s: %s\n", str,
+				key, cluster.AWS().Tags()[key])
+		}
+	}
+
 	str = fmt.Sprintf("%s"+
 		"State:                      %s %s\n"+
 		"Private:                    %s\n"+
@@ -357,6 +472,108 @@ func run(cmd *cobra.Command, argv []string) {
 	// Print short cluster description:
 	fmt.Print(str)
 	fmt.Println()
+
+	if args.estimateCost {
+		machinePools, err := ocmClient.GetMachinePools(cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+		PrintCostEstimate(reporter, args.pricingFile, costCluster(cluster, machinePools))
+	}
+}
+
+// PrintCostEstimate prints the line items of the estimated monthly cost of the cluster.
+func PrintCostEstimate(reporter *rprtr.Object, pricingFile string, cluster *cost.Cluster) {
+	pricing, err := cost.LoadPricing(pricingFile)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	estimate, err := cost.EstimateCluster(pricing, cluster)
+	if err != nil {
+		reporter.Errorf("Failed to estimate cost: %v", err)
+		os.Exit(1)
+	}
+	reporter.Infof("Estimated monthly AWS cost, on demand prices without data transfer nor service fees:")
+	estimate.Print(os.Stdout)
+	fmt.Println()
+}
+
+// costCluster returns the resources of an existing cluster that are used to estimate its cost.
+func costCluster(cluster *cmv1.Cluster, machinePools []*cmv1.MachinePool) *cost.Cluster {
+	result := &cost.Cluster{
+		Region:      cluster.Region().ID(),
+		MultiAZ:     cluster.MultiAZ(),
+		Private:     cluster.API().Listening() == cmv1.ListeningMethodInternal,
+		ExistingVPC: len(cluster.AWS().SubnetIDs()) > 0,
+	}
+	defaultPool := cost.ComputePool{
+		Name:         "default",
+		InstanceType: cluster.Nodes().ComputeMachineType().ID(),
+		MinNodes:     cluster.Nodes().Compute(),
+		MaxNodes:     cluster.Nodes().Compute(),
+	}
+	if cluster.Nodes().AutoscaleCompute() != nil {
+		defaultPool.MinNodes = cluster.Nodes().AutoscaleCompute().MinReplicas()
+		defaultPool.MaxNodes = cluster.Nodes().AutoscaleCompute().MaxReplicas()
+	}
+	result.ComputePools = append(result.ComputePools, defaultPool)
+	for _, machinePool := range machinePools {
+		pool := cost.ComputePool{
+			Name:         machinePool.ID(),
+			InstanceType: machinePool.InstanceType(),
+			MinNodes:     machinePool.Replicas(),
+			MaxNodes:     machinePool.Replicas(),
+		}
+		if machinePool.Autoscaling() != nil {
+			pool.MinNodes = machinePool.Autoscaling().MinReplicas()
+			pool.MaxNodes = machinePool.Autoscaling().MaxReplicas()
+		}
+		result.ComputePools = append(result.ComputePools, pool)
+	}
+	return result
+}
+
+// exportCluster returns the configuration of the cluster in the format accepted by 'rosa create cluster',
+// either as a command line or as a spec file, or as Terraform resources.
+func exportCluster(ocmClient *ocm.Client, cluster *cmv1.Cluster, format string) (string, error) {
+	spec, err := ocmClient.GetClusterSpec(cluster)
+	if err != nil {
+		return "", fmt.Errorf("Failed to get cluster spec: %v", err)
+	}
+	switch format {
+	case exportFlags:
+		return clusterspec.BuildCommand(spec) + "\n", nil
+	case clusterspec.FormatYAML, clusterspec.FormatJSON:
+		data, err := clusterspec.FromSpec(spec).Marshal(format)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	case clusterspec.FormatTerraform:
+		export := &clusterspec.TerraformExport{Spec: spec}
+		var err error
+		export.MachinePools, err = ocmClient.GetMachinePools(cluster.ID())
+		if err != nil {
+			return "", fmt.Errorf("Failed to get machine pools: %v", err)
+		}
+		export.IdentityProviders, err = ocmClient.GetIdentityProviders(cluster.ID())
+		if err != nil {
+			return "", fmt.Errorf("Failed to get identity providers: %v", err)
+		}
+		export.Ingresses, err = ocmClient.GetIngresses(cluster.ID())
+		if err != nil {
+			return "", fmt.Errorf("Failed to get ingresses: %v", err)
+		}
+		export.AddOns, err = ocmClient.GetAddOnInstallations(cluster.ID())
+		if err != nil {
+			return "", fmt.Errorf("Failed to get add-ons: %v", err)
+		}
+		return export.Render(), nil
+	default:
+		return "", fmt.Errorf("Unknown export format '%s'. Valid formats are %s", format, exportFormats)
+	}
 }
 
 func getDetailsLink(environment string) string {
@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var Cmd = &cobra.Command{
+	Use:   "profile NAME",
+	Short: "Show details of a cluster profile",
+	Long:  "Show the settings of a cluster profile saved with 'rosa create profile'.",
+	Example: `  # Describe the profile named "prod"
+  rosa describe profile prod`,
+	Run: run,
+	Args: func(_ *cobra.Command, argv []string) error {
+		if len(argv) != 1 {
+			return fmt.Errorf("Expected exactly one command line argument containing the name of the profile")
+		}
+		return nil
+	},
+}
+
+func run(_ *cobra.Command, argv []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	name := argv[0]
+
+	path, err := ocm.ProfilesLocation()
+	if err != nil {
+		reporter.Errorf("Failed to get location of profiles: %v", err)
+		os.Exit(1)
+	}
+	profiles, err := clusterspec.LoadProfiles(path)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	profile, ok := profiles[name]
+	if !ok {
+		reporter.Errorf("Profile '%s' doesn't exist. Try running 'rosa list profiles' to see all profiles.", name)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Name: %s\n\n", name)
+
+	// The settings are displayed as the flags of 'rosa create cluster' that they set:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "FLAG\tVALUE\n")
+	for _, value := range profile.FlagValues() {
+		fmt.Fprintf(writer, "--%s\t%s\n", value.Name, value.Value)
+	}
+	writer.Flush()
+}
@@ -18,6 +18,7 @@ package cluster
 
 import (
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -35,6 +36,10 @@ var args struct {
 	// Watch logs during cluster uninstallation
 	watch      bool
 	clusterKey string
+
+	// Wait for the cluster to be uninstalled
+	wait    bool
+	timeout time.Duration
 }
 
 var Cmd = &cobra.Command{
@@ -42,7 +47,10 @@ var Cmd = &cobra.Command{
 	Short: "Delete cluster",
 	Long:  "Delete cluster.",
 	Example: `  # Delete a cluster named "mycluster"
-  rosa delete cluster --cluster=mycluster`,
+  rosa delete cluster --cluster=mycluster
+
+  # Delete a cluster and wait until it is gone
+  rosa delete cluster --cluster=mycluster --wait`,
 	Run: run,
 }
 
@@ -66,6 +74,21 @@ func init() {
 		false,
 		"Watch cluster uninstallation logs.",
 	)
+
+	flags.BoolVar(
+		&args.wait,
+		"wait",
+		false,
+		"Block until the cluster is deleted, and then delete the OIDC provider of STS clusters. "+
+			"Exits with 2 if the uninstallation fails and with 3 if it doesn't finish before the timeout.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		time.Hour,
+		"Maximum time to wait for the cluster to be deleted, used with '--wait'.",
+	)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -84,6 +107,11 @@ func run(cmd *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	if args.timeout <= 0 {
+		reporter.Errorf("Expected a positive timeout, got '%s'", args.timeout)
+		os.Exit(1)
+	}
+
 	// Create the AWS client:
 	awsClient, err := aws.NewClient().
 		Region(arguments.GetRegion()).
@@ -120,19 +148,60 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 
 	reporter.Debugf("Deleting cluster '%s'", clusterKey)
-	_, err = ocmClient.DeleteCluster(clusterKey, awsCreator)
+	cluster, err := ocmClient.DeleteCluster(clusterKey, awsCreator)
 	if err != nil {
 		reporter.Errorf("Failed to delete cluster '%s': %v", clusterKey, err)
 		os.Exit(1)
 	}
 	reporter.Infof("Cluster '%s' will start uninstalling now", clusterKey)
 
+	oidcEndpointURL := cluster.AWS().STS().OIDCEndpointURL()
+	waitStart := time.Now()
 	if args.watch {
 		uninstallLogs.Cmd.Run(uninstallLogs.Cmd, []string{clusterKey})
-	} else {
+	}
+	if args.wait {
+		result, err := ocmClient.WaitForClusterAndReport(reporter, cluster, ocm.WaitUninstall,
+			args.timeout-time.Since(waitStart))
+		if err != nil {
+			reporter.Errorf("Failed to get state of cluster '%s': %v", cluster.Name(), err)
+			os.Exit(1)
+		}
+		if result != ocm.WaitSucceeded {
+			os.Exit(result.ExitCode())
+		}
+		// The OIDC provider is only deleted once the cluster is known to be gone, as the
+		// operators need it to clean up the AWS resources during the uninstallation:
+		if oidcEndpointURL != "" {
+			deleteOIDCProvider(reporter, awsClient, oidcEndpointURL)
+		}
+		return
+	}
+	if !args.watch {
 		reporter.Infof(
 			"To watch your cluster uninstallation logs, run 'rosa logs uninstall -c %s --watch'",
 			clusterKey,
 		)
 	}
+	if oidcEndpointURL != "" {
+		reporter.Infof(
+			"Once the cluster is uninstalled, delete its OIDC provider running "+
+				"'rosa delete oidc-provider --oidc-endpoint-url %s'",
+			oidcEndpointURL,
+		)
+	}
+}
+
+// deleteOIDCProvider deletes the OIDC provider of an STS cluster that has already been
+// uninstalled. Failures are only reported as warnings, as the cluster is already gone.
+func deleteOIDCProvider(reporter *rprtr.Object, awsClient aws.Client, oidcEndpointURL string) {
+	reporter.Debugf("Deleting OIDC provider '%s'", oidcEndpointURL)
+	deleted, err := awsClient.DeleteOpenIDConnectProvider(oidcEndpointURL)
+	if err != nil {
+		reporter.Warnf("Failed to delete OIDC provider '%s': %v", oidcEndpointURL, err)
+		return
+	}
+	if deleted {
+		reporter.Infof("Deleted OIDC provider '%s'", oidcEndpointURL)
+	}
 }
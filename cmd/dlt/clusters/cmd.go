@@ -0,0 +1,264 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusters
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// clustersPageSize is the size of the pages used to fetch the clusters. GetClusters keeps
+// fetching pages till it has all the clusters, so the selection sees every cluster of the user.
+const clustersPageSize = 100
+
+var args struct {
+	expired        bool
+	expiringWithin time.Duration
+	name           string
+	olderThan      time.Duration
+	concurrency    int
+	dryRun         bool
+}
+
+var Cmd = &cobra.Command{
+	Use:   "clusters",
+	Short: "Delete the clusters that match a selection",
+	Long: "Delete, after a single confirmation, all the clusters selected by expiration, name or " +
+		"age. The selected clusters are always listed before deleting them.",
+	Example: `  # Delete all the clusters that have already expired
+  rosa delete clusters --expired
+
+  # Show the clusters named "ci-..." created more than two days ago, without deleting them
+  rosa delete clusters --name='ci-*' --older-than=48h --dry-run`,
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	arguments.AddRegionFlag(flags)
+
+	flags.BoolVar(
+		&args.expired,
+		"expired",
+		false,
+		"Select the clusters whose expiration time has already passed.",
+	)
+	flags.DurationVar(
+		&args.expiringWithin,
+		"expiring-within",
+		0,
+		"Select the clusters that expire within the given duration, for example 2h.",
+	)
+	flags.StringVar(
+		&args.name,
+		"name",
+		"",
+		"Select the clusters whose name matches the given shell pattern, for example 'ci-*'.",
+	)
+	flags.DurationVar(
+		&args.olderThan,
+		"older-than",
+		0,
+		"Select the clusters created more than the given duration ago, for example 72h.",
+	)
+	flags.IntVar(
+		&args.concurrency,
+		"concurrency",
+		5,
+		"Maximum number of clusters deleted at the same time.",
+	)
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Only list the selected clusters, without deleting them.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	if args.expired && args.expiringWithin != 0 {
+		reporter.Errorf("Only one of expired or expiring-within may be used")
+		os.Exit(1)
+	}
+	if args.expiringWithin < 0 || args.olderThan < 0 {
+		reporter.Errorf("Expected positive durations for expiring-within and older-than")
+		os.Exit(1)
+	}
+	if args.concurrency < 1 {
+		reporter.Errorf("Expected a concurrency of at least 1, got %d", args.concurrency)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	selector := &ocm.ClusterSelector{
+		NamePattern: args.name,
+	}
+	if args.expired || args.expiringWithin > 0 {
+		selector.ExpiringBefore = now.Add(args.expiringWithin)
+	}
+	if args.olderThan > 0 {
+		selector.CreatedBefore = now.Add(-args.olderThan)
+	}
+	if selector.Empty() {
+		reporter.Errorf("At least one of expired, expiring-within, name or older-than is required")
+		os.Exit(1)
+	}
+	err := selector.Validate()
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	clusters, err := ocmClient.GetClusters(awsCreator, clustersPageSize)
+	if err != nil {
+		reporter.Errorf("Failed to get clusters: %v", err)
+		os.Exit(1)
+	}
+	selected := []*cmv1.Cluster{}
+	for _, cluster := range ocm.SelectClusters(clusters, selector) {
+		// Clusters that are already being deleted don't need to be deleted again:
+		if cluster.State() == cmv1.ClusterStateUninstalling {
+			continue
+		}
+		selected = append(selected, cluster)
+	}
+	if len(selected) == 0 {
+		reporter.Infof("No clusters match the selection")
+		os.Exit(0)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tNAME\tSTATE\tCREATED\tEXPIRES\n")
+	for _, cluster := range selected {
+		expires := ""
+		if expiration, ok := cluster.GetExpirationTimestamp(); ok {
+			expires = expiration.Format(time.RFC3339)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n",
+			cluster.ID(),
+			cluster.Name(),
+			cluster.State(),
+			cluster.CreationTimestamp().Format(time.RFC3339),
+			expires,
+		)
+	}
+	writer.Flush()
+
+	if args.dryRun {
+		reporter.Infof("Dry run, %d clusters would be deleted", len(selected))
+		os.Exit(0)
+	}
+
+	if !confirm.Confirm("delete these %d clusters", len(selected)) {
+		os.Exit(0)
+	}
+
+	results := deleteClusters(reporter, ocmClient, awsCreator, selected, args.concurrency)
+
+	failed := 0
+	oidcEndpointURLs := []string{}
+	writer = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ID\tNAME\tRESULT\n")
+	for i, cluster := range selected {
+		result := "uninstalling"
+		if results[i] != nil {
+			result = fmt.Sprintf("failed: %v", results[i])
+			failed++
+		} else if cluster.AWS().STS().OIDCEndpointURL() != "" {
+			oidcEndpointURLs = append(oidcEndpointURLs, cluster.AWS().STS().OIDCEndpointURL())
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", cluster.ID(), cluster.Name(), result)
+	}
+	writer.Flush()
+
+	for _, oidcEndpointURL := range oidcEndpointURLs {
+		reporter.Infof(
+			"Once the cluster is uninstalled, delete its OIDC provider running "+
+				"'rosa delete oidc-provider --oidc-endpoint-url %s'",
+			oidcEndpointURL,
+		)
+	}
+	if failed > 0 {
+		reporter.Errorf("Failed to delete %d of %d clusters", failed, len(selected))
+		os.Exit(1)
+	}
+	reporter.Infof("%d clusters will start uninstalling now", len(selected))
+}
+
+// deleteClusters deletes the given clusters running at most 'concurrency' deletions at the same
+// time, and returns the error of each deletion in the same order as the clusters.
+func deleteClusters(reporter *rprtr.Object, ocmClient *ocm.Client, awsCreator *aws.Creator,
+	clusters []*cmv1.Cluster, concurrency int) []error {
+	results := make([]error, len(clusters))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, cluster := range clusters {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, cluster *cmv1.Cluster) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			reporter.Debugf("Deleting cluster '%s'", cluster.Name())
+			_, results[i] = ocmClient.DeleteCluster(cluster.ID(), awsCreator)
+		}(i, cluster)
+	}
+	wg.Wait()
+	return results
+}
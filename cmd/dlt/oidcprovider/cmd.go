@@ -0,0 +1,90 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oidcprovider
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/cmd/create/oidcprovider"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	clusterKey      string
+	oidcEndpointURL string
+}
+
+var Cmd = &cobra.Command{
+	Use:     "oidc-provider",
+	Aliases: []string{"oidcprovider"},
+	Short:   "Delete OIDC provider of an STS cluster",
+	Long:    "Delete the IAM OpenID Connect provider used by the operators of an STS cluster.",
+	Example: `  # Delete the OIDC provider of the cluster named "mycluster"
+  rosa delete oidc-provider --cluster=mycluster
+
+  # Delete the OIDC provider of an issuer
+  rosa delete oidc-provider --oidc-endpoint-url=https://oidc.example.com/mycluster`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the STS cluster whose OIDC provider will be deleted.",
+	)
+	flags.StringVar(
+		&args.oidcEndpointURL,
+		"oidc-endpoint-url",
+		"",
+		"URL of the OIDC issuer, used instead of the one of the cluster.",
+	)
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+
+	oidcEndpointURL := oidcprovider.GetOIDCEndpointURL(reporter, logger, awsClient,
+		args.clusterKey, args.oidcEndpointURL)
+
+	if !confirm.Confirm("delete OIDC provider '%s'", oidcEndpointURL) {
+		os.Exit(0)
+	}
+
+	deleted, err := awsClient.DeleteOpenIDConnectProvider(oidcEndpointURL)
+	if err != nil {
+		reporter.Errorf("Failed to delete OIDC provider '%s': %v", oidcEndpointURL, err)
+		os.Exit(1)
+	}
+	if !deleted {
+		reporter.Warnf("There is no OIDC provider for '%s'", oidcEndpointURL)
+		return
+	}
+	reporter.Infof("Deleted OIDC provider '%s'", oidcEndpointURL)
+}
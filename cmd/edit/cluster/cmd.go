@@ -42,15 +42,25 @@ var args struct {
 
 	// Networking options
 	private bool
+
+	// Proxy options
+	httpProxy                 string
+	httpsProxy                string
+	noProxy                   string
+	additionalTrustBundleFile string
 }
 
 var Cmd = &cobra.Command{
 	Use:   "cluster",
 	Short: "Edit cluster",
-	Long:  "Edit cluster.",
+	Long: "Edit cluster. The AWS tags of a cluster can't be edited, as the API only accepts them " +
+		"when the cluster is created.",
 	Example: `  # Edit a cluster named "mycluster" to make it private
   rosa edit cluster mycluster --private
 
+  # Edit a cluster named "mycluster" to use a cluster-wide proxy
+  rosa edit cluster -c mycluster --https-proxy http://proxy.example.com:3128 --no-proxy .corp.example.com
+
   # Edit all options interactively
   rosa edit cluster -c mycluster --interactive`,
 	Run: run,
@@ -93,6 +103,36 @@ func init() {
 		false,
 		"Restrict master API endpoint to direct, private connectivity.",
 	)
+
+	// Proxy options
+	flags.StringVar(
+		&args.httpProxy,
+		"http-proxy",
+		"",
+		"A proxy URL to use for creating HTTP connections outside the cluster. The URL scheme must be http. "+
+			"Set it to an empty string to remove it.",
+	)
+	flags.StringVar(
+		&args.httpsProxy,
+		"https-proxy",
+		"",
+		"A proxy URL to use for creating HTTPS connections outside the cluster. "+
+			"Set it to an empty string to remove it.",
+	)
+	flags.StringVar(
+		&args.noProxy,
+		"no-proxy",
+		"",
+		"A comma-separated list of destination domain names, IP addresses or CIDR blocks for which "+
+			"the proxy should not be used. Set it to an empty string to remove it.",
+	)
+	flags.StringVar(
+		&args.additionalTrustBundleFile,
+		"additional-trust-bundle-file",
+		"",
+		"A file containing a PEM encoded X.509 certificate bundle that will be added to the trusted "+
+			"certificates of the nodes. Set it to an empty string to remove the current bundle.",
+	)
 }
 
 func run(cmd *cobra.Command, _ []string) {
@@ -113,7 +153,8 @@ func run(cmd *cobra.Command, _ []string) {
 	// Enable interactive mode if no flags have been set
 	if !interactive.Enabled() {
 		changedFlags := false
-		for _, flag := range []string{"expiration-time", "expiration", "private"} {
+		for _, flag := range []string{"expiration-time", "expiration", "private", "http-proxy",
+			"https-proxy", "no-proxy", "additional-trust-bundle-file"} {
 			if cmd.Flags().Changed(flag) {
 				changedFlags = true
 			}
@@ -204,9 +245,101 @@ func run(cmd *cobra.Command, _ []string) {
 		}
 	}
 
+	// The cluster-wide proxy can only be used by clusters installed into existing subnets:
+	proxyFlagsChanged := false
+	for _, flag := range []string{"http-proxy", "https-proxy", "no-proxy", "additional-trust-bundle-file"} {
+		if cmd.Flags().Changed(flag) {
+			proxyFlagsChanged = true
+		}
+	}
+	existingVPC := len(cluster.AWS().SubnetIDs()) > 0
+	if proxyFlagsChanged && !existingVPC {
+		reporter.Errorf("The cluster-wide proxy and the additional trust bundle can only be used by " +
+			"clusters installed into existing subnets")
+		os.Exit(1)
+	}
+
+	var httpProxy, httpsProxy, noProxy, additionalTrustBundle *string
+	if proxyFlagsChanged || (existingVPC && interactive.Enabled()) {
+		proxy, err := ocmClient.GetClusterProxy(cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get proxy of cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+
+		httpProxy, err = getProxyValue(cmd, "http-proxy", "HTTP proxy", args.httpProxy, proxy.HTTPProxy)
+		if err != nil {
+			reporter.Errorf("Expected a valid http-proxy value: %s", err)
+			os.Exit(1)
+		}
+		httpsProxy, err = getProxyValue(cmd, "https-proxy", "HTTPS proxy", args.httpsProxy, proxy.HTTPSProxy)
+		if err != nil {
+			reporter.Errorf("Expected a valid https-proxy value: %s", err)
+			os.Exit(1)
+		}
+		noProxy, err = getProxyValue(cmd, "no-proxy", "No proxy", args.noProxy, proxy.NoProxy)
+		if err != nil {
+			reporter.Errorf("Expected a valid no-proxy value: %s", err)
+			os.Exit(1)
+		}
+
+		// Validate the resulting settings, taking into account the ones that don't change:
+		newValue := func(value *string, current string) string {
+			if value != nil {
+				return *value
+			}
+			return current
+		}
+		err = ocm.ValidateProxy(
+			newValue(httpProxy, proxy.HTTPProxy),
+			newValue(httpsProxy, proxy.HTTPSProxy),
+			newValue(noProxy, proxy.NoProxy),
+		)
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
+		}
+
+		additionalTrustBundleFile := args.additionalTrustBundleFile
+		if interactive.Enabled() && !cmd.Flags().Changed("additional-trust-bundle-file") {
+			help := cmd.Flags().Lookup("additional-trust-bundle-file").Usage
+			if proxy.AdditionalTrustBundle {
+				help = fmt.Sprintf("%s The cluster already has an additional trust bundle, leave it "+
+					"empty to keep it.", help)
+			}
+			additionalTrustBundleFile, err = interactive.GetCert(interactive.Input{
+				Question: "Additional trust bundle file path",
+				Help:     help,
+			})
+			if err != nil {
+				reporter.Errorf("Expected a valid additional trust bundle file: %s", err)
+				os.Exit(1)
+			}
+		}
+		if additionalTrustBundleFile != "" {
+			err = interactive.ValidateCertFile(additionalTrustBundleFile)
+			if err != nil {
+				reporter.Errorf("Expected a valid additional trust bundle file: %s", err)
+				os.Exit(1)
+			}
+			bundle, err := ocm.ReadTrustBundle(additionalTrustBundleFile)
+			if err != nil {
+				reporter.Errorf("%s", err)
+				os.Exit(1)
+			}
+			additionalTrustBundle = &bundle
+		} else if cmd.Flags().Changed("additional-trust-bundle-file") {
+			additionalTrustBundle = &additionalTrustBundleFile
+		}
+	}
+
 	clusterConfig := ocm.Spec{
-		Expiration: expiration,
-		Private:    private,
+		Expiration:            expiration,
+		Private:               private,
+		HTTPProxy:             httpProxy,
+		HTTPSProxy:            httpsProxy,
+		NoProxy:               noProxy,
+		AdditionalTrustBundle: additionalTrustBundle,
 	}
 
 	reporter.Debugf("Updating cluster '%s'", clusterKey)
@@ -218,6 +351,29 @@ func run(cmd *cobra.Command, _ []string) {
 	reporter.Infof("Updated cluster '%s'", clusterKey)
 }
 
+// getProxyValue returns the new value of a proxy setting, given either with the flag or in
+// interactive mode, or nil if it doesn't change.
+func getProxyValue(cmd *cobra.Command, flag string, question string, value string,
+	current string) (*string, error) {
+	changed := cmd.Flags().Changed(flag)
+	if interactive.Enabled() && !changed {
+		var err error
+		value, err = interactive.GetString(interactive.Input{
+			Question: question,
+			Help:     cmd.Flags().Lookup(flag).Usage,
+			Default:  current,
+		})
+		if err != nil {
+			return nil, err
+		}
+		changed = value != current
+	}
+	if !changed {
+		return nil, nil
+	}
+	return &value, nil
+}
+
 func validateExpiration() (expiration time.Time, err error) {
 	// Validate options
 	if len(args.expirationTime) > 0 && args.expirationDuration != 0 {
@@ -27,6 +27,7 @@ import (
 
 	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/interactive"
+	"github.com/openshift/rosa/pkg/kube"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
@@ -44,6 +45,11 @@ var args struct {
 	maxReplicas        int
 	labels             string
 	taints             string
+	useSpotInstances   bool
+	addLabels          []string
+	removeLabels       []string
+	addTaints          []string
+	removeTaints       []string
 }
 
 var Cmd = &cobra.Command{
@@ -54,7 +60,9 @@ var Cmd = &cobra.Command{
 	Example: `  # Set 4 replicas on machine pool 'mp1' on cluster 'mycluster'
   rosa edit machinepool --replicas=4 --cluster=mycluster mp1
   # Enable autoscaling and Set 3-5 replicas on machine pool 'mp1' on cluster 'mycluster'
-  rosa edit machinepool --enable-autoscaling --min-replicas=3 --max-replicas=5 --cluster=mycluster mp1`,
+  rosa edit machinepool --enable-autoscaling --min-replicas=3 --max-replicas=5 --cluster=mycluster mp1
+  # Add a label and remove a taint, keeping the rest, on machine pool 'mp1' on cluster 'mycluster'
+  rosa edit machinepool --add-label=tier=backend --remove-taint=dedicated --cluster=mycluster mp1`,
 	Run: run,
 	Args: func(_ *cobra.Command, argv []string) error {
 		if len(argv) != 1 {
@@ -118,9 +126,50 @@ func init() {
 		&args.taints,
 		"taints",
 		"",
-		"Taints for machine pool. Format should be a comma-separated list of 'key=value:ScheduleType'. "+
+		"Taints for machine pool. Format should be a comma-separated list of 'key=value:Effect', where "+
+			"the effect is one of NoSchedule, PreferNoSchedule or NoExecute. "+
 			"This list will overwrite any modifications made to node taints on an ongoing basis.",
 	)
+
+	flags.StringSliceVar(
+		&args.addLabels,
+		"add-label",
+		nil,
+		"Labels to add to the machine pool, keeping the existing ones. Format should be a "+
+			"comma-separated list of 'key=value'. Existing labels with the same keys are replaced.",
+	)
+
+	flags.StringSliceVar(
+		&args.removeLabels,
+		"remove-label",
+		nil,
+		"Keys of the labels to remove from the machine pool, as a comma-separated list.",
+	)
+
+	flags.StringSliceVar(
+		&args.addTaints,
+		"add-taint",
+		nil,
+		"Taints to add to the machine pool, keeping the existing ones. Format should be a "+
+			"comma-separated list of 'key=value:Effect'. Existing taints with the same key and effect "+
+			"are replaced.",
+	)
+
+	flags.StringSliceVar(
+		&args.removeTaints,
+		"remove-taint",
+		nil,
+		"Taints to remove from the machine pool, as a comma-separated list of keys, or of "+
+			"'key:Effect' to remove only the taint with that effect.",
+	)
+
+	flags.BoolVar(
+		&args.useSpotInstances,
+		"use-spot-instances",
+		false,
+		"Use spot instances for the machine pool. Existing machine pools can't be switched "+
+			"between spot and on-demand instances, a new machine pool needs to be created instead.",
+	)
 }
 
 func run(cmd *cobra.Command, argv []string) {
@@ -133,6 +182,19 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	if cmd.Flags().Changed("labels") &&
+		(cmd.Flags().Changed("add-label") || cmd.Flags().Changed("remove-label")) {
+		reporter.Errorf("Labels can't be replaced and edited at the same time, use either " +
+			"'--labels' or '--add-label' and '--remove-label'")
+		os.Exit(1)
+	}
+	if cmd.Flags().Changed("taints") &&
+		(cmd.Flags().Changed("add-taint") || cmd.Flags().Changed("remove-taint")) {
+		reporter.Errorf("Taints can't be replaced and edited at the same time, use either " +
+			"'--taints' or '--add-taint' and '--remove-taint'")
+		os.Exit(1)
+	}
+
 	// Check that the cluster key (name, identifier or external identifier) given by the user
 	// is reasonably safe so that there is no risk of SQL injection:
 	clusterKey := args.clusterKey
@@ -186,14 +248,19 @@ func run(cmd *cobra.Command, argv []string) {
 
 	// Editing the default machine pool is a different process
 	if machinePoolID == "Default" {
-		if cmd.Flags().Changed("labels") {
+		if isLabelsSet(cmd) {
 			reporter.Errorf("Labels cannot be updated on the Default machine pool")
 			os.Exit(1)
 		}
-		if cmd.Flags().Changed("taints") {
+		if isTaintsSet(cmd) {
 			reporter.Errorf("Taints are not supported on the Default machine pool")
 			os.Exit(1)
 		}
+		if args.useSpotInstances {
+			reporter.Errorf("The Default machine pool can't use spot instances, create a new " +
+				"machine pool with 'rosa create machinepool --use-spot-instances' instead")
+			os.Exit(1)
+		}
 
 		autoscaling, replicas, minReplicas, maxReplicas := getReplicas(cmd, reporter, machinePoolID,
 			cluster.Nodes().Compute(), cluster.Nodes().AutoscaleCompute())
@@ -253,6 +320,29 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
+	// Spot instances can only be chosen when the machine pool is created:
+	if cmd.Flags().Changed("use-spot-instances") {
+		machinePoolsAWS, err := ocmClient.GetMachinePoolsAWS(cluster.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get AWS settings of machine pool '%s' for cluster '%s': %v",
+				machinePoolID, clusterKey, err)
+			os.Exit(1)
+		}
+		awsConfig := machinePoolsAWS[machinePoolID]
+		usesSpotInstances := awsConfig != nil && awsConfig.Spot != nil
+		if args.useSpotInstances != usesSpotInstances {
+			current, requested := "on-demand", "spot"
+			if usesSpotInstances {
+				current, requested = "spot", "on-demand"
+			}
+			reporter.Errorf("Machine pool '%s' uses %s instances and can't be switched to %s "+
+				"instances. Create a new machine pool with 'rosa create machinepool "+
+				"--use-spot-instances=%t' and delete this one instead",
+				machinePoolID, current, requested, args.useSpotInstances)
+			os.Exit(1)
+		}
+	}
+
 	autoscaling, replicas, minReplicas, maxReplicas := getReplicas(cmd, reporter, machinePoolID,
 		machinePool.Replicas(), machinePool.Autoscaling())
 
@@ -262,76 +352,88 @@ func run(cmd *cobra.Command, argv []string) {
 		os.Exit(1)
 	}
 
-	if cluster.MultiAZ() &&
-		(!autoscaling && replicas%3 != 0 ||
-			(autoscaling && (minReplicas%3 != 0 || maxReplicas%3 != 0))) {
-		reporter.Errorf("Multi AZ clusters require that the number of MachinePool replicas be a multiple of 3")
+	// Replicas are spread evenly across the availability zones of the machine pool, which are
+	// all the zones of the cluster unless the machine pool is pinned to some of them:
+	zones := len(machinePool.AvailabilityZones())
+	if zones == 0 {
+		zones = len(cluster.Nodes().AvailabilityZones())
+	}
+	if zones > 1 &&
+		(!autoscaling && replicas%zones != 0 ||
+			(autoscaling && (minReplicas%zones != 0 || maxReplicas%zones != 0))) {
+		reporter.Errorf("Machine pools in %d availability zones require that the number of replicas "+
+			"be a multiple of %d", zones, zones)
 		os.Exit(1)
 	}
 
-	labels := args.labels
-	labelMap := make(map[string]string)
-	if interactive.Enabled() {
-		if labels == "" {
-			for lk, lv := range machinePool.Labels() {
-				if labels != "" {
-					labels += ","
-				}
-				labels += fmt.Sprintf("%s=%s", lk, lv)
-			}
+	// Labels and taints are either replaced, or edited starting from the current ones:
+	labelMap := machinePool.Labels()
+	if cmd.Flags().Changed("labels") {
+		labelMap, err = kube.ParseLabels(strings.Split(args.labels, ","))
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
-		labels, err = interactive.GetString(interactive.Input{
+	}
+	addLabels, err := kube.ParseLabels(args.addLabels)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	labelMap, err = kube.EditLabels(labelMap, addLabels, args.removeLabels)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if interactive.Enabled() {
+		labels, err := interactive.GetString(interactive.Input{
 			Question: "Labels",
 			Help:     cmd.Flags().Lookup("labels").Usage,
-			Default:  labels,
+			Default:  strings.Join(kube.LabelStrings(labelMap), ","),
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
 			os.Exit(1)
 		}
-	}
-	labels = strings.Trim(labels, " ")
-	if labels != "" {
-		for _, label := range strings.Split(labels, ",") {
-			if !strings.Contains(label, "=") {
-				reporter.Errorf("Expected key=value format for labels")
-				os.Exit(1)
-			}
-			tokens := strings.Split(label, "=")
-			labelMap[strings.TrimSpace(tokens[0])] = strings.TrimSpace(tokens[1])
+		labelMap, err = kube.ParseLabels(strings.Split(labels, ","))
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
 	}
 
-	taints := args.taints
-	taintBuilders := []*cmv1.TaintBuilder{}
-	if interactive.Enabled() {
-		if taints == "" {
-			for _, taint := range machinePool.Taints() {
-				if taints != "" {
-					taints += ","
-				}
-				taints += fmt.Sprintf("%s=%s:%s", taint.Key(), taint.Value(), taint.Effect())
-			}
+	taintList := kube.FromTaints(machinePool.Taints())
+	if cmd.Flags().Changed("taints") {
+		taintList, err = kube.ParseTaints(strings.Split(args.taints, ","))
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
-		taints, err = interactive.GetString(interactive.Input{
+	}
+	addTaints, err := kube.ParseTaints(args.addTaints)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	taintList, err = kube.EditTaints(taintList, addTaints, args.removeTaints)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	if interactive.Enabled() {
+		taints, err := interactive.GetString(interactive.Input{
 			Question: "Taints",
 			Help:     cmd.Flags().Lookup("taints").Usage,
-			Default:  taints,
+			Default:  strings.Join(kube.TaintStrings(taintList), ","),
 		})
 		if err != nil {
 			reporter.Errorf("Expected a valid comma-separated list of attributes: %s", err)
 			os.Exit(1)
 		}
-	}
-	taints = strings.Trim(taints, " ")
-	if taints != "" {
-		for _, taint := range strings.Split(taints, ",") {
-			if !strings.Contains(taint, "=") || !strings.Contains(taint, ":") {
-				reporter.Errorf("Expected key=value:scheduleType format for taints")
-				os.Exit(1)
-			}
-			tokens := strings.FieldsFunc(taint, Split)
-			taintBuilders = append(taintBuilders, cmv1.NewTaint().Key(tokens[0]).Value(tokens[1]).Effect(tokens[2]))
+		taintList, err = kube.ParseTaints(strings.Split(taints, ","))
+		if err != nil {
+			reporter.Errorf("%s", err)
+			os.Exit(1)
 		}
 	}
 
@@ -341,11 +443,13 @@ func run(cmd *cobra.Command, argv []string) {
 	// Check either for an explicit flag or interactive mode. Since
 	// interactive will always show both labels and taints we can safely
 	// assume that the value entered is the same as the value desired.
-	if cmd.Flags().Changed("labels") || interactive.Enabled() {
+	if isLabelsSet(cmd) || interactive.Enabled() {
+		printDiff("Labels", kube.LabelStrings(machinePool.Labels()), kube.LabelStrings(labelMap))
 		mpBuilder = mpBuilder.Labels(labelMap)
 	}
-	if cmd.Flags().Changed("taints") || interactive.Enabled() {
-		mpBuilder = mpBuilder.Taints(taintBuilders...)
+	if isTaintsSet(cmd) || interactive.Enabled() {
+		printDiff("Taints", kube.TaintStrings(kube.FromTaints(machinePool.Taints())), kube.TaintStrings(taintList))
+		mpBuilder = mpBuilder.Taints(kube.Builders(taintList)...)
 	}
 
 	if autoscaling {
@@ -473,6 +577,27 @@ func getReplicas(cmd *cobra.Command,
 	return
 }
 
-func Split(r rune) bool {
-	return r == '=' || r == ':'
+func isLabelsSet(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("labels") ||
+		cmd.Flags().Changed("add-label") ||
+		cmd.Flags().Changed("remove-label")
+}
+
+func isTaintsSet(cmd *cobra.Command) bool {
+	return cmd.Flags().Changed("taints") ||
+		cmd.Flags().Changed("add-taint") ||
+		cmd.Flags().Changed("remove-taint")
+}
+
+// printDiff prints the labels or taints that are removed and added by the edit.
+func printDiff(title string, before []string, after []string) {
+	diff := kube.Diff(before, after)
+	if len(diff) == 0 {
+		fmt.Printf("%s: no changes\n", title)
+		return
+	}
+	fmt.Printf("%s:\n", title)
+	for _, line := range diff {
+		fmt.Printf("  %s\n", line)
+	}
 }
@@ -0,0 +1,170 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	clusterKey string
+
+	// Wait for the cluster to be hibernating
+	wait    bool
+	timeout time.Duration
+}
+
+var Cmd = &cobra.Command{
+	Use:   "cluster",
+	Short: "Hibernate cluster",
+	Long: "Hibernate a cluster, stopping its nodes till it is resumed with 'rosa resume cluster'. " +
+		"The cluster can't be used while it is hibernating.",
+	Example: `  # Hibernate a cluster named "mycluster"
+  rosa hibernate cluster --cluster=mycluster
+
+  # Hibernate a cluster and wait until all its nodes are stopped
+  rosa hibernate cluster --cluster=mycluster --wait`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	arguments.AddRegionFlag(flags)
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster to hibernate.",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.BoolVar(
+		&args.wait,
+		"wait",
+		false,
+		"Block until the cluster is hibernating. Exits with 2 if the cluster fails to hibernate and "+
+			"with 3 if it doesn't finish before the timeout.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		30*time.Minute,
+		"Maximum time to wait for the cluster to be hibernating, used with '--wait'.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+
+	if args.timeout <= 0 {
+		reporter.Errorf("Expected a positive timeout, got '%s'", args.timeout)
+		os.Exit(1)
+	}
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	operation := ocm.HibernateOperation
+	err = operation.Validate(ocmClient, cluster)
+	if err != nil {
+		reporter.Errorf("Can't hibernate cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
+	if !confirm.Confirm("hibernate cluster %s", clusterKey) {
+		os.Exit(0)
+	}
+
+	reporter.Debugf("Hibernating cluster '%s'", clusterKey)
+	err = operation.Send(ocmClient, cluster)
+	if err != nil {
+		reporter.Errorf("Failed to hibernate cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	reporter.Infof("Cluster '%s' will start hibernating now", clusterKey)
+
+	if !args.wait {
+		reporter.Infof(
+			"To determine when your cluster is hibernating, run 'rosa describe cluster -c %s'.",
+			clusterKey,
+		)
+		return
+	}
+	result, err := ocmClient.WaitForClusterAndReport(reporter, cluster, operation.Wait, args.timeout)
+	if err != nil {
+		reporter.Errorf("Failed to get state of cluster '%s': %v", cluster.Name(), err)
+		os.Exit(1)
+	}
+	if result != ocm.WaitSucceeded {
+		os.Exit(result.ExitCode())
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -31,13 +32,26 @@ import (
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
+var args struct {
+	expired        bool
+	expiringWithin time.Duration
+	expiringAfter  string
+	expiringBefore string
+}
+
 var Cmd = &cobra.Command{
 	Use:     "clusters",
 	Aliases: []string{"cluster"},
 	Short:   "List clusters",
 	Long:    "List clusters.",
 	Example: `  # List all clusters
-  rosa list clusters`,
+  rosa list clusters
+
+  # List the clusters that expire in the next 24 hours
+  rosa list clusters --expiring-within=24h
+
+  # List the clusters that expire during May 2021
+  rosa list clusters --expiring-after=2021-05-01T00:00:00Z --expiring-before=2021-06-01T00:00:00Z`,
 	Args: cobra.NoArgs,
 	Run:  run,
 }
@@ -47,6 +61,32 @@ func init() {
 	flags.SortFlags = false
 
 	arguments.AddRegionFlag(flags)
+
+	flags.BoolVar(
+		&args.expired,
+		"expired",
+		false,
+		"List only the clusters whose expiration time has already passed.",
+	)
+	flags.DurationVar(
+		&args.expiringWithin,
+		"expiring-within",
+		0,
+		"List only the clusters that expire within the given duration, for example 24h.",
+	)
+	flags.StringVar(
+		&args.expiringAfter,
+		"expiring-after",
+		"",
+		"List only the clusters that expire after the given time (RFC3339).",
+	)
+	flags.StringVar(
+		&args.expiringBefore,
+		"expiring-before",
+		"",
+		"List only the clusters that expire before the given time (RFC3339).",
+	)
+
 	output.AddFlag(Cmd)
 }
 
@@ -54,6 +94,12 @@ func run(_ *cobra.Command, _ []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	selector, err := buildSelector(time.Now())
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+
 	// Create the AWS client:
 	awsClient, err := aws.NewClient().
 		Region(arguments.GetRegion()).
@@ -91,6 +137,7 @@ func run(_ *cobra.Command, _ []string) {
 		reporter.Errorf("Failed to get clusters: %v", err)
 		os.Exit(1)
 	}
+	clusters = ocm.SelectClusters(clusters, selector)
 
 	if output.HasFlag() {
 		err = output.Print(clusters)
@@ -108,15 +155,56 @@ func run(_ *cobra.Command, _ []string) {
 
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tNAME\tSTATE\n")
+	if selector.Empty() {
+		fmt.Fprintf(writer, "ID\tNAME\tSTATE\n")
+	} else {
+		fmt.Fprintf(writer, "ID\tNAME\tSTATE\tEXPIRES\n")
+	}
 	for _, cluster := range clusters {
 		fmt.Fprintf(
 			writer,
-			"%s\t%s\t%s\n",
+			"%s\t%s\t%s",
 			cluster.ID(),
 			cluster.Name(),
 			cluster.State(),
 		)
+		if !selector.Empty() {
+			fmt.Fprintf(writer, "\t%s", cluster.ExpirationTimestamp().Format(time.RFC3339))
+		}
+		fmt.Fprintf(writer, "\n")
 	}
 	writer.Flush()
 }
+
+// buildSelector returns the selector of the clusters that match the expiration flags.
+func buildSelector(now time.Time) (*ocm.ClusterSelector, error) {
+	selector := &ocm.ClusterSelector{}
+	if args.expiringAfter != "" {
+		expiringAfter, err := time.Parse(time.RFC3339, args.expiringAfter)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse expiring-after time: %v", err)
+		}
+		selector.ExpiringAfter = expiringAfter
+	}
+	if args.expiringBefore != "" {
+		expiringBefore, err := time.Parse(time.RFC3339, args.expiringBefore)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse expiring-before time: %v", err)
+		}
+		selector.ExpiringBefore = expiringBefore
+	}
+	if args.expiringWithin < 0 {
+		return nil, fmt.Errorf("Expected a positive expiring-within duration, got '%s'", args.expiringWithin)
+	}
+	if args.expiringWithin > 0 || args.expired {
+		if args.expiringBefore != "" || (args.expiringWithin > 0 && args.expired) {
+			return nil, fmt.Errorf("Only one of expiring-before, expiring-within or expired may be used")
+		}
+		selector.ExpiringBefore = now.Add(args.expiringWithin)
+	}
+	err := selector.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return selector, nil
+}
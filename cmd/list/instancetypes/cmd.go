@@ -19,28 +19,96 @@ package instancetypes
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/ocm"
 	"github.com/openshift/rosa/pkg/output"
 	rprtr "github.com/openshift/rosa/pkg/reporter"
 )
 
+var args struct {
+	minCPU            int
+	minMemory         float64
+	category          string
+	gpu               bool
+	sortBy            string
+	availabilityZones []string
+}
+
+// filterFlags are the flags that restrict the instance types that are listed.
+var filterFlags = []string{"min-cpu", "min-memory", "category", "gpu", "availability-zone"}
+
 var Cmd = &cobra.Command{
 	Use:     "instance-types",
 	Aliases: []string{"instancetypes"},
 	Short:   "List Instance types",
-	Long:    "List Instance types that are available for use with ROSA.",
+	Long: "List Instance types that are available for use with ROSA, and the availability zones of " +
+		"the region where EC2 offers them.",
 	Example: `  # List all instance types
-  rosa list instance-types`,
-	Run: run,
+  rosa list instance-types
+
+  # List the memory optimized instance types with at least 8 cores, sorted by memory
+  rosa list instance-types --category=memory_optimized --min-cpu=8 --sort-by=memory
+
+  # List the GPU instance types offered in two availability zones of the 'us-west-2' region
+  rosa list instance-types --gpu --region=us-west-2 --availability-zone=us-west-2a,us-west-2b`,
+	Args: cobra.NoArgs,
+	Run:  run,
 }
 
 func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	arguments.AddRegionFlag(flags)
+
+	flags.IntVar(
+		&args.minCPU,
+		"min-cpu",
+		0,
+		"List only the instance types with at least this number of CPU cores.",
+	)
+	flags.Float64Var(
+		&args.minMemory,
+		"min-memory",
+		0,
+		"List only the instance types with at least this amount of memory, in GiB.",
+	)
+	flags.StringVar(
+		&args.category,
+		"category",
+		"",
+		fmt.Sprintf("List only the instance types of this category. Valid categories are: %s.",
+			strings.Join(ocm.MachineTypeCategories, ", ")),
+	)
+	flags.BoolVar(
+		&args.gpu,
+		"gpu",
+		false,
+		"List only the instance types with GPUs.",
+	)
+	flags.StringVar(
+		&args.sortBy,
+		"sort-by",
+		"cpu",
+		fmt.Sprintf("Sort the instance types by this key. Valid keys are: %s.",
+			strings.Join(ocm.MachineTypeSortKeys, ", ")),
+	)
+	flags.StringSliceVar(
+		&args.availabilityZones,
+		"availability-zone",
+		nil,
+		"List only the instance types offered in all these availability zones of the region.",
+	)
+
 	output.AddFlag(Cmd)
 }
 
@@ -48,6 +116,30 @@ func run(cmd *cobra.Command, _ []string) {
 	reporter := rprtr.CreateReporterOrExit()
 	logger := logging.CreateLoggerOrExit(reporter)
 
+	filter := &ocm.MachineTypeFilter{
+		MinCPU:       args.minCPU,
+		MinMemoryGiB: args.minMemory,
+		Category:     args.category,
+		GPU:          args.gpu,
+	}
+	err := filter.Validate()
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	region, err := aws.GetRegion(arguments.GetRegion())
+	if err != nil {
+		reporter.Errorf("Error getting region: %v", err)
+		os.Exit(1)
+	}
+	for _, zone := range args.availabilityZones {
+		if !strings.HasPrefix(zone, region) {
+			reporter.Errorf("Availability zone '%s' isn't in region '%s'", zone, region)
+			os.Exit(1)
+		}
+	}
+
 	// Create the client for the OCM API:
 	ocmClient, err := ocm.NewClient().
 		Logger(logger).
@@ -70,6 +162,35 @@ func run(cmd *cobra.Command, _ []string) {
 		reporter.Errorf("Failed to fetch instance types: %v", err)
 		os.Exit(1)
 	}
+	machineTypes = ocm.FilterMachineTypes(machineTypes, filter)
+	err = ocm.SortMachineTypes(machineTypes, args.sortBy)
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+
+	// The offerings are only needed to filter by zone and to fill the availability column, so
+	// when they can't be retrieved the list is still printed, without that column:
+	reporter.Debugf("Fetching instance type offerings in region '%s'", region)
+	offerings, err := getOfferings(logger, region)
+	if err != nil {
+		if len(args.availabilityZones) > 0 {
+			reporter.Errorf("Failed to fetch instance type offerings: %v", err)
+			os.Exit(1)
+		}
+		reporter.Warnf("Failed to fetch instance type offerings, availability zones won't be listed: %v", err)
+	}
+	if len(args.availabilityZones) > 0 {
+		offered := []*ocm.MachineType{}
+		for _, machineType := range machineTypes {
+			zones := offerings[machineType.MachineType.ID()]
+			if aws.ValidateInstanceTypeZones(machineType.MachineType.ID(), zones,
+				args.availabilityZones, false) == nil {
+				offered = append(offered, machineType)
+			}
+		}
+		machineTypes = offered
+	}
 
 	if output.HasFlag() {
 		var instanceTypes []*cmv1.MachineType
@@ -85,13 +206,21 @@ func run(cmd *cobra.Command, _ []string) {
 	}
 
 	if len(machineTypes) == 0 {
-		reporter.Warnf("There are no machine types supported for your account. Contact Red Hat support.")
+		if filtered(cmd) {
+			reporter.Warnf("There are no instance types matching the given criteria.")
+		} else {
+			reporter.Warnf("There are no machine types supported for your account. Contact Red Hat support.")
+		}
 		os.Exit(1)
 	}
 
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(writer, "ID\tCATEGORY\tCPU_CORES\tMEMORY\t\n")
+	if offerings != nil {
+		fmt.Fprintf(writer, "ID\tCATEGORY\tCPU_CORES\tMEMORY\tAVAILABILITY ZONES (%s)\n", region)
+	} else {
+		fmt.Fprintf(writer, "ID\tCATEGORY\tCPU_CORES\tMEMORY\t\n")
+	}
 
 	for _, machine := range machineTypes {
 		if !machine.Available {
@@ -99,15 +228,43 @@ func run(cmd *cobra.Command, _ []string) {
 		}
 		availableMachine := machine.MachineType
 		fmt.Fprintf(writer,
-			"%s\t%s\t%d\t%s\n",
+			"%s\t%s\t%d\t%s\t",
 			availableMachine.ID(), availableMachine.Category(), int(availableMachine.CPU().Value()),
 			ByteCountIEC(int(availableMachine.Memory().Value()),
 				availableMachine.Memory().Unit()),
 		)
+		if offerings != nil {
+			fmt.Fprintf(writer, "%s", printZones(region, offerings[availableMachine.ID()]))
+		}
+		fmt.Fprintf(writer, "\n")
 	}
 	writer.Flush()
 }
 
+// getOfferings returns the availability zones of the region where each instance type is offered.
+func getOfferings(logger *logrus.Logger, region string) (map[string][]string, error) {
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Region(region).
+		Build()
+	if err != nil {
+		return nil, err
+	}
+	return awsClient.GetInstanceTypeZones(nil)
+}
+
+// printZones returns the list of zones without the region prefix, for example 'a, b, c'.
+func printZones(region string, zones []string) string {
+	if len(zones) == 0 {
+		return "none"
+	}
+	suffixes := []string{}
+	for _, zone := range zones {
+		suffixes = append(suffixes, strings.TrimPrefix(zone, region))
+	}
+	return strings.Join(suffixes, ", ")
+}
+
 func ByteCountIEC(b int, uValue string) string {
 	var unit int
 	if uValue == "B" {
@@ -124,3 +281,13 @@ func ByteCountIEC(b int, uValue string) string {
 	return fmt.Sprintf("%.1f %ciB",
 		float64(b)/float64(div), "KMGTPE"[exp])
 }
+
+// filtered returns true if any of the filter flags was given.
+func filtered(cmd *cobra.Command) bool {
+	for _, name := range filterFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -128,6 +128,12 @@ func run(_ *cobra.Command, _ []string) {
 		os.Exit(1)
 	}
 
+	machinePoolsAWS, err := ocmClient.GetMachinePoolsAWS(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get AWS settings of machine pools for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+
 	// Add default machine pool to the list
 	defaultMachinePoolBuilder := cmv1.NewMachinePool().
 		ID("Default").
@@ -158,16 +164,19 @@ func run(_ *cobra.Command, _ []string) {
 	// Create the writer that will be used to print the tabulated results:
 	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
 
-	fmt.Fprintf(writer, "ID\tAUTOSCALING\tREPLICAS\tINSTANCE TYPE\tLABELS\t\tTAINTS\t\tAVAILABILITY ZONES\n")
+	fmt.Fprintf(writer, "ID\tAUTOSCALING\tREPLICAS\tINSTANCE TYPE\tSPOT INSTANCES\tLABELS\t\tTAINTS\t\t"+
+		"AVAILABILITY ZONES\t\tSUBNETS\n")
 	for _, machinePool := range machinePools {
-		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t\t%s\t\t%s\n",
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t\t%s\t\t%s\t\t%s\n",
 			machinePool.ID(),
 			printAutoscaling(machinePool.Autoscaling()),
 			printReplicas(machinePool.Autoscaling(), machinePool.Replicas()),
 			machinePool.InstanceType(),
+			printSpot(machinePoolsAWS[machinePool.ID()]),
 			printLabels(machinePool.Labels()),
 			printTaints(machinePool.Taints()),
 			printAZ(machinePool.AvailabilityZones()),
+			printSubnets(machinePoolsAWS[machinePool.ID()]),
 		)
 	}
 	writer.Flush()
@@ -189,6 +198,16 @@ func printReplicas(autoscaling *cmv1.MachinePoolAutoscaling, replicas int) strin
 	return fmt.Sprintf("%d", replicas)
 }
 
+func printSpot(awsConfig *ocm.MachinePoolAWS) string {
+	if awsConfig == nil || awsConfig.Spot == nil {
+		return "No"
+	}
+	if awsConfig.Spot.MaxPrice == nil {
+		return "Yes (max on-demand)"
+	}
+	return fmt.Sprintf("Yes (max $%g)", *awsConfig.Spot.MaxPrice)
+}
+
 func printAZ(az []string) string {
 	if len(az) == 0 {
 		return ""
@@ -196,6 +215,13 @@ func printAZ(az []string) string {
 	return strings.Join(az, ", ")
 }
 
+func printSubnets(awsConfig *ocm.MachinePoolAWS) string {
+	if awsConfig == nil {
+		return ""
+	}
+	return strings.Join(awsConfig.Subnets, ", ")
+}
+
 func printLabels(labels map[string]string) string {
 	if len(labels) == 0 {
 		return ""
@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package profile
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var Cmd = &cobra.Command{
+	Use:     "profiles",
+	Aliases: []string{"profile"},
+	Short:   "List cluster profiles",
+	Long:    "List the cluster profiles saved with 'rosa create profile'.",
+	Example: `  # List all cluster profiles
+  rosa list profiles`,
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	path, err := ocm.ProfilesLocation()
+	if err != nil {
+		reporter.Errorf("Failed to get location of profiles: %v", err)
+		os.Exit(1)
+	}
+	profiles, err := clusterspec.LoadProfiles(path)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	if len(profiles) == 0 {
+		reporter.Infof("There are no cluster profiles. To create one, run 'rosa create profile'")
+		os.Exit(0)
+	}
+
+	// Create the writer that will be used to print the tabulated results:
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "NAME\tREGION\tMULTI-AZ\tMACHINE TYPE\tNODES\tVERSION\tCHANNEL GROUP\n")
+	for _, name := range clusterspec.ProfileNames(profiles) {
+		profile := profiles[name]
+		machineType := ""
+		nodes := ""
+		if profile.Compute != nil {
+			machineType = profile.Compute.MachineType
+			if profile.Compute.Autoscaling != nil {
+				nodes = fmt.Sprintf("%d-%d", profile.Compute.Autoscaling.MinReplicas,
+					profile.Compute.Autoscaling.MaxReplicas)
+			} else if profile.Compute.Nodes != 0 {
+				nodes = strconv.Itoa(profile.Compute.Nodes)
+			}
+		}
+		multiAZ := ""
+		if profile.MultiAZ != nil {
+			multiAZ = strconv.FormatBool(*profile.MultiAZ)
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			name,
+			profile.Region,
+			multiAZ,
+			machineType,
+			nodes,
+			profile.OpenShiftVersion,
+			profile.ChannelGroup,
+		)
+	}
+	writer.Flush()
+}
@@ -0,0 +1,552 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machinepool
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	"github.com/openshift/rosa/pkg/logging"
+	"github.com/openshift/rosa/pkg/ocm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// Regular expression to used to make sure that the identifier given by the
+// user is safe and that it there is no risk of SQL injection:
+var machinePoolKeyRE = regexp.MustCompile(`^[a-z]([-a-z0-9]*[a-z0-9])?$`)
+
+var args struct {
+	clusterKey   string
+	machinePool  string
+	instanceType string
+	name         string
+	step         int
+	timeout      time.Duration
+	dryRun       bool
+}
+
+var Cmd = &cobra.Command{
+	Use:     "machinepool",
+	Aliases: []string{"machine-pool"},
+	Short:   "Migrate a machine pool to a different instance type",
+	Long: "Replace a machine pool with a new one that uses a different instance type. The new " +
+		"machine pool has the same labels, taints and replicas. Once its replicas are ready the old " +
+		"machine pool is scaled down in steps and then deleted. If the migration is interrupted, " +
+		"running the same command again resumes it.",
+	Example: `  # Migrate machine pool "mp1" of cluster "mycluster" to m5.4xlarge instances
+  rosa migrate machinepool -c mycluster --machinepool mp1 --instance-type m5.4xlarge
+
+  # Show the steps of the migration without running them
+  rosa migrate machinepool -c mycluster --machinepool mp1 --instance-type m5.4xlarge --dry-run`,
+	Args: cobra.NoArgs,
+	Run:  run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringVarP(
+		&args.clusterKey,
+		"cluster",
+		"c",
+		"",
+		"Name or ID of the cluster of the machine pool (required).",
+	)
+	Cmd.MarkFlagRequired("cluster")
+
+	flags.StringVar(
+		&args.machinePool,
+		"machinepool",
+		"",
+		"ID of the machine pool to migrate (required).",
+	)
+	Cmd.MarkFlagRequired("machinepool")
+
+	flags.StringVar(
+		&args.instanceType,
+		"instance-type",
+		"",
+		"Instance type of the replacement machine pool (required).",
+	)
+	Cmd.MarkFlagRequired("instance-type")
+
+	flags.StringVar(
+		&args.name,
+		"name",
+		"",
+		"Name of the replacement machine pool. The default is the name of the machine pool "+
+			"followed by the instance type, for example 'mp1-m5-4xlarge'.",
+	)
+
+	flags.IntVar(
+		&args.step,
+		"step",
+		0,
+		"Number of replicas removed from the old machine pool in each step. The default is the "+
+			"number of availability zones of the machine pool.",
+	)
+
+	flags.DurationVar(
+		&args.timeout,
+		"timeout",
+		30*time.Minute,
+		"Maximum time to wait for the replicas of the machine pools in each step.",
+	)
+
+	flags.BoolVar(
+		&args.dryRun,
+		"dry-run",
+		false,
+		"Only show the steps of the migration, without running them.",
+	)
+
+	confirm.AddFlag(flags)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	// Check that the cluster key (name, identifier or external identifier) given by the user
+	// is reasonably safe so that there is no risk of SQL injection:
+	clusterKey := args.clusterKey
+	if !ocm.IsValidClusterKey(clusterKey) {
+		reporter.Errorf(
+			"Cluster name, identifier or external identifier '%s' isn't valid: it "+
+				"must contain only letters, digits, dashes and underscores",
+			clusterKey,
+		)
+		os.Exit(1)
+	}
+	machinePoolID := args.machinePool
+	if machinePoolID == "Default" {
+		reporter.Errorf("The Default machine pool can't be migrated, create a new machine pool " +
+			"with 'rosa create machinepool' instead")
+		os.Exit(1)
+	}
+	if !machinePoolKeyRE.MatchString(machinePoolID) {
+		reporter.Errorf("Expected a valid identifier for the machine pool")
+		os.Exit(1)
+	}
+	instanceType := strings.TrimSpace(args.instanceType)
+	if instanceType == "" {
+		reporter.Errorf("Expected a valid instance type")
+		os.Exit(1)
+	}
+	if args.step < 0 {
+		reporter.Errorf("Expected a positive number of replicas for the step, got %d", args.step)
+		os.Exit(1)
+	}
+	if args.timeout <= 0 {
+		reporter.Errorf("Expected a positive timeout, got %s", args.timeout)
+		os.Exit(1)
+	}
+
+	awsClient := aws.GetAWSClientForUserRegion(reporter, logger)
+	awsCreator, err := awsClient.GetCreator()
+	if err != nil {
+		reporter.Errorf("Failed to get AWS creator: %v", err)
+		os.Exit(1)
+	}
+
+	// Create the client for the OCM API:
+	ocmClient, err := ocm.NewClient().
+		Logger(logger).
+		Build()
+	if err != nil {
+		reporter.Errorf("Failed to create OCM connection: %v", err)
+		os.Exit(1)
+	}
+	defer func() {
+		err = ocmClient.Close()
+		if err != nil {
+			reporter.Errorf("Failed to close OCM connection: %v", err)
+		}
+	}()
+
+	// Try to find the cluster:
+	reporter.Debugf("Loading cluster '%s'", clusterKey)
+	cluster, err := ocmClient.GetCluster(clusterKey, awsCreator)
+	if err != nil {
+		reporter.Errorf("Failed to get cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	if cluster.State() != cmv1.ClusterStateReady {
+		reporter.Errorf("Cluster '%s' is not yet ready", clusterKey)
+		os.Exit(1)
+	}
+
+	reporter.Debugf("Loading machine pools for cluster '%s'", clusterKey)
+	machinePools, err := ocmClient.GetMachinePools(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get machine pools for cluster '%s': %v", clusterKey, err)
+		os.Exit(1)
+	}
+	machinePool := findMachinePool(machinePools, machinePoolID)
+
+	// Load the progress of the migration, if it was already started:
+	path, err := ocm.MigrationsLocation()
+	if err != nil {
+		reporter.Errorf("Failed to get location of migrations: %v", err)
+		os.Exit(1)
+	}
+	migrations, err := ocm.LoadMigrations(path)
+	if err != nil {
+		reporter.Errorf("%v", err)
+		os.Exit(1)
+	}
+	migration, resuming := migrations[ocm.MigrationKey(cluster.ID(), machinePoolID)]
+	if resuming {
+		if migration.InstanceType != instanceType {
+			reporter.Errorf("Machine pool '%s' is already being migrated to instance type '%s', "+
+				"run 'rosa migrate machinepool' with that instance type to finish the migration",
+				machinePoolID, migration.InstanceType)
+			os.Exit(1)
+		}
+		if args.name != "" && args.name != migration.Replacement {
+			reporter.Errorf("Machine pool '%s' is already being replaced by machine pool '%s'",
+				machinePoolID, migration.Replacement)
+			os.Exit(1)
+		}
+		reporter.Infof("Resuming the migration of machine pool '%s'", machinePoolID)
+	} else {
+		migration = newMigration(reporter, logger, ocmClient, cluster, machinePools, machinePool,
+			instanceType)
+	}
+
+	// The old machine pool is needed to create the replacement, it only goes away in the last
+	// step:
+	if machinePool == nil && migration.Step != ocm.MigrationDelete {
+		reporter.Errorf("Failed to get machine pool '%s' for cluster '%s'", machinePoolID, clusterKey)
+		os.Exit(1)
+	}
+	replacement := findMachinePool(machinePools, migration.Replacement)
+	if replacement == nil && machinePool != nil {
+		replacement, err = ocm.ReplacementMachinePool(machinePool, migration.Replacement, instanceType)
+		if err != nil {
+			reporter.Errorf("Failed to create machine pool for cluster '%s': %v", clusterKey, err)
+			os.Exit(1)
+		}
+	}
+
+	printPlan(migration, replacement)
+	if args.dryRun {
+		reporter.Infof("Dry run, no changes were made")
+		os.Exit(0)
+	}
+	if !confirm.Confirm("replace machine pool '%s' with machine pool '%s'", machinePoolID,
+		migration.Replacement) {
+		os.Exit(0)
+	}
+
+	save := func() {
+		err = ocm.SaveMigrations(path, migrations)
+		if err != nil {
+			reporter.Errorf("%v", err)
+			os.Exit(1)
+		}
+	}
+	migrations[migration.Key()] = migration
+	save()
+
+	for {
+		switch migration.Step {
+		case ocm.MigrationCreate:
+			if findMachinePool(machinePools, migration.Replacement) == nil {
+				createReplacement(reporter, ocmClient, cluster, machinePoolID, replacement)
+			}
+			migration.Step = ocm.MigrationWait
+		case ocm.MigrationWait:
+			readyReplicas := ocm.ReadyReplicas(replacement)
+			reporter.Infof("Waiting for machine pool '%s' to have %d ready replicas",
+				migration.Replacement, readyReplicas)
+			waitForReplicas(reporter, ocmClient, cluster, migration.Replacement, func(replicas int) bool {
+				return replicas >= readyReplicas
+			})
+			migration.Step = ocm.MigrationScaleDown
+		case ocm.MigrationScaleDown:
+			if migration.Replicas == 0 {
+				migration.Step = ocm.MigrationDelete
+				break
+			}
+			next := migration.NextReplicas()
+			reporter.Infof("Scaling machine pool '%s' down to %d replicas", machinePoolID, next)
+			scaleDown(reporter, ocmClient, cluster, machinePool, next)
+			waitForReplicas(reporter, ocmClient, cluster, machinePoolID, func(replicas int) bool {
+				return replicas <= next
+			})
+			migration.Replicas = next
+		case ocm.MigrationDelete:
+			if machinePool != nil {
+				reporter.Debugf("Deleting machine pool '%s' on cluster '%s'", machinePoolID, clusterKey)
+				err = ocmClient.DeleteMachinePool(cluster.ID(), machinePoolID)
+				if err != nil {
+					reporter.Errorf("Failed to delete machine pool '%s' on cluster '%s': %s",
+						machinePoolID, clusterKey, err)
+					os.Exit(1)
+				}
+			}
+			delete(migrations, migration.Key())
+			save()
+			reporter.Infof("Machine pool '%s' was replaced by machine pool '%s' with instance type '%s'",
+				machinePoolID, migration.Replacement, instanceType)
+			return
+		default:
+			reporter.Errorf("Unknown step '%s' in the migration of machine pool '%s'",
+				migration.Step, machinePoolID)
+			os.Exit(1)
+		}
+		save()
+	}
+}
+
+// newMigration checks that the machine pool can be migrated to the instance type and returns
+// the initial state of its migration.
+func newMigration(reporter *rprtr.Object, logger *logrus.Logger, ocmClient *ocm.Client,
+	cluster *cmv1.Cluster, machinePools []*cmv1.MachinePool, machinePool *cmv1.MachinePool,
+	instanceType string) *ocm.MachinePoolMigration {
+	if machinePool == nil {
+		reporter.Errorf("Failed to get machine pool '%s' for cluster '%s'", args.machinePool, args.clusterKey)
+		os.Exit(1)
+	}
+	if machinePool.InstanceType() == instanceType {
+		reporter.Errorf("Machine pool '%s' already uses instance type '%s'", machinePool.ID(), instanceType)
+		os.Exit(1)
+	}
+	instanceTypeList, err := ocmClient.GetAvailableMachineTypes()
+	if err != nil {
+		reporter.Errorf("%s", err)
+		os.Exit(1)
+	}
+	_, err = ocm.ValidateMachineType(instanceType, instanceTypeList, cluster.MultiAZ())
+	if err != nil {
+		reporter.Errorf("Expected a valid machine type: %s", err)
+		os.Exit(1)
+	}
+
+	name := strings.TrimSpace(args.name)
+	if name == "" {
+		name = ocm.ReplacementMachinePoolName(machinePool.ID(), instanceType)
+	}
+	if !machinePoolKeyRE.MatchString(name) {
+		reporter.Errorf("Expected a valid name for the replacement machine pool, got '%s'", name)
+		os.Exit(1)
+	}
+	if findMachinePool(machinePools, name) != nil {
+		reporter.Errorf("Machine pool '%s' already exists, use '--name' to choose a different name "+
+			"for the replacement", name)
+		os.Exit(1)
+	}
+
+	// The replacement uses the same subnets, tags and spot options, so the migration can't start
+	// if they aren't known:
+	machinePoolsAWS, err := ocmClient.GetMachinePoolsAWS(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get AWS settings of machine pool '%s': %v", machinePool.ID(), err)
+		os.Exit(1)
+	}
+	awsConfig := machinePoolsAWS[machinePool.ID()]
+	if awsConfig == nil {
+		reporter.Errorf("Failed to get AWS settings of machine pool '%s'", machinePool.ID())
+		os.Exit(1)
+	}
+
+	// The replacement of a spot machine pool uses spot instances as well:
+	if awsConfig.Spot != nil {
+		regionalClient, err := aws.NewClient().
+			Logger(logger).
+			Region(cluster.Region().ID()).
+			Build()
+		if err != nil {
+			reporter.Errorf("Failed to create AWS client: %v", err)
+			os.Exit(1)
+		}
+		supported, err := regionalClient.SupportsSpotInstances(instanceType)
+		if err != nil {
+			reporter.Errorf("Failed to check if instance type '%s' supports spot instances: %v",
+				instanceType, err)
+			os.Exit(1)
+		}
+		if !supported {
+			reporter.Errorf("Machine pool '%s' uses spot instances, but instance type '%s' doesn't "+
+				"support them in region '%s'", machinePool.ID(), instanceType, cluster.Region().ID())
+			os.Exit(1)
+		}
+	}
+
+	// Replicas are spread evenly across the availability zones, so each step needs to remove the
+	// same number of replicas from each of them:
+	zones := len(machinePool.AvailabilityZones())
+	if zones == 0 {
+		zones = len(cluster.Nodes().AvailabilityZones())
+	}
+	if zones == 0 {
+		zones = 1
+	}
+	step := args.step
+	if step == 0 {
+		step = zones
+	}
+	if step%zones != 0 {
+		reporter.Errorf("Machine pools in %d availability zones need to be scaled down in "+
+			"multiples of %d replicas", zones, zones)
+		os.Exit(1)
+	}
+
+	replicas := machinePool.Replicas()
+	if machinePool.Autoscaling() != nil {
+		replicas, err = ocmClient.GetMachinePoolReplicas(cluster.ID(), machinePool.ID())
+		if err != nil {
+			reporter.Errorf("Failed to get replicas of machine pool '%s': %v", machinePool.ID(), err)
+			os.Exit(1)
+		}
+	}
+
+	return &ocm.MachinePoolMigration{
+		ClusterID:    cluster.ID(),
+		MachinePool:  machinePool.ID(),
+		Replacement:  name,
+		InstanceType: instanceType,
+		Step:         ocm.MigrationCreate,
+		Replicas:     replicas,
+		StepSize:     step,
+	}
+}
+
+// createReplacement creates the replacement machine pool with the AWS settings of the old one.
+func createReplacement(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster,
+	machinePoolID string, replacement *cmv1.MachinePool) {
+	machinePoolsAWS, err := ocmClient.GetMachinePoolsAWS(cluster.ID())
+	if err != nil {
+		reporter.Errorf("Failed to get AWS settings of machine pool '%s': %v", machinePoolID, err)
+		os.Exit(1)
+	}
+	// Without the AWS settings the replacement would lose the subnets, tags and spot options of
+	// the machine pool, for example spreading across all the zones instead of its subnets:
+	awsConfig := machinePoolsAWS[machinePoolID]
+	if awsConfig == nil {
+		reporter.Errorf("Failed to get AWS settings of machine pool '%s'", machinePoolID)
+		os.Exit(1)
+	}
+	reporter.Infof("Creating machine pool '%s' with instance type '%s'", replacement.ID(),
+		replacement.InstanceType())
+	_, err = ocmClient.CreateMachinePoolWithAWS(cluster.ID(), replacement,
+		ocm.ReplacementMachinePoolAWS(awsConfig))
+	if err != nil {
+		reporter.Errorf("Failed to add machine pool to cluster '%s': %v", args.clusterKey, err)
+		os.Exit(1)
+	}
+}
+
+// scaleDown sets the replicas of the machine pool, disabling autoscaling if it was enabled.
+func scaleDown(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster,
+	machinePool *cmv1.MachinePool, replicas int) {
+	reporter.Debugf("Updating machine pool '%s' on cluster '%s'", machinePool.ID(), args.clusterKey)
+	err := ocmClient.ScaleDownMachinePool(cluster.ID(), machinePool, replicas)
+	if err != nil {
+		reporter.Errorf("Failed to update machine pool '%s' on cluster '%s': %s",
+			machinePool.ID(), args.clusterKey, err)
+		os.Exit(1)
+	}
+}
+
+// waitForReplicas waits till the number of running replicas of the machine pool is accepted by
+// the 'ready' function, and exits if the timeout expires. The progress of the migration is saved
+// before waiting, so the same command resumes it.
+func waitForReplicas(reporter *rprtr.Object, ocmClient *ocm.Client, cluster *cmv1.Cluster,
+	machinePoolID string, ready func(int) bool) {
+	ok, err := ocmClient.WaitForMachinePool(cluster.ID(), machinePoolID, ready, args.timeout,
+		func(replicas int) {
+			reporter.Debugf("Machine pool '%s' has %d running replicas", machinePoolID, replicas)
+		})
+	if err != nil {
+		reporter.Errorf("Failed to get replicas of machine pool '%s': %v", machinePoolID, err)
+		os.Exit(1)
+	}
+	if !ok {
+		reporter.Errorf("Timed out waiting for the replicas of machine pool '%s', run the same "+
+			"command again to resume the migration", machinePoolID)
+		os.Exit(1)
+	}
+}
+
+// printPlan prints the steps of the migration, marking the ones that are already done.
+func printPlan(migration *ocm.MachinePoolMigration, replacement *cmv1.MachinePool) {
+	create := fmt.Sprintf("Create machine pool '%s' with instance type '%s'", migration.Replacement,
+		migration.InstanceType)
+	wait := fmt.Sprintf("Wait for the replicas of machine pool '%s' to be ready", migration.Replacement)
+	if replacement != nil {
+		if autoscaling, ok := replacement.GetAutoscaling(); ok {
+			create += fmt.Sprintf(" and %d-%d replicas", autoscaling.MinReplicas(), autoscaling.MaxReplicas())
+		} else {
+			create += fmt.Sprintf(" and %d replicas", replacement.Replicas())
+		}
+		wait = fmt.Sprintf("Wait for machine pool '%s' to have %d ready replicas", migration.Replacement,
+			ocm.ReadyReplicas(replacement))
+	}
+	scaleDown := fmt.Sprintf("Machine pool '%s' has no replicas to scale down", migration.MachinePool)
+	if steps := migration.ScaleDownSteps(); len(steps) > 0 {
+		replicas := []string{}
+		for _, step := range steps {
+			replicas = append(replicas, fmt.Sprintf("%d", step))
+		}
+		scaleDown = fmt.Sprintf("Scale machine pool '%s' down from %d to %s replicas", migration.MachinePool,
+			migration.Replicas, strings.Join(replicas, ", then "))
+	}
+	steps := []struct {
+		step        ocm.MigrationStep
+		description string
+	}{
+		{ocm.MigrationCreate, create},
+		{ocm.MigrationWait, wait},
+		{ocm.MigrationScaleDown, scaleDown},
+		{ocm.MigrationDelete, fmt.Sprintf("Delete machine pool '%s'", migration.MachinePool)},
+	}
+
+	fmt.Printf("Migration of machine pool '%s' to instance type '%s':\n", migration.MachinePool,
+		migration.InstanceType)
+	done := true
+	for i, item := range steps {
+		if item.step == migration.Step {
+			done = false
+		}
+		status := ""
+		if done {
+			status = " (done)"
+		}
+		fmt.Printf("  %d. %s%s\n", i+1, item.description, status)
+	}
+}
+
+func findMachinePool(machinePools []*cmv1.MachinePool, machinePoolID string) *cmv1.MachinePool {
+	for _, machinePool := range machinePools {
+		if machinePool.ID() == machinePoolID {
+			return machinePool
+		}
+	}
+	return nil
+}
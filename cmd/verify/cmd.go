@@ -19,8 +19,10 @@ package verify
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/openshift/rosa/cmd/verify/networkplan"
 	"github.com/openshift/rosa/cmd/verify/oc"
 	"github.com/openshift/rosa/cmd/verify/permissions"
+	"github.com/openshift/rosa/cmd/verify/privatelink"
 	"github.com/openshift/rosa/cmd/verify/quota"
 )
 
@@ -31,7 +33,9 @@ var Cmd = &cobra.Command{
 }
 
 func init() {
+	Cmd.AddCommand(networkplan.Cmd)
 	Cmd.AddCommand(oc.Cmd)
 	Cmd.AddCommand(permissions.Cmd)
+	Cmd.AddCommand(privatelink.Cmd)
 	Cmd.AddCommand(quota.Cmd)
 }
@@ -0,0 +1,119 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package networkplan
+
+import (
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/network"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	machineCIDR net.IPNet
+	serviceCIDR net.IPNet
+	podCIDR     net.IPNet
+	hostPrefix  int
+	multiAZ     bool
+	maxReplicas int
+}
+
+var Cmd = &cobra.Command{
+	Use:     "network-plan",
+	Aliases: []string{"networkplan"},
+	Short:   "Verify the network configuration of a cluster",
+	Long: "Verify that the machine, service and pod CIDRs and the host prefix of a cluster don't " +
+		"overlap and fit together, and display the capacity that they allow.",
+	Example: `  # Verify the default network configuration
+  rosa verify network-plan
+
+  # Verify a custom network configuration for a multi AZ cluster with up to 30 compute nodes
+  rosa verify network-plan --machine-cidr=10.0.0.0/22 --pod-cidr=10.128.0.0/18 \
+    --host-prefix=24 --multi-az --max-replicas=30`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	defaults := network.DefaultPlan()
+	flags.IPNetVar(
+		&args.machineCIDR,
+		"machine-cidr",
+		*defaults.MachineCIDR,
+		"Block of IP addresses used by OpenShift while installing the cluster.",
+	)
+	flags.IPNetVar(
+		&args.serviceCIDR,
+		"service-cidr",
+		*defaults.ServiceCIDR,
+		"Block of IP addresses for services.",
+	)
+	flags.IPNetVar(
+		&args.podCIDR,
+		"pod-cidr",
+		*defaults.PodCIDR,
+		"Block of IP addresses from which Pod IP addresses are allocated.",
+	)
+	flags.IntVar(
+		&args.hostPrefix,
+		"host-prefix",
+		defaults.HostPrefix,
+		"Subnet prefix length to assign to each individual node.",
+	)
+	flags.BoolVar(
+		&args.multiAZ,
+		"multi-az",
+		false,
+		"Verify the plan for a cluster deployed to multiple availability zones.",
+	)
+	flags.IntVar(
+		&args.maxReplicas,
+		"max-replicas",
+		0,
+		"Maximum number of compute nodes that the cluster will have, to check that the plan has room for them.",
+	)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+
+	result := network.ValidatePlan(network.Plan{
+		MachineCIDR: &args.machineCIDR,
+		ServiceCIDR: &args.serviceCIDR,
+		PodCIDR:     &args.podCIDR,
+		HostPrefix:  args.hostPrefix,
+		MultiAZ:     args.multiAZ,
+		MaxReplicas: args.maxReplicas,
+	})
+	if len(result.Errors) > 0 {
+		for _, message := range result.Errors {
+			reporter.Errorf("%s", message)
+		}
+		os.Exit(1)
+	}
+	for _, message := range result.Warnings {
+		reporter.Warnf("%s", message)
+	}
+	reporter.Infof("Network plan ok. It allows up to %d nodes, including control plane and infra nodes, "+
+		"%d pods per node and %d machine IPs per availability zone",
+		result.MaxNodes, result.PodsPerNode, result.MachineIPsPerZone)
+}
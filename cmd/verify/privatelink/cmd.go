@@ -0,0 +1,103 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privatelink
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa/pkg/arguments"
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/logging"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+var args struct {
+	subnetIDs []string
+}
+
+var Cmd = &cobra.Command{
+	Use:   "privatelink",
+	Short: "Verify that existing subnets are ready for a PrivateLink cluster",
+	Long: "Verify that the subnets are private, that their VPC has DNS resolution and hostnames " +
+		"enabled, that they have an egress path and that no private hosted zones associated to the " +
+		"VPC conflict with the zones of the cluster.",
+	Example: `  # Verify the subnets of a PrivateLink cluster
+  rosa verify privatelink --subnet-ids=subnet-1,subnet-2,subnet-3
+
+  # Verify subnets in a different region
+  rosa verify privatelink --subnet-ids=subnet-1 --region=us-west-2`,
+	Run: run,
+}
+
+func init() {
+	flags := Cmd.Flags()
+	flags.SortFlags = false
+
+	flags.StringSliceVar(
+		&args.subnetIDs,
+		"subnet-ids",
+		nil,
+		"The private subnet IDs that the cluster will be installed into. "+
+			"Format should be a comma-separated list.",
+	)
+	Cmd.MarkFlagRequired("subnet-ids")
+
+	arguments.AddRegionFlag(flags)
+	arguments.AddProfileFlag(flags)
+}
+
+func run(_ *cobra.Command, _ []string) {
+	reporter := rprtr.CreateReporterOrExit()
+	logger := logging.CreateLoggerOrExit(reporter)
+
+	region, err := aws.GetRegion(arguments.GetRegion())
+	if err != nil {
+		reporter.Errorf("Error getting region: %v", err)
+		os.Exit(1)
+	}
+
+	awsClient, err := aws.NewClient().
+		Logger(logger).
+		Region(region).
+		Build()
+	if err != nil {
+		reporter.Errorf("Error creating AWS client: %v", err)
+		os.Exit(1)
+	}
+
+	reporter.Infof("Verifying PrivateLink readiness of subnets %v...", args.subnetIDs)
+	subnetLayout, err := awsClient.GetSubnetLayout(args.subnetIDs)
+	if err != nil {
+		reporter.Errorf("Failed to get the layout of the subnets: %v", err)
+		os.Exit(1)
+	}
+	layout, err := awsClient.GetPrivateLinkLayout(subnetLayout)
+	if err != nil {
+		reporter.Errorf("Failed to get the PrivateLink layout of the subnets: %v", err)
+		os.Exit(1)
+	}
+	report := aws.ValidatePrivateLink(layout)
+	fmt.Print(report)
+	if !report.Passed() {
+		reporter.Errorf("Subnets aren't ready for a PrivateLink cluster")
+		os.Exit(1)
+	}
+	reporter.Infof("Subnets are ready for a PrivateLink cluster")
+}
@@ -35,8 +35,12 @@ import (
 	"github.com/aws/aws-sdk-go/service/ec2/ec2iface"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/kms/kmsiface"
 	"github.com/aws/aws-sdk-go/service/organizations"
 	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go/service/route53/route53iface"
 	"github.com/aws/aws-sdk-go/service/servicequotas"
 	"github.com/aws/aws-sdk-go/service/servicequotas/servicequotasiface"
 	"github.com/aws/aws-sdk-go/service/sts"
@@ -74,6 +78,24 @@ type Client interface {
 	ValidateQuota() (bool, error)
 	TagUserRegion(username string, region string) error
 	GetClusterRegionTagForUser(username string) (string, error)
+	GetRole(roleName string) (*iam.Role, error)
+	CreateRole(roleName string, trustPolicy string, permissionsBoundary string,
+		tagList map[string]string) (string, error)
+	UpdateTrustPolicy(roleName string, trustPolicy string) error
+	PutPermissionsBoundary(roleName string, permissionsBoundary string) error
+	GetRolePolicy(roleName string, policyName string) (string, error)
+	PutRolePolicy(roleName string, policyName string, policy string) error
+	TagRole(roleName string, tagList map[string]string) error
+	EnsureOpenIDConnectProvider(issuerURL string, thumbprint string, clientIDs []string) (string, bool, error)
+	DeleteOpenIDConnectProvider(issuerURL string) (bool, error)
+	CreateNetworkStack(stackName string, config NetworkConfig) (*Network, error)
+	GetNetworkStack(stackName string) (*Network, error)
+	GetAvailabilityZones() ([]string, error)
+	GetSubnetLayout(subnetIDs []string) (*SubnetLayout, error)
+	GetKMSKey(keyARN string) (*KMSKey, error)
+	GetPrivateLinkLayout(subnetLayout *SubnetLayout) (*PrivateLinkLayout, error)
+	SupportsSpotInstances(instanceType string) (bool, error)
+	GetInstanceTypeZones(instanceTypes []string) (map[string][]string, error)
 }
 
 // ClientBuilder contains the information and logic needed to build a new AWS client.
@@ -91,6 +113,8 @@ type awsClient struct {
 	stsClient           stsiface.STSAPI
 	cfClient            cloudformationiface.CloudFormationAPI
 	servicequotasClient servicequotasiface.ServiceQuotasAPI
+	kmsClient           kmsiface.KMSAPI
+	route53Client       route53iface.Route53API
 	awsSession          *session.Session
 	awsAccessKeys       *AccessKey
 }
@@ -108,6 +132,8 @@ func New(
 	stsClient stsiface.STSAPI,
 	cfClient cloudformationiface.CloudFormationAPI,
 	servicequotasClient servicequotasiface.ServiceQuotasAPI,
+	kmsClient kmsiface.KMSAPI,
+	route53Client route53iface.Route53API,
 	awsSession *session.Session,
 	awsAccessKeys *AccessKey,
 
@@ -120,6 +146,8 @@ func New(
 		stsClient,
 		cfClient,
 		servicequotasClient,
+		kmsClient,
+		route53Client,
 		awsSession,
 		awsAccessKeys,
 	}
@@ -247,6 +275,8 @@ func (b *ClientBuilder) Build() (Client, error) {
 		stsClient:           sts.New(sess),
 		cfClient:            cloudformation.New(sess),
 		servicequotasClient: servicequotas.New(sess),
+		kmsClient:           kms.New(sess),
+		route53Client:       route53.New(sess),
 		awsSession:          sess,
 	}
 
@@ -319,6 +349,16 @@ func (c *awsClient) GetCreator() (*Creator, error) {
 	}, nil
 }
 
+// Partition returns the AWS partition of the creator, for example 'aws-us-gov' in GovCloud. The
+// default 'aws' partition is returned if the ARN can't be parsed.
+func (c *Creator) Partition() string {
+	parsed, err := arn.Parse(c.ARN)
+	if err != nil {
+		return "aws"
+	}
+	return parsed.Partition
+}
+
 // Checks if given credentials are valid.
 func (c *awsClient) ValidateCredentials() (bool, bool, error) {
 	// Validate the AWS credentials by calling STS GetCallerIdentity
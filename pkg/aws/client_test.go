@@ -36,6 +36,8 @@ var _ = Describe("Client", func() {
 			mocks.NewMockSTSAPI(mockCtrl),
 			mockCfAPI,
 			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			mocks.NewMockKMSAPI(mockCtrl),
+			mocks.NewMockRoute53API(mockCtrl),
 			&session.Session{},
 			&aws.AccessKey{},
 		)
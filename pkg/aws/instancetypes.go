@@ -0,0 +1,112 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check the capabilities and the availability of the EC2
+// instance types in the region of the client.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// SupportsSpotInstances checks if the given instance type can be launched as a spot instance in
+// the region of the client. Instance types that aren't offered in the region don't support spot
+// instances either.
+func (c *awsClient) SupportsSpotInstances(instanceType string) (bool, error) {
+	output, err := c.ec2Client.DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+		InstanceTypes: []*string{aws.String(instanceType)},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "InvalidInstanceType" {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, info := range output.InstanceTypes {
+		for _, usageClass := range info.SupportedUsageClasses {
+			if aws.StringValue(usageClass) == ec2.UsageClassTypeSpot {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// GetInstanceTypeZones returns the availability zones of the region of the client where each of
+// the given instance types is offered, indexed by instance type and sorted. When no instance types
+// are given it returns the zones of all the instance types offered in the region.
+func (c *awsClient) GetInstanceTypeZones(instanceTypes []string) (map[string][]string, error) {
+	input := &ec2.DescribeInstanceTypeOfferingsInput{
+		LocationType: aws.String(ec2.LocationTypeAvailabilityZone),
+	}
+	if len(instanceTypes) > 0 {
+		input.Filters = []*ec2.Filter{{
+			Name:   aws.String("instance-type"),
+			Values: aws.StringSlice(instanceTypes),
+		}}
+	}
+	zones := map[string][]string{}
+	err := c.ec2Client.DescribeInstanceTypeOfferingsPages(input,
+		func(output *ec2.DescribeInstanceTypeOfferingsOutput, _ bool) bool {
+			for _, offering := range output.InstanceTypeOfferings {
+				instanceType := aws.StringValue(offering.InstanceType)
+				zones[instanceType] = append(zones[instanceType], aws.StringValue(offering.Location))
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	for _, list := range zones {
+		sort.Strings(list)
+	}
+	return zones, nil
+}
+
+// ValidateInstanceTypeZones checks that the instance type is offered in all the given availability
+// zones. When the zones aren't known yet, because the installer chooses them, it checks that the
+// instance type is offered in enough zones of the region for a single or multi AZ cluster.
+func ValidateInstanceTypeZones(instanceType string, offered []string, zones []string, multiAZ bool) error {
+	if len(zones) > 0 {
+		missing := []string{}
+		for _, zone := range zones {
+			if !contains(offered, zone) {
+				missing = append(missing, zone)
+			}
+		}
+		if len(missing) > 0 {
+			return fmt.Errorf("Instance type '%s' isn't offered in availability zones %s",
+				instanceType, strings.Join(missing, ", "))
+		}
+		return nil
+	}
+	required := 1
+	if multiAZ {
+		required = 3
+	}
+	if len(offered) < required {
+		return fmt.Errorf("Instance type '%s' is offered in %d availability zones of the region, "+
+			"but the cluster needs %d", instanceType, len(offered), required)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Instance types", func() {
+	var (
+		client     aws.Client
+		mockCtrl   *gomock.Controller
+		mockEC2API *mocks.MockEC2API
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockEC2API = mocks.NewMockEC2API(mockCtrl)
+		client = aws.New(
+			logrus.New(),
+			mocks.NewMockIAMAPI(mockCtrl),
+			mockEC2API,
+			mocks.NewMockOrganizationsAPI(mockCtrl),
+			mocks.NewMockSTSAPI(mockCtrl),
+			mocks.NewMockCloudFormationAPI(mockCtrl),
+			mocks.NewMockServiceQuotasAPI(mockCtrl),
+			mocks.NewMockKMSAPI(mockCtrl),
+			mocks.NewMockRoute53API(mockCtrl),
+			&session.Session{},
+			&aws.AccessKey{},
+		)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	Context("SupportsSpotInstances", func() {
+		usageClasses := func(classes ...string) *ec2.DescribeInstanceTypesOutput {
+			return &ec2.DescribeInstanceTypesOutput{
+				InstanceTypes: []*ec2.InstanceTypeInfo{{
+					InstanceType:          awssdk.String("m5.xlarge"),
+					SupportedUsageClasses: awssdk.StringSlice(classes),
+				}},
+			}
+		}
+
+		It("accepts instance types that support spot", func() {
+			mockEC2API.EXPECT().DescribeInstanceTypes(&ec2.DescribeInstanceTypesInput{
+				InstanceTypes: awssdk.StringSlice([]string{"m5.xlarge"}),
+			}).Return(usageClasses(ec2.UsageClassTypeOnDemand, ec2.UsageClassTypeSpot), nil)
+			Expect(client.SupportsSpotInstances("m5.xlarge")).To(BeTrue())
+		})
+
+		It("rejects instance types that are only on demand", func() {
+			mockEC2API.EXPECT().DescribeInstanceTypes(gomock.Any()).
+				Return(usageClasses(ec2.UsageClassTypeOnDemand), nil)
+			Expect(client.SupportsSpotInstances("m5.xlarge")).To(BeFalse())
+		})
+
+		It("rejects instance types that aren't offered in the region", func() {
+			mockEC2API.EXPECT().DescribeInstanceTypes(gomock.Any()).
+				Return(nil, awserr.New("InvalidInstanceType", "not offered", nil))
+			Expect(client.SupportsSpotInstances("x2gd.medium")).To(BeFalse())
+		})
+	})
+
+	Context("GetInstanceTypeZones", func() {
+		offering := func(instanceType string, zone string) *ec2.InstanceTypeOffering {
+			return &ec2.InstanceTypeOffering{
+				InstanceType: awssdk.String(instanceType),
+				Location:     awssdk.String(zone),
+				LocationType: awssdk.String(ec2.LocationTypeAvailabilityZone),
+			}
+		}
+
+		It("groups the zones by instance type", func() {
+			mockEC2API.EXPECT().DescribeInstanceTypeOfferingsPages(&ec2.DescribeInstanceTypeOfferingsInput{
+				LocationType: awssdk.String(ec2.LocationTypeAvailabilityZone),
+				Filters: []*ec2.Filter{{
+					Name:   awssdk.String("instance-type"),
+					Values: awssdk.StringSlice([]string{"m5.xlarge", "r5.xlarge"}),
+				}},
+			}, gomock.Any()).DoAndReturn(func(_ *ec2.DescribeInstanceTypeOfferingsInput,
+				fn func(*ec2.DescribeInstanceTypeOfferingsOutput, bool) bool) error {
+				fn(&ec2.DescribeInstanceTypeOfferingsOutput{
+					InstanceTypeOfferings: []*ec2.InstanceTypeOffering{
+						offering("m5.xlarge", "us-east-1b"),
+						offering("m5.xlarge", "us-east-1a"),
+					},
+				}, false)
+				fn(&ec2.DescribeInstanceTypeOfferingsOutput{
+					InstanceTypeOfferings: []*ec2.InstanceTypeOffering{
+						offering("r5.xlarge", "us-east-1a"),
+					},
+				}, true)
+				return nil
+			})
+			zones, err := client.GetInstanceTypeZones([]string{"m5.xlarge", "r5.xlarge"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(zones).To(Equal(map[string][]string{
+				"m5.xlarge": {"us-east-1a", "us-east-1b"},
+				"r5.xlarge": {"us-east-1a"},
+			}))
+		})
+	})
+})
+
+var _ = Describe("ValidateInstanceTypeZones", func() {
+	offered := []string{"us-east-1a", "us-east-1b"}
+
+	It("checks the zones of the cluster", func() {
+		Expect(aws.ValidateInstanceTypeZones("m5.xlarge", offered, []string{"us-east-1a"}, false)).To(Succeed())
+		Expect(aws.ValidateInstanceTypeZones("m5.xlarge", offered,
+			[]string{"us-east-1a", "us-east-1c", "us-east-1d"}, true)).To(MatchError(
+			"Instance type 'm5.xlarge' isn't offered in availability zones us-east-1c, us-east-1d"))
+	})
+
+	It("checks the number of zones when they aren't known", func() {
+		Expect(aws.ValidateInstanceTypeZones("m5.xlarge", offered, nil, false)).To(Succeed())
+		Expect(aws.ValidateInstanceTypeZones("m5.xlarge", offered, nil, true)).To(MatchError(
+			"Instance type 'm5.xlarge' is offered in 2 availability zones of the region, but the cluster needs 3"))
+	})
+})
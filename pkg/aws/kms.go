@@ -0,0 +1,237 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check that a customer managed KMS key can be used to
+// encrypt the etcd data and the EBS volumes of a cluster.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/service/kms"
+)
+
+// KMSKeyActions are the actions that the key policy has to allow to the roles of the cluster, so
+// that they can encrypt volumes and create the grants that EC2 uses to attach them.
+var KMSKeyActions = []string{
+	"kms:Decrypt",
+	"kms:Encrypt",
+	"kms:GenerateDataKey",
+	"kms:GenerateDataKeyWithoutPlaintext",
+	"kms:ReEncryptFrom",
+	"kms:ReEncryptTo",
+	"kms:DescribeKey",
+	"kms:CreateGrant",
+}
+
+// KMSKey contains the details of a KMS key needed to validate it.
+type KMSKey struct {
+	ARN      string
+	Enabled  bool
+	KeySpec  string
+	KeyUsage string
+
+	// Policy is the default key policy, as a JSON document.
+	Policy string
+}
+
+// GetKMSKey returns the description and the key policy of the given key.
+func (c *awsClient) GetKMSKey(keyARN string) (*KMSKey, error) {
+	output, err := c.kmsClient.DescribeKey(&kms.DescribeKeyInput{
+		KeyId: aws.String(keyARN),
+	})
+	if err != nil {
+		return nil, err
+	}
+	metadata := output.KeyMetadata
+	policy, err := c.kmsClient.GetKeyPolicy(&kms.GetKeyPolicyInput{
+		KeyId:      metadata.KeyId,
+		PolicyName: aws.String("default"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &KMSKey{
+		ARN:      aws.StringValue(metadata.Arn),
+		Enabled:  aws.BoolValue(metadata.Enabled),
+		KeySpec:  aws.StringValue(metadata.CustomerMasterKeySpec),
+		KeyUsage: aws.StringValue(metadata.KeyUsage),
+		Policy:   aws.StringValue(policy.Policy),
+	}, nil
+}
+
+// ValidateKMSKeyARN checks that the given string is the ARN of a KMS key in the given region. The
+// region isn't checked if it is empty.
+func ValidateKMSKeyARN(keyARN string, region string) error {
+	parsed, err := arn.Parse(keyARN)
+	if err != nil || parsed.Service != "kms" || !strings.HasPrefix(parsed.Resource, "key/") {
+		return fmt.Errorf("'%s' isn't a valid KMS key ARN", keyARN)
+	}
+	if region != "" && parsed.Region != region {
+		return fmt.Errorf("KMS key '%s' is in region '%s', but the cluster is in region '%s'",
+			keyARN, parsed.Region, region)
+	}
+	return nil
+}
+
+// ValidateKMSKey checks that the key is enabled, symmetric and usable for encryption, and that
+// its key policy allows the given principals to use it. A principal is also considered allowed
+// when the policy delegates the permissions of the key to IAM, granting them to the root user
+// of its account, because then they depend on the IAM policies of the principal.
+func ValidateKMSKey(key *KMSKey, principals []string) []string {
+	failures := []string{}
+	if !key.Enabled {
+		failures = append(failures, "key isn't enabled")
+	}
+	if key.KeySpec != kms.CustomerMasterKeySpecSymmetricDefault {
+		failures = append(failures, fmt.Sprintf("key spec is '%s', only symmetric keys are supported",
+			key.KeySpec))
+	}
+	if key.KeyUsage != kms.KeyUsageTypeEncryptDecrypt {
+		failures = append(failures, fmt.Sprintf("key usage is '%s', expected '%s'",
+			key.KeyUsage, kms.KeyUsageTypeEncryptDecrypt))
+	}
+
+	statements, err := parseKeyPolicy(key.Policy)
+	if err != nil {
+		return append(failures, fmt.Sprintf("failed to parse key policy: %v", err))
+	}
+	for _, principal := range principals {
+		missing := []string{}
+		for _, action := range KMSKeyActions {
+			if !policyAllows(statements, principal, action) {
+				missing = append(missing, action)
+			}
+		}
+		if len(missing) > 0 {
+			failures = append(failures, fmt.Sprintf("key policy doesn't allow '%s' to use %s",
+				principal, strings.Join(missing, ", ")))
+		}
+	}
+	return failures
+}
+
+// keyPolicyStatement is a statement of a key policy. The principals and actions are normalized
+// to lists, as the policy language accepts both single strings and lists.
+type keyPolicyStatement struct {
+	Effect     string
+	Principals []string
+	Actions    []string
+}
+
+func parseKeyPolicy(policy string) ([]keyPolicyStatement, error) {
+	document := struct {
+		Statement []struct {
+			Effect    string          `json:"Effect"`
+			Principal json.RawMessage `json:"Principal"`
+			Action    json.RawMessage `json:"Action"`
+		} `json:"Statement"`
+	}{}
+	err := json.Unmarshal([]byte(policy), &document)
+	if err != nil {
+		return nil, err
+	}
+	statements := []keyPolicyStatement{}
+	for _, raw := range document.Statement {
+		statement := keyPolicyStatement{
+			Effect: raw.Effect,
+		}
+		statement.Actions, err = stringOrList(raw.Action)
+		if err != nil {
+			return nil, err
+		}
+		// The principal is either "*" or an object with the principals of each type:
+		if strings.TrimSpace(string(raw.Principal)) == `"*"` {
+			statement.Principals = []string{"*"}
+		} else if len(raw.Principal) > 0 {
+			principal := struct {
+				AWS json.RawMessage `json:"AWS"`
+			}{}
+			err = json.Unmarshal(raw.Principal, &principal)
+			if err != nil {
+				return nil, err
+			}
+			statement.Principals, err = stringOrList(principal.AWS)
+			if err != nil {
+				return nil, err
+			}
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+func stringOrList(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var value string
+	if json.Unmarshal(raw, &value) == nil {
+		return []string{value}, nil
+	}
+	var list []string
+	err := json.Unmarshal(raw, &list)
+	return list, err
+}
+
+// policyAllows returns true if an allow statement, and no deny statement, matches the principal
+// and action. Conditions aren't evaluated.
+func policyAllows(statements []keyPolicyStatement, principal string, action string) bool {
+	allowed := false
+	for _, statement := range statements {
+		if !matchesPrincipal(statement.Principals, principal) || !matchesAction(statement.Actions, action) {
+			continue
+		}
+		switch statement.Effect {
+		case "Deny":
+			return false
+		case "Allow":
+			allowed = true
+		}
+	}
+	return allowed
+}
+
+func matchesPrincipal(principals []string, principal string) bool {
+	// The root user of the account can be given either as an ARN or as the account ID:
+	rootARN, accountID := "", ""
+	if parsed, err := arn.Parse(principal); err == nil {
+		rootARN = fmt.Sprintf("arn:%s:iam::%s:root", parsed.Partition, parsed.AccountID)
+		accountID = parsed.AccountID
+	}
+	for _, candidate := range principals {
+		if candidate == "*" || candidate == principal || candidate == rootARN || candidate == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAction(actions []string, action string) bool {
+	for _, pattern := range actions {
+		expression := "(?i)^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+		if regexp.MustCompile(expression).MatchString(action) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("KMS keys", func() {
+	const (
+		keyARN       = "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+		installerARN = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+		workerARN    = "arn:aws:iam::123456789012:role/ManagedOpenShift-Worker-Role"
+	)
+
+	key := func(policy string) *aws.KMSKey {
+		return &aws.KMSKey{
+			ARN:      keyARN,
+			Enabled:  true,
+			KeySpec:  kms.CustomerMasterKeySpecSymmetricDefault,
+			KeyUsage: kms.KeyUsageTypeEncryptDecrypt,
+			Policy:   policy,
+		}
+	}
+
+	Context("ValidateKMSKeyARN", func() {
+		It("accepts keys in the region of the cluster", func() {
+			Expect(aws.ValidateKMSKeyARN(keyARN, "us-east-1")).To(Succeed())
+		})
+
+		It("rejects keys in other regions and other ARNs", func() {
+			Expect(aws.ValidateKMSKeyARN(keyARN, "eu-west-1")).To(MatchError(ContainSubstring(
+				"is in region 'us-east-1', but the cluster is in region 'eu-west-1'")))
+			Expect(aws.ValidateKMSKeyARN(installerARN, "us-east-1")).To(MatchError(ContainSubstring(
+				"isn't a valid KMS key ARN")))
+		})
+
+		It("only checks the format when the region isn't known", func() {
+			Expect(aws.ValidateKMSKeyARN(keyARN, "")).To(Succeed())
+			Expect(aws.ValidateKMSKeyARN(installerARN, "")).To(MatchError(ContainSubstring(
+				"isn't a valid KMS key ARN")))
+		})
+	})
+
+	Context("ValidateKMSKey", func() {
+		It("accepts keys that delegate to IAM", func() {
+			failures := aws.ValidateKMSKey(key(`{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"AWS": "arn:aws:iam::123456789012:root"},
+					"Action": "kms:*",
+					"Resource": "*"
+				}]
+			}`), []string{installerARN, workerARN})
+			Expect(failures).To(BeEmpty())
+		})
+
+		It("checks the actions granted to each role", func() {
+			failures := aws.ValidateKMSKey(key(`{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": {"AWS": ["`+installerARN+`", "`+workerARN+`"]},
+					"Action": ["kms:Encrypt", "kms:Decrypt", "kms:ReEncrypt*", "kms:GenerateDataKey*",
+						"kms:DescribeKey"]
+				}, {
+					"Effect": "Allow",
+					"Principal": {"AWS": "`+installerARN+`"},
+					"Action": "kms:CreateGrant"
+				}]
+			}`), []string{installerARN, workerARN})
+			Expect(failures).To(Equal([]string{
+				"key policy doesn't allow '" + workerARN + "' to use kms:CreateGrant",
+			}))
+		})
+
+		It("honors deny statements", func() {
+			failures := aws.ValidateKMSKey(key(`{
+				"Statement": [{
+					"Effect": "Allow",
+					"Principal": "*",
+					"Action": "kms:*"
+				}, {
+					"Effect": "Deny",
+					"Principal": {"AWS": "`+workerARN+`"},
+					"Action": "kms:Decrypt"
+				}]
+			}`), []string{installerARN, workerARN})
+			Expect(failures).To(Equal([]string{
+				"key policy doesn't allow '" + workerARN + "' to use kms:Decrypt",
+			}))
+		})
+
+		It("rejects disabled and asymmetric keys", func() {
+			disabled := key(`{"Statement": []}`)
+			disabled.Enabled = false
+			disabled.KeySpec = kms.CustomerMasterKeySpecRsa2048
+			Expect(aws.ValidateKMSKey(disabled, nil)).To(Equal([]string{
+				"key isn't enabled",
+				"key spec is 'RSA_2048', only symmetric keys are supported",
+			}))
+		})
+	})
+
+	Context("GetKMSKey", func() {
+		var (
+			client     aws.Client
+			mockCtrl   *gomock.Controller
+			mockKMSAPI *mocks.MockKMSAPI
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockKMSAPI = mocks.NewMockKMSAPI(mockCtrl)
+			client = aws.New(
+				logrus.New(),
+				mocks.NewMockIAMAPI(mockCtrl),
+				mocks.NewMockEC2API(mockCtrl),
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mocks.NewMockSTSAPI(mockCtrl),
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				mockKMSAPI,
+				mocks.NewMockRoute53API(mockCtrl),
+				&session.Session{},
+				&aws.AccessKey{},
+			)
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("returns the key metadata and its default policy", func() {
+			mockKMSAPI.EXPECT().DescribeKey(&kms.DescribeKeyInput{
+				KeyId: awssdk.String(keyARN),
+			}).Return(&kms.DescribeKeyOutput{
+				KeyMetadata: &kms.KeyMetadata{
+					Arn:                   awssdk.String(keyARN),
+					KeyId:                 awssdk.String("1234abcd-12ab-34cd-56ef-1234567890ab"),
+					Enabled:               awssdk.Bool(true),
+					CustomerMasterKeySpec: awssdk.String(kms.CustomerMasterKeySpecSymmetricDefault),
+					KeyUsage:              awssdk.String(kms.KeyUsageTypeEncryptDecrypt),
+				},
+			}, nil)
+			mockKMSAPI.EXPECT().GetKeyPolicy(&kms.GetKeyPolicyInput{
+				KeyId:      awssdk.String("1234abcd-12ab-34cd-56ef-1234567890ab"),
+				PolicyName: awssdk.String("default"),
+			}).Return(&kms.GetKeyPolicyOutput{
+				Policy: awssdk.String(`{"Statement": []}`),
+			}, nil)
+
+			result, err := client.GetKMSKey(keyARN)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(key(`{"Statement": []}`)))
+		})
+	})
+})
@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to create the VPC and subnets that clusters can be
+// installed into with '--subnet-ids'.
+
+package aws
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	"github.com/openshift/rosa/assets"
+)
+
+const networkTemplatePath = "templates/cloudformation/vpc_rosaNetwork.json"
+
+// NetworkConfig describes the layout of the network created by CreateNetworkStack.
+type NetworkConfig struct {
+	// VPCCIDR is the CIDR block of the VPC, between /16 and /24. It is split in six subnets of
+	// the same size, a private and a public one for each of the possible availability zones.
+	VPCCIDR string
+
+	// AvailabilityZones contains one zone for single AZ networks and three for multi AZ ones.
+	AvailabilityZones []string
+
+	// PrivateOnly skips the public subnets, the internet gateway and the NAT gateways, as
+	// needed by PrivateLink clusters. Egress then has to be provided by other means.
+	PrivateOnly bool
+
+	// Tags are applied to the stack, which propagates them to the resources it creates.
+	Tags map[string]string
+}
+
+// Network contains the identifiers of the resources created by CreateNetworkStack.
+type Network struct {
+	VPCID            string
+	PrivateSubnetIDs []string
+	PublicSubnetIDs  []string
+}
+
+// SubnetIDs returns the private subnets followed by the public ones, as expected by the
+// '--subnet-ids' option of 'rosa create cluster'.
+func (n *Network) SubnetIDs() []string {
+	subnetIDs := append([]string{}, n.PrivateSubnetIDs...)
+	return append(subnetIDs, n.PublicSubnetIDs...)
+}
+
+// NetworkStackParameters validates the given configuration and returns the parameters of the
+// network CloudFormation template.
+func NetworkStackParameters(config NetworkConfig) ([]*cloudformation.Parameter, error) {
+	_, vpcNet, err := net.ParseCIDR(config.VPCCIDR)
+	if err != nil {
+		return nil, fmt.Errorf("VPC CIDR '%s' isn't valid: %v", config.VPCCIDR, err)
+	}
+	ones, bits := vpcNet.Mask.Size()
+	if bits != 32 || ones < 16 || ones > 24 {
+		return nil, fmt.Errorf("VPC CIDR '%s' must be an IPv4 block between /16 and /24", config.VPCCIDR)
+	}
+	if len(config.AvailabilityZones) != 1 && len(config.AvailabilityZones) != 3 {
+		return nil, fmt.Errorf("Expected 1 or 3 availability zones, got %d", len(config.AvailabilityZones))
+	}
+
+	values := map[string]string{
+		"VpcCidr": vpcNet.String(),
+		// Six subnets need three more bits than the VPC:
+		"SubnetBits":    strconv.Itoa(bits - ones - 3),
+		"PublicSubnets": strconv.FormatBool(!config.PrivateOnly),
+	}
+	for i, zone := range config.AvailabilityZones {
+		values[fmt.Sprintf("AvailabilityZone%d", i+1)] = zone
+	}
+
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parameters := []*cloudformation.Parameter{}
+	for _, key := range keys {
+		parameters = append(parameters, &cloudformation.Parameter{
+			ParameterKey:   aws.String(key),
+			ParameterValue: aws.String(values[key]),
+		})
+	}
+	return parameters, nil
+}
+
+// CreateNetworkStack deploys the network CloudFormation template with the given name and
+// configuration, waits till it is complete and returns the identifiers of the VPC and subnets.
+func (c *awsClient) CreateNetworkStack(stackName string, config NetworkConfig) (*Network, error) {
+	parameters, err := NetworkStackParameters(config)
+	if err != nil {
+		return nil, err
+	}
+	template, err := assets.Asset(networkTemplatePath)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read cloudformation template: %s", err)
+	}
+
+	tags := []*cloudformation.Tag{}
+	keys := []string{}
+	for key := range config.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tags = append(tags, &cloudformation.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(config.Tags[key]),
+		})
+	}
+
+	_, err = c.cfClient.CreateStack(&cloudformation.CreateStackInput{
+		StackName:    aws.String(stackName),
+		TemplateBody: aws.String(string(template)),
+		Parameters:   parameters,
+		Tags:         tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger.Debugf("Waiting for stack '%s' to be created", stackName)
+	err = c.cfClient.WaitUntilStackCreateComplete(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Stack '%s' failed to create, check its events for details: %v", stackName, err)
+	}
+
+	return c.GetNetworkStack(stackName)
+}
+
+// GetNetworkStack returns the identifiers of the VPC and subnets created by the network stack
+// with the given name.
+func (c *awsClient) GetNetworkStack(stackName string) (*Network, error) {
+	output, err := c.cfClient.DescribeStacks(&cloudformation.DescribeStacksInput{
+		StackName: aws.String(stackName),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(output.Stacks) == 0 {
+		return nil, fmt.Errorf("Stack '%s' doesn't exist", stackName)
+	}
+
+	outputs := map[string]string{}
+	for _, stackOutput := range output.Stacks[0].Outputs {
+		outputs[aws.StringValue(stackOutput.OutputKey)] = aws.StringValue(stackOutput.OutputValue)
+	}
+	network := &Network{
+		VPCID: outputs["VpcId"],
+	}
+	for i := 1; i <= 3; i++ {
+		if subnetID, ok := outputs[fmt.Sprintf("PrivateSubnet%d", i)]; ok {
+			network.PrivateSubnetIDs = append(network.PrivateSubnetIDs, subnetID)
+		}
+		if subnetID, ok := outputs[fmt.Sprintf("PublicSubnet%d", i)]; ok {
+			network.PublicSubnetIDs = append(network.PublicSubnetIDs, subnetID)
+		}
+	}
+	if network.VPCID == "" || len(network.PrivateSubnetIDs) == 0 {
+		return nil, fmt.Errorf("Stack '%s' isn't a network stack created by rosa", stackName)
+	}
+	return network, nil
+}
+
+// GetAvailabilityZones returns the names of the availability zones of the region that don't
+// require opting in, which excludes local zones.
+func (c *awsClient) GetAvailabilityZones() ([]string, error) {
+	output, err := c.ec2Client.DescribeAvailabilityZones(&ec2.DescribeAvailabilityZonesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("state"),
+				Values: aws.StringSlice([]string{ec2.AvailabilityZoneStateAvailable}),
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	zones := []string{}
+	for _, zone := range output.AvailabilityZones {
+		if aws.StringValue(zone.OptInStatus) != ec2.AvailabilityZoneOptInStatusOptInNotRequired {
+			continue
+		}
+		zones = append(zones, aws.StringValue(zone.ZoneName))
+	}
+	sort.Strings(zones)
+	return zones, nil
+}
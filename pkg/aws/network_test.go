@@ -0,0 +1,79 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudformation"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+var _ = Describe("Network", func() {
+	parameterValues := func(parameters []*cloudformation.Parameter) map[string]string {
+		values := map[string]string{}
+		for _, parameter := range parameters {
+			values[awssdk.StringValue(parameter.ParameterKey)] = awssdk.StringValue(parameter.ParameterValue)
+		}
+		return values
+	}
+
+	Context("NetworkStackParameters", func() {
+		It("builds the parameters of a single AZ network", func() {
+			parameters, err := aws.NetworkStackParameters(aws.NetworkConfig{
+				VPCCIDR:           "10.0.0.0/16",
+				AvailabilityZones: []string{"us-east-1a"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parameterValues(parameters)).To(Equal(map[string]string{
+				"VpcCidr":           "10.0.0.0/16",
+				"SubnetBits":        "13",
+				"PublicSubnets":     "true",
+				"AvailabilityZone1": "us-east-1a",
+			}))
+		})
+
+		It("builds the parameters of a multi AZ private network", func() {
+			parameters, err := aws.NetworkStackParameters(aws.NetworkConfig{
+				VPCCIDR:           "10.1.2.0/24",
+				AvailabilityZones: []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+				PrivateOnly:       true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(parameterValues(parameters)).To(Equal(map[string]string{
+				"VpcCidr":           "10.1.2.0/24",
+				"SubnetBits":        "5",
+				"PublicSubnets":     "false",
+				"AvailabilityZone1": "us-east-1a",
+				"AvailabilityZone2": "us-east-1b",
+				"AvailabilityZone3": "us-east-1c",
+			}))
+		})
+
+		It("fails if the VPC CIDR is too small", func() {
+			_, err := aws.NetworkStackParameters(aws.NetworkConfig{
+				VPCCIDR:           "10.0.0.0/25",
+				AvailabilityZones: []string{"us-east-1a"},
+			})
+			Expect(err).To(MatchError(ContainSubstring("between /16 and /24")))
+		})
+
+		It("fails if the number of availability zones isn't 1 or 3", func() {
+			_, err := aws.NetworkStackParameters(aws.NetworkConfig{
+				VPCCIDR:           "10.0.0.0/16",
+				AvailabilityZones: []string{"us-east-1a", "us-east-1b"},
+			})
+			Expect(err).To(MatchError("Expected 1 or 3 availability zones, got 2"))
+		})
+	})
+
+	Context("Network", func() {
+		It("returns the private subnets before the public ones", func() {
+			network := &aws.Network{
+				PrivateSubnetIDs: []string{"subnet-1", "subnet-2"},
+				PublicSubnetIDs:  []string{"subnet-3", "subnet-4"},
+			}
+			Expect(network.SubnetIDs()).To(Equal([]string{"subnet-1", "subnet-2", "subnet-3", "subnet-4"}))
+		})
+	})
+})
@@ -0,0 +1,211 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to manage the IAM OpenID Connect providers that allow
+// the operators of STS clusters to assume their roles.
+
+package aws
+
+import (
+	"crypto/sha1" // #nosec G505
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+)
+
+// OIDCClientIDs are the audiences of the tokens that the operators present to AWS STS.
+var OIDCClientIDs = []string{"openshift", "sts.amazonaws.com"}
+
+// OIDCProviderConfig contains the details of an OIDC issuer needed to register it in IAM.
+type OIDCProviderConfig struct {
+	IssuerURL  string
+	JWKSURL    string
+	Thumbprint string
+}
+
+// FetchOIDCProviderConfig reads the discovery document and the keys of the given HTTPS issuer,
+// and computes the thumbprint of the certificate that signs the server of the keys, which is
+// the one that IAM checks.
+func FetchOIDCProviderConfig(httpClient *http.Client, issuerURL string) (*OIDCProviderConfig, error) {
+	parsed, err := url.Parse(issuerURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("Issuer URL '%s' isn't a valid HTTPS URL", issuerURL)
+	}
+	issuer := strings.TrimSuffix(issuerURL, "/")
+
+	discovery := struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}{}
+	_, err = fetchJSON(httpClient, issuer+"/.well-known/openid-configuration", &discovery)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("Discovery document declares issuer '%s', expected '%s'",
+			discovery.Issuer, issuer)
+	}
+	if discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("Discovery document of issuer '%s' doesn't contain a JWKS URI", issuer)
+	}
+
+	jwks := struct {
+		Keys []json.RawMessage `json:"keys"`
+	}{}
+	state, err := fetchJSON(httpClient, discovery.JWKSURI, &jwks)
+	if err != nil {
+		return nil, err
+	}
+	if len(jwks.Keys) == 0 {
+		return nil, fmt.Errorf("JWKS of issuer '%s' doesn't contain any key", issuer)
+	}
+	if state == nil || len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("JWKS of issuer '%s' isn't served over TLS", issuer)
+	}
+
+	// IAM expects the thumbprint of the top certificate of the chain sent by the server:
+	certificate := state.PeerCertificates[len(state.PeerCertificates)-1]
+	// #nosec G401
+	thumbprint := fmt.Sprintf("%x", sha1.Sum(certificate.Raw))
+
+	return &OIDCProviderConfig{
+		IssuerURL:  issuer,
+		JWKSURL:    discovery.JWKSURI,
+		Thumbprint: thumbprint,
+	}, nil
+}
+
+func fetchJSON(httpClient *http.Client, address string, target interface{}) (*tls.ConnectionState, error) {
+	response, err := httpClient.Get(address)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch '%s': %v", address, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Failed to fetch '%s': unexpected status '%s'", address, response.Status)
+	}
+	err = json.NewDecoder(response.Body).Decode(target)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse '%s': %v", address, err)
+	}
+	return response.TLS, nil
+}
+
+// EnsureOpenIDConnectProvider creates the IAM OpenID Connect provider of the given issuer, or
+// updates the thumbprint and client IDs of the existing one. It returns the ARN of the provider
+// and whether it was created.
+func (c *awsClient) EnsureOpenIDConnectProvider(issuerURL string, thumbprint string,
+	clientIDs []string) (string, bool, error) {
+	providerARN, err := c.findOpenIDConnectProvider(issuerURL)
+	if err != nil {
+		return "", false, err
+	}
+
+	if providerARN == "" {
+		output, err := c.iamClient.CreateOpenIDConnectProvider(&iam.CreateOpenIDConnectProviderInput{
+			Url:            aws.String(issuerURL),
+			ClientIDList:   aws.StringSlice(clientIDs),
+			ThumbprintList: aws.StringSlice([]string{thumbprint}),
+		})
+		if err != nil {
+			return "", false, err
+		}
+		return aws.StringValue(output.OpenIDConnectProviderArn), true, nil
+	}
+
+	provider, err := c.iamClient.GetOpenIDConnectProvider(&iam.GetOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+	})
+	if err != nil {
+		return "", false, err
+	}
+	if !contains(aws.StringValueSlice(provider.ThumbprintList), thumbprint) {
+		c.logger.Debugf("Updating thumbprint of OIDC provider '%s'", providerARN)
+		_, err = c.iamClient.UpdateOpenIDConnectProviderThumbprint(
+			&iam.UpdateOpenIDConnectProviderThumbprintInput{
+				OpenIDConnectProviderArn: aws.String(providerARN),
+				ThumbprintList:           aws.StringSlice([]string{thumbprint}),
+			})
+		if err != nil {
+			return "", false, err
+		}
+	}
+	for _, clientID := range clientIDs {
+		if contains(aws.StringValueSlice(provider.ClientIDList), clientID) {
+			continue
+		}
+		c.logger.Debugf("Adding client ID '%s' to OIDC provider '%s'", clientID, providerARN)
+		_, err = c.iamClient.AddClientIDToOpenIDConnectProvider(&iam.AddClientIDToOpenIDConnectProviderInput{
+			OpenIDConnectProviderArn: aws.String(providerARN),
+			ClientID:                 aws.String(clientID),
+		})
+		if err != nil {
+			return "", false, err
+		}
+	}
+	return providerARN, false, nil
+}
+
+// DeleteOpenIDConnectProvider deletes the IAM OpenID Connect provider of the given issuer. It
+// returns false if the provider doesn't exist.
+func (c *awsClient) DeleteOpenIDConnectProvider(issuerURL string) (bool, error) {
+	providerARN, err := c.findOpenIDConnectProvider(issuerURL)
+	if err != nil {
+		return false, err
+	}
+	if providerARN == "" {
+		return false, nil
+	}
+	_, err = c.iamClient.DeleteOpenIDConnectProvider(&iam.DeleteOpenIDConnectProviderInput{
+		OpenIDConnectProviderArn: aws.String(providerARN),
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// findOpenIDConnectProvider returns the ARN of the provider of the given issuer, or an empty
+// string if there is no such provider.
+func (c *awsClient) findOpenIDConnectProvider(issuerURL string) (string, error) {
+	suffix := ":oidc-provider/" + strings.TrimSuffix(strings.TrimPrefix(issuerURL, "https://"), "/")
+	output, err := c.iamClient.ListOpenIDConnectProviders(&iam.ListOpenIDConnectProvidersInput{})
+	if err != nil {
+		return "", err
+	}
+	for _, provider := range output.OpenIDConnectProviderList {
+		providerARN := aws.StringValue(provider.Arn)
+		if strings.HasSuffix(providerARN, suffix) {
+			return providerARN, nil
+		}
+	}
+	return "", nil
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
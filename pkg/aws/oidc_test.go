@@ -0,0 +1,66 @@
+package aws_test
+
+import (
+	"crypto/sha1" // #nosec G505
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+var _ = Describe("OIDC provider", func() {
+	var (
+		server *httptest.Server
+		issuer string
+		keys   string
+	)
+
+	BeforeEach(func() {
+		keys = `{"keys": [{"kty": "RSA", "kid": "1"}]}`
+		server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/.well-known/openid-configuration":
+				fmt.Fprintf(w, `{"issuer": %q, "jwks_uri": %q}`, issuer, server.URL+"/keys.json")
+			case "/keys.json":
+				fmt.Fprint(w, keys)
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		issuer = server.URL
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("fetches the configuration and thumbprint of the issuer", func() {
+		config, err := aws.FetchOIDCProviderConfig(server.Client(), server.URL+"/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(config.IssuerURL).To(Equal(server.URL))
+		Expect(config.JWKSURL).To(Equal(server.URL + "/keys.json"))
+		// #nosec G401
+		Expect(config.Thumbprint).To(Equal(fmt.Sprintf("%x", sha1.Sum(server.Certificate().Raw))))
+	})
+
+	It("fails if the issuer isn't an HTTPS URL", func() {
+		_, err := aws.FetchOIDCProviderConfig(server.Client(), "http://oidc.example.com")
+		Expect(err).To(MatchError(ContainSubstring("isn't a valid HTTPS URL")))
+	})
+
+	It("fails if the discovery document declares another issuer", func() {
+		issuer = "https://oidc.example.com"
+		_, err := aws.FetchOIDCProviderConfig(server.Client(), server.URL)
+		Expect(err).To(MatchError(ContainSubstring("declares issuer 'https://oidc.example.com'")))
+	})
+
+	It("fails if the JWKS doesn't contain any key", func() {
+		keys = `{"keys": []}`
+		_, err := aws.FetchOIDCProviderConfig(server.Client(), server.URL)
+		Expect(err).To(MatchError(ContainSubstring("doesn't contain any key")))
+	})
+})
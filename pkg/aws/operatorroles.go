@@ -0,0 +1,319 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to build the IAM roles used by the operators of STS
+// clusters from the CredentialsRequest manifests of the OpenShift release.
+
+package aws
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/ghodss/yaml"
+
+	"github.com/openshift/rosa/assets"
+)
+
+// Directory of the embedded CredentialsRequest manifests, one sub-directory per minor version:
+const credentialsRequestsDir = "templates/credentialsrequests"
+
+// CredentialsRequest contains the fields of the CredentialsRequest manifests that are needed to
+// create the IAM role of an operator.
+type CredentialsRequest struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec struct {
+		SecretRef struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"secretRef"`
+		ProviderSpec struct {
+			Kind             string           `json:"kind"`
+			StatementEntries []StatementEntry `json:"statementEntries"`
+		} `json:"providerSpec"`
+		ServiceAccountNames []string `json:"serviceAccountNames"`
+	} `json:"spec"`
+}
+
+// StatementEntry is a statement of the AWS policy requested by a CredentialsRequest.
+type StatementEntry struct {
+	Effect          string                 `json:"effect"`
+	Action          []string               `json:"action"`
+	Resource        string                 `json:"resource"`
+	PolicyCondition map[string]interface{} `json:"policyCondition,omitempty"`
+}
+
+var documentSeparatorRE = regexp.MustCompile(`(?m)^---\s*$`)
+
+// ParseCredentialsRequests parses the YAML or JSON documents contained in the given data, and
+// returns the CredentialsRequests that target AWS. Other documents are ignored.
+func ParseCredentialsRequests(data []byte) ([]*CredentialsRequest, error) {
+	requests := []*CredentialsRequest{}
+	for _, document := range documentSeparatorRE.Split(string(data), -1) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+		request := new(CredentialsRequest)
+		err := yaml.Unmarshal([]byte(document), request)
+		if err != nil {
+			return nil, err
+		}
+		if request.Kind != "CredentialsRequest" || request.Spec.ProviderSpec.Kind != "AWSProviderSpec" {
+			continue
+		}
+		if request.Spec.SecretRef.Name == "" || request.Spec.SecretRef.Namespace == "" {
+			return nil, fmt.Errorf("CredentialsRequest '%s' doesn't have a secret reference",
+				request.Metadata.Name)
+		}
+		if len(request.Spec.ServiceAccountNames) == 0 {
+			return nil, fmt.Errorf("CredentialsRequest '%s' doesn't have any service account",
+				request.Metadata.Name)
+		}
+		requests = append(requests, request)
+	}
+	return requests, nil
+}
+
+// ReadCredentialsRequests reads the CredentialsRequests from the YAML and JSON files stored in
+// the given directory, as extracted with 'oc adm release extract --credentials-requests'.
+func ReadCredentialsRequests(dir string) ([]*CredentialsRequest, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	requests := []*CredentialsRequest{}
+	for _, file := range files {
+		switch filepath.Ext(file.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		path := filepath.Join(dir, file.Name())
+		// #nosec G304
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fileRequests, err := ParseCredentialsRequests(data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse '%s': %v", path, err)
+		}
+		requests = append(requests, fileRequests...)
+	}
+	return requests, nil
+}
+
+// GetCredentialsRequests returns the CredentialsRequests known for the given OpenShift version.
+func GetCredentialsRequests(version string) ([]*CredentialsRequest, error) {
+	parts := strings.Split(strings.TrimPrefix(version, "openshift-v"), ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("Version '%s' isn't valid", version)
+	}
+	minor := strings.Join(parts[:2], ".")
+
+	files, err := assets.AssetDir(fmt.Sprintf("%s/%s", credentialsRequestsDir, minor))
+	if err != nil {
+		known, _ := assets.AssetDir(credentialsRequestsDir)
+		sort.Strings(known)
+		return nil, fmt.Errorf("No CredentialsRequests are known for version '%s', "+
+			"known versions are %s", version, known)
+	}
+	sort.Strings(files)
+
+	requests := []*CredentialsRequest{}
+	for _, file := range files {
+		path := fmt.Sprintf("%s/%s/%s", credentialsRequestsDir, minor, file)
+		data, err := assets.Asset(path)
+		if err != nil {
+			return nil, err
+		}
+		fileRequests, err := ParseCredentialsRequests(data)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse '%s': %v", path, err)
+		}
+		requests = append(requests, fileRequests...)
+	}
+	return requests, nil
+}
+
+// Maximum length of the name of an IAM role, and length of the hash added to the names that are
+// truncated:
+const (
+	maxRoleNameLength  = 64
+	roleNameHashLength = 8
+)
+
+// RoleName returns the name of the IAM role of the operator for the given prefix. Names longer
+// than allowed by IAM are truncated, and a short hash of the complete name is appended, so that
+// different operators don't end up with the same role.
+func (r *CredentialsRequest) RoleName(prefix string) string {
+	name := fmt.Sprintf("%s-%s-%s", prefix, r.Spec.SecretRef.Namespace, r.Spec.SecretRef.Name)
+	if len(name) > maxRoleNameLength {
+		hash := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:roleNameHashLength]
+		name = strings.TrimRight(name[:maxRoleNameLength-roleNameHashLength-1], "-") + "-" + hash
+	}
+	return name
+}
+
+// ServiceAccounts returns the subjects of the service accounts that use the credentials.
+func (r *CredentialsRequest) ServiceAccounts() []string {
+	serviceAccounts := []string{}
+	for _, name := range r.Spec.ServiceAccountNames {
+		serviceAccounts = append(serviceAccounts,
+			fmt.Sprintf("system:serviceaccount:%s:%s", r.Spec.SecretRef.Namespace, name))
+	}
+	return serviceAccounts
+}
+
+// PermissionPolicy returns the IAM policy document requested by the CredentialsRequest.
+func (r *CredentialsRequest) PermissionPolicy() (string, error) {
+	statements := []map[string]interface{}{}
+	for _, entry := range r.Spec.ProviderSpec.StatementEntries {
+		statement := map[string]interface{}{
+			"Effect":   entry.Effect,
+			"Action":   entry.Action,
+			"Resource": entry.Resource,
+		}
+		if len(entry.PolicyCondition) > 0 {
+			statement["Condition"] = entry.PolicyCondition
+		}
+		statements = append(statements, statement)
+	}
+	return marshalPolicy(map[string]interface{}{
+		"Version":   "2012-10-17",
+		"Statement": statements,
+	})
+}
+
+// OIDCProviderARN returns the ARN of the IAM OIDC provider of the given issuer in the account
+// and partition of the creator.
+func OIDCProviderARN(creator *Creator, oidcEndpointURL string) string {
+	return fmt.Sprintf("arn:%s:iam::%s:oidc-provider/%s", creator.Partition(), creator.AccountID,
+		oidcIssuer(oidcEndpointURL))
+}
+
+func oidcIssuer(oidcEndpointURL string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(oidcEndpointURL, "https://"), "/")
+}
+
+// OperatorRoleTrustPolicy returns the trust policy that allows the service accounts of the
+// operator to assume the role using the web identity tokens issued by the OIDC provider.
+func (r *CredentialsRequest) OperatorRoleTrustPolicy(creator *Creator, oidcEndpointURL string) (string, error) {
+	return marshalPolicy(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{
+			{
+				"Effect": "Allow",
+				"Principal": map[string]interface{}{
+					"Federated": OIDCProviderARN(creator, oidcEndpointURL),
+				},
+				"Action": "sts:AssumeRoleWithWebIdentity",
+				"Condition": map[string]interface{}{
+					"StringEquals": map[string]interface{}{
+						fmt.Sprintf("%s:sub", oidcIssuer(oidcEndpointURL)): r.ServiceAccounts(),
+					},
+				},
+			},
+		},
+	})
+}
+
+// CheckOperatorRoleProvider checks that an existing operator role trusts the given OIDC provider.
+// An operator role only trusts the provider of one cluster, so replacing its trust policy would
+// break the operators of the cluster that already uses it.
+func CheckOperatorRoleProvider(role *iam.Role, providerARN string) error {
+	trustPolicy, err := GetRoleTrustPolicy(role)
+	if err != nil {
+		return err
+	}
+	providers, err := federatedPrincipals(trustPolicy)
+	if err != nil {
+		return err
+	}
+	roleName := aws.StringValue(role.RoleName)
+	if len(providers) == 0 {
+		return fmt.Errorf("Role '%s' already exists and doesn't trust any OIDC provider", roleName)
+	}
+	for _, provider := range providers {
+		if provider == providerARN {
+			return nil
+		}
+	}
+	return fmt.Errorf("Role '%s' already exists and trusts OIDC provider '%s' of another cluster",
+		roleName, strings.Join(providers, "', '"))
+}
+
+// federatedPrincipals returns the federated principals of the statements of the given policy.
+// Both the statements and the principals can be either a single value or a list.
+func federatedPrincipals(policy string) ([]string, error) {
+	var document struct {
+		Statement json.RawMessage
+	}
+	err := json.Unmarshal([]byte(policy), &document)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse trust policy: %v", err)
+	}
+	type statement struct {
+		Principal struct {
+			Federated json.RawMessage
+		}
+	}
+	statements := []statement{}
+	if json.Unmarshal(document.Statement, &statements) != nil {
+		single := statement{}
+		err = json.Unmarshal(document.Statement, &single)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse trust policy statements: %v", err)
+		}
+		statements = append(statements, single)
+	}
+	principals := []string{}
+	for _, s := range statements {
+		if len(s.Principal.Federated) == 0 {
+			continue
+		}
+		values := []string{}
+		if json.Unmarshal(s.Principal.Federated, &values) != nil {
+			value := ""
+			err = json.Unmarshal(s.Principal.Federated, &value)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse federated principal: %v", err)
+			}
+			values = append(values, value)
+		}
+		principals = append(principals, values...)
+	}
+	return principals, nil
+}
+
+func marshalPolicy(document interface{}) (string, error) {
+	data, err := json.MarshalIndent(document, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
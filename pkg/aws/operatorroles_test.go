@@ -0,0 +1,167 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+var _ = Describe("Operator roles", func() {
+	Context("ParseCredentialsRequests", func() {
+		It("returns only the AWS requests", func() {
+			requests, err := aws.ParseCredentialsRequests([]byte(`
+apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-ingress
+spec:
+  providerSpec:
+    kind: AWSProviderSpec
+    statementEntries:
+    - action:
+      - route53:ListHostedZones
+      effect: Allow
+      resource: '*'
+  secretRef:
+    name: cloud-credentials
+    namespace: openshift-ingress-operator
+  serviceAccountNames:
+  - ingress-operator
+---
+apiVersion: cloudcredential.openshift.io/v1
+kind: CredentialsRequest
+metadata:
+  name: openshift-ingress-gcp
+spec:
+  providerSpec:
+    kind: GCPProviderSpec
+  secretRef:
+    name: cloud-credentials
+    namespace: openshift-ingress-operator
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].RoleName("prefix")).To(Equal("prefix-openshift-ingress-operator-cloud-credentials"))
+			Expect(requests[0].ServiceAccounts()).To(Equal([]string{
+				"system:serviceaccount:openshift-ingress-operator:ingress-operator",
+			}))
+		})
+	})
+
+	Context("RoleName", func() {
+		request := func(namespace string, name string) *aws.CredentialsRequest {
+			result := &aws.CredentialsRequest{}
+			result.Spec.SecretRef.Namespace = namespace
+			result.Spec.SecretRef.Name = name
+			return result
+		}
+
+		It("truncates long names without collisions", func() {
+			prefix := "my-very-long-organization-prefix"
+			a := request("openshift-cluster-csi-drivers", "ebs-cloud-credentials-first")
+			b := request("openshift-cluster-csi-drivers", "ebs-cloud-credentials-second")
+			Expect(len(a.RoleName(prefix))).To(BeNumerically("<=", 64))
+			Expect(a.RoleName(prefix)).To(MatchRegexp(`^my-very-long-organization-prefix-openshift-cluster-csi-[0-9a-f]{8}$`))
+			Expect(a.RoleName(prefix)).NotTo(Equal(b.RoleName(prefix)))
+		})
+	})
+
+	Context("GetCredentialsRequests", func() {
+		It("returns the known requests of a version", func() {
+			requests, err := aws.GetCredentialsRequests("4.7.2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(requests).NotTo(BeEmpty())
+		})
+
+		It("fails for unknown versions", func() {
+			_, err := aws.GetCredentialsRequests("3.11.0")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("OperatorRoleTrustPolicy", func() {
+		creator := &aws.Creator{
+			ARN:       "arn:aws:iam::123456789012:user/admin",
+			AccountID: "123456789012",
+		}
+
+		It("trusts the service accounts through the OIDC provider", func() {
+			requests, err := aws.GetCredentialsRequests("4.7")
+			Expect(err).NotTo(HaveOccurred())
+			policy, err := requests[0].OperatorRoleTrustPolicy(creator, "https://oidc.example.com/abc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(ContainSubstring(`"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"`))
+			Expect(policy).To(ContainSubstring(`"oidc.example.com/abc:sub"`))
+			Expect(policy).To(ContainSubstring("system:serviceaccount:"))
+		})
+
+		It("ignores a trailing slash in the OIDC endpoint URL", func() {
+			requests, err := aws.GetCredentialsRequests("4.7")
+			Expect(err).NotTo(HaveOccurred())
+			policy, err := requests[0].OperatorRoleTrustPolicy(creator, "https://oidc.example.com/abc/")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(ContainSubstring(`"Federated": "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"`))
+			Expect(policy).To(ContainSubstring(`"oidc.example.com/abc:sub"`))
+		})
+
+		It("uses the partition of the creator", func() {
+			requests, err := aws.GetCredentialsRequests("4.7")
+			Expect(err).NotTo(HaveOccurred())
+			govCreator := &aws.Creator{
+				ARN:       "arn:aws-us-gov:iam::123456789012:user/admin",
+				AccountID: "123456789012",
+			}
+			policy, err := requests[0].OperatorRoleTrustPolicy(govCreator, "https://oidc.example.com/abc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(ContainSubstring(
+				`"Federated": "arn:aws-us-gov:iam::123456789012:oidc-provider/oidc.example.com/abc"`))
+		})
+
+		It("keeps the conditions of the requested permissions", func() {
+			requests, err := aws.GetCredentialsRequests("4.7")
+			Expect(err).NotTo(HaveOccurred())
+			found := false
+			for _, request := range requests {
+				policy, err := request.PermissionPolicy()
+				Expect(err).NotTo(HaveOccurred())
+				if request.Metadata.Name == "openshift-machine-api-aws" {
+					found = true
+					Expect(policy).To(ContainSubstring(`"kms:GrantIsForAWSResource": true`))
+				}
+			}
+			Expect(found).To(BeTrue())
+		})
+	})
+
+	Context("CheckOperatorRoleProvider", func() {
+		const providerARN = "arn:aws:iam::123456789012:oidc-provider/oidc.example.com/abc"
+
+		role := func(trustPolicy string) *iam.Role {
+			return &iam.Role{
+				RoleName:                 awssdk.String("myorg-openshift-ingress-operator-cloud-credentials"),
+				AssumeRolePolicyDocument: awssdk.String(trustPolicy),
+			}
+		}
+
+		It("accepts a role that trusts the provider", func() {
+			Expect(aws.CheckOperatorRoleProvider(role(`{"Statement": [{"Principal": {
+				"Federated": "`+providerARN+`"}}]}`), providerARN)).To(Succeed())
+		})
+
+		It("rejects a role that trusts the provider of another cluster", func() {
+			err := aws.CheckOperatorRoleProvider(role(`{"Statement": {"Principal": {
+				"Federated": ["arn:aws:iam::123456789012:oidc-provider/oidc.example.com/other"]}}}`),
+				providerARN)
+			Expect(err).To(MatchError(ContainSubstring("oidc.example.com/other")))
+		})
+
+		It("rejects a role that doesn't trust any provider", func() {
+			err := aws.CheckOperatorRoleProvider(role(`{"Statement": [{"Principal": {
+				"AWS": "arn:aws:iam::123456789012:root"}}]}`), providerARN)
+			Expect(err).To(MatchError(ContainSubstring("doesn't trust any OIDC provider")))
+		})
+	})
+})
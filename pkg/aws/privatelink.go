@@ -0,0 +1,283 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check, before creating a PrivateLink cluster, that
+// its VPC has the DNS settings, egress path and hosted zones that the installer needs.
+
+package aws
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+)
+
+// PrivateLinkBaseDomain is the domain under which the DNS zones of PrivateLink clusters are
+// created. Private hosted zones for this domain or for any of its parents hide those zones.
+const PrivateLinkBaseDomain = "p1.openshiftapps.com"
+
+// PrivateLinkEndpointServices are the AWS services that the cluster needs to reach through VPC
+// endpoints when its subnets don't have a default route.
+var PrivateLinkEndpointServices = []string{
+	"ec2",
+	"elasticloadbalancing",
+	"s3",
+	"sts",
+}
+
+// PrivateLinkLayout contains the AWS resources needed to check that the subnets can be used by a
+// PrivateLink cluster.
+type PrivateLinkLayout struct {
+	*SubnetLayout
+
+	// Region is the region of the VPCs, used to build the names of the endpoint services.
+	Region string
+
+	// Endpoints are the VPC endpoints of the VPCs of the subnets.
+	Endpoints []*ec2.VpcEndpoint
+
+	// HostedZones are the private hosted zones whose names conflict with the base domain of the
+	// clusters, together with the VPCs they are associated to.
+	HostedZones []*PrivateHostedZone
+}
+
+// PrivateHostedZone is a Route 53 private hosted zone and the VPCs it is associated to.
+type PrivateHostedZone struct {
+	ID     string
+	Name   string
+	VPCIDs []string
+}
+
+// PrivateLinkCheck is the result of one of the checks of the PrivateLink preflight. The
+// remediation hint is only set when the check fails.
+type PrivateLinkCheck struct {
+	Name        string
+	Failures    []string
+	Remediation string
+}
+
+// Passed returns true if the check didn't find any problem.
+func (c *PrivateLinkCheck) Passed() bool {
+	return len(c.Failures) == 0
+}
+
+// PrivateLinkReport contains the results of the checks of the PrivateLink preflight.
+type PrivateLinkReport struct {
+	Checks []*PrivateLinkCheck
+}
+
+// Passed returns true if all the checks passed.
+func (r *PrivateLinkReport) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a human readable report with the result of each check, and the remediation
+// hints of the checks that failed.
+func (r *PrivateLinkReport) String() string {
+	var out strings.Builder
+	for _, check := range r.Checks {
+		if check.Passed() {
+			fmt.Fprintf(&out, "PASS  %s\n", check.Name)
+			continue
+		}
+		fmt.Fprintf(&out, "FAIL  %s\n", check.Name)
+		for _, failure := range check.Failures {
+			fmt.Fprintf(&out, "      - %s\n", failure)
+		}
+		fmt.Fprintf(&out, "      Hint: %s\n", check.Remediation)
+	}
+	return out.String()
+}
+
+// GetPrivateLinkLayout returns the given layout of the subnets, as returned by GetSubnetLayout,
+// together with the VPC endpoints of their VPCs and the private hosted zones that conflict with
+// the base domain of the clusters.
+func (c *awsClient) GetPrivateLinkLayout(subnetLayout *SubnetLayout) (*PrivateLinkLayout, error) {
+	layout := &PrivateLinkLayout{
+		SubnetLayout: subnetLayout,
+		Region:       aws.StringValue(c.awsSession.Config.Region),
+	}
+
+	vpcIDs := []string{}
+	for vpcID := range subnetLayout.DNSSupport {
+		vpcIDs = append(vpcIDs, vpcID)
+	}
+	sort.Strings(vpcIDs)
+	if len(vpcIDs) == 0 {
+		return layout, nil
+	}
+
+	err := c.ec2Client.DescribeVpcEndpointsPages(&ec2.DescribeVpcEndpointsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: aws.StringSlice(vpcIDs),
+			},
+		},
+	}, func(page *ec2.DescribeVpcEndpointsOutput, lastPage bool) bool {
+		layout.Endpoints = append(layout.Endpoints, page.VpcEndpoints...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The zones are listed by name, and only those that conflict are described to find the VPCs
+	// they are associated to:
+	zones := []*route53.HostedZone{}
+	err = c.route53Client.ListHostedZonesPages(&route53.ListHostedZonesInput{},
+		func(page *route53.ListHostedZonesOutput, lastPage bool) bool {
+			for _, zone := range page.HostedZones {
+				if zone.Config != nil && aws.BoolValue(zone.Config.PrivateZone) &&
+					conflictsWithBaseDomain(aws.StringValue(zone.Name)) {
+					zones = append(zones, zone)
+				}
+			}
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+	for _, zone := range zones {
+		output, err := c.route53Client.GetHostedZone(&route53.GetHostedZoneInput{
+			Id: zone.Id,
+		})
+		if err != nil {
+			return nil, err
+		}
+		hostedZone := &PrivateHostedZone{
+			ID:   strings.TrimPrefix(aws.StringValue(zone.Id), "/hostedzone/"),
+			Name: strings.TrimSuffix(aws.StringValue(zone.Name), "."),
+		}
+		for _, vpc := range output.VPCs {
+			hostedZone.VPCIDs = append(hostedZone.VPCIDs, aws.StringValue(vpc.VPCId))
+		}
+		layout.HostedZones = append(layout.HostedZones, hostedZone)
+	}
+
+	return layout, nil
+}
+
+// conflictsWithBaseDomain returns true if a zone with the given name would hide the zones of the
+// clusters, because it is for the base domain or for one of its parents.
+func conflictsWithBaseDomain(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	return name != "" && (name == PrivateLinkBaseDomain || strings.HasSuffix(PrivateLinkBaseDomain, "."+name))
+}
+
+// ValidatePrivateLink checks that the subnets are private, that their VPCs have DNS support and
+// hostnames enabled, that the private subnets have an egress path, either a default route or VPC
+// endpoints for the required services, and that there are no private hosted zones associated to
+// the VPCs that conflict with the zones of the cluster.
+func ValidatePrivateLink(layout *PrivateLinkLayout) *PrivateLinkReport {
+	private := &PrivateLinkCheck{
+		Name: "Subnets are private",
+		Remediation: "PrivateLink clusters can only use private subnets. Remove the public subnets " +
+			"from '--subnet-ids', or replace their default route to the internet gateway.",
+	}
+	dns := &PrivateLinkCheck{
+		Name: "VPC has DNS resolution and DNS hostnames enabled",
+		Remediation: "Enable them with 'aws ec2 modify-vpc-attribute --vpc-id <vpc> " +
+			"--enable-dns-support' and '--enable-dns-hostnames'.",
+	}
+	egress := &PrivateLinkCheck{
+		Name: "Subnets have an egress path",
+		Remediation: "Add a default route through a NAT gateway, transit gateway or firewall to the " +
+			"route tables of the subnets, or create VPC endpoints for the missing services.",
+	}
+	zones := &PrivateLinkCheck{
+		Name: "No conflicting private hosted zones",
+		Remediation: fmt.Sprintf("Disassociate the private hosted zones for '%s' or its parent domains "+
+			"from the VPC, as they prevent the cluster names from resolving.", PrivateLinkBaseDomain),
+	}
+
+	vpcIDs := []string{}
+	for _, subnet := range layout.Subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		vpcID := aws.StringValue(subnet.VpcId)
+		if !contains(vpcIDs, vpcID) {
+			vpcIDs = append(vpcIDs, vpcID)
+		}
+		target := defaultRouteTarget(layout.RouteTables, vpcID, subnetID)
+		if strings.HasPrefix(target, "igw-") {
+			private.Failures = append(private.Failures, fmt.Sprintf(
+				"subnet '%s' is public, its default route goes through '%s'", subnetID, target))
+			continue
+		}
+		if target != "" {
+			continue
+		}
+		missing := missingEndpoints(layout, vpcID)
+		if len(missing) > 0 {
+			egress.Failures = append(egress.Failures, fmt.Sprintf(
+				"subnet '%s' has no default route and VPC '%s' has no endpoints for %s",
+				subnetID, vpcID, strings.Join(missing, ", ")))
+		}
+	}
+	sort.Strings(vpcIDs)
+
+	for _, vpcID := range vpcIDs {
+		if !layout.DNSSupport[vpcID] {
+			dns.Failures = append(dns.Failures, fmt.Sprintf("VPC '%s' doesn't have DNS resolution enabled", vpcID))
+		}
+		if !layout.DNSHostnames[vpcID] {
+			dns.Failures = append(dns.Failures, fmt.Sprintf("VPC '%s' doesn't have DNS hostnames enabled", vpcID))
+		}
+	}
+
+	for _, zone := range layout.HostedZones {
+		for _, vpcID := range zone.VPCIDs {
+			if contains(vpcIDs, vpcID) {
+				zones.Failures = append(zones.Failures, fmt.Sprintf(
+					"private hosted zone '%s' (%s) is associated to VPC '%s'", zone.Name, zone.ID, vpcID))
+			}
+		}
+	}
+
+	return &PrivateLinkReport{
+		Checks: []*PrivateLinkCheck{private, dns, egress, zones},
+	}
+}
+
+// missingEndpoints returns the services of PrivateLinkEndpointServices that don't have an
+// available endpoint in the VPC.
+func missingEndpoints(layout *PrivateLinkLayout, vpcID string) []string {
+	missing := []string{}
+	for _, service := range PrivateLinkEndpointServices {
+		serviceName := fmt.Sprintf("com.amazonaws.%s.%s", layout.Region, service)
+		found := false
+		for _, endpoint := range layout.Endpoints {
+			if aws.StringValue(endpoint.VpcId) == vpcID &&
+				aws.StringValue(endpoint.ServiceName) == serviceName &&
+				strings.EqualFold(aws.StringValue(endpoint.State), ec2.StateAvailable) {
+				found = true
+			}
+		}
+		if !found {
+			missing = append(missing, service)
+		}
+	}
+	return missing
+}
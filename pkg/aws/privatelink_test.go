@@ -0,0 +1,187 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("PrivateLink", func() {
+	subnet := func(id string) *ec2.Subnet {
+		return &ec2.Subnet{
+			SubnetId:         awssdk.String(id),
+			VpcId:            awssdk.String("vpc-1"),
+			AvailabilityZone: awssdk.String("us-east-1a"),
+			CidrBlock:        awssdk.String("10.0.0.0/20"),
+		}
+	}
+	routeTable := func(subnetID string, routes ...*ec2.Route) *ec2.RouteTable {
+		for _, route := range routes {
+			route.DestinationCidrBlock = awssdk.String("0.0.0.0/0")
+		}
+		return &ec2.RouteTable{
+			VpcId: awssdk.String("vpc-1"),
+			Associations: []*ec2.RouteTableAssociation{
+				{SubnetId: awssdk.String(subnetID)},
+			},
+			Routes: routes,
+		}
+	}
+	endpoint := func(service string) *ec2.VpcEndpoint {
+		return &ec2.VpcEndpoint{
+			VpcId:       awssdk.String("vpc-1"),
+			ServiceName: awssdk.String("com.amazonaws.us-east-1." + service),
+			State:       awssdk.String("available"),
+		}
+	}
+	failures := func(report *aws.PrivateLinkReport, name string) []string {
+		for _, check := range report.Checks {
+			if check.Name == name {
+				return check.Failures
+			}
+		}
+		return nil
+	}
+
+	var layout *aws.PrivateLinkLayout
+
+	BeforeEach(func() {
+		layout = &aws.PrivateLinkLayout{
+			SubnetLayout: &aws.SubnetLayout{
+				Subnets: []*ec2.Subnet{subnet("subnet-1")},
+				RouteTables: []*ec2.RouteTable{
+					routeTable("subnet-1", &ec2.Route{TransitGatewayId: awssdk.String("tgw-1")}),
+				},
+				DNSSupport:   map[string]bool{"vpc-1": true},
+				DNSHostnames: map[string]bool{"vpc-1": true},
+			},
+			Region: "us-east-1",
+		}
+	})
+
+	It("passes for private subnets with an egress path", func() {
+		report := aws.ValidatePrivateLink(layout)
+		Expect(report.Passed()).To(BeTrue(), report.String())
+		Expect(report.String()).To(ContainSubstring("PASS  Subnets are private"))
+	})
+
+	It("fails for public subnets and disabled DNS", func() {
+		layout.RouteTables[0].Routes[0] = &ec2.Route{
+			DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+			GatewayId:            awssdk.String("igw-1"),
+		}
+		layout.DNSHostnames["vpc-1"] = false
+		report := aws.ValidatePrivateLink(layout)
+		Expect(report.Passed()).To(BeFalse())
+		Expect(failures(report, "Subnets are private")).To(ConsistOf(
+			"subnet 'subnet-1' is public, its default route goes through 'igw-1'"))
+		Expect(failures(report, "VPC has DNS resolution and DNS hostnames enabled")).To(ConsistOf(
+			"VPC 'vpc-1' doesn't have DNS hostnames enabled"))
+		Expect(report.String()).To(ContainSubstring("Hint: Enable them with"))
+	})
+
+	It("accepts VPC endpoints instead of a default route", func() {
+		layout.RouteTables[0].Routes = nil
+		layout.Endpoints = []*ec2.VpcEndpoint{endpoint("ec2"), endpoint("s3"), endpoint("sts")}
+		report := aws.ValidatePrivateLink(layout)
+		Expect(failures(report, "Subnets have an egress path")).To(ConsistOf(
+			"subnet 'subnet-1' has no default route and VPC 'vpc-1' has no endpoints for elasticloadbalancing"))
+
+		layout.Endpoints = append(layout.Endpoints, endpoint("elasticloadbalancing"))
+		Expect(aws.ValidatePrivateLink(layout).Passed()).To(BeTrue())
+	})
+
+	It("accepts subnets without a default route that only use VPC endpoints", func() {
+		layout.RouteTables[0].Routes = nil
+		layout.Endpoints = []*ec2.VpcEndpoint{
+			endpoint("ec2"), endpoint("elasticloadbalancing"), endpoint("s3"), endpoint("sts"),
+		}
+		subnetReport := aws.ValidateSubnetLayout(layout.SubnetLayout, false, true, nil)
+		Expect(subnetReport.Valid()).To(BeTrue(), subnetReport.String())
+		report := aws.ValidatePrivateLink(layout)
+		Expect(report.Passed()).To(BeTrue(), report.String())
+	})
+
+	It("fails for conflicting private hosted zones associated to the VPC", func() {
+		layout.HostedZones = []*aws.PrivateHostedZone{
+			{ID: "Z1", Name: "openshiftapps.com", VPCIDs: []string{"vpc-1"}},
+			{ID: "Z2", Name: "p1.openshiftapps.com", VPCIDs: []string{"vpc-2"}},
+		}
+		report := aws.ValidatePrivateLink(layout)
+		Expect(failures(report, "No conflicting private hosted zones")).To(ConsistOf(
+			"private hosted zone 'openshiftapps.com' (Z1) is associated to VPC 'vpc-1'"))
+	})
+
+	Context("GetPrivateLinkLayout", func() {
+		var (
+			client         aws.Client
+			mockCtrl       *gomock.Controller
+			mockEC2API     *mocks.MockEC2API
+			mockRoute53API *mocks.MockRoute53API
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockEC2API = mocks.NewMockEC2API(mockCtrl)
+			mockRoute53API = mocks.NewMockRoute53API(mockCtrl)
+			client = aws.New(
+				logrus.New(),
+				mocks.NewMockIAMAPI(mockCtrl),
+				mockEC2API,
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mocks.NewMockSTSAPI(mockCtrl),
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				mocks.NewMockKMSAPI(mockCtrl),
+				mockRoute53API,
+				&session.Session{Config: awssdk.NewConfig().WithRegion("us-east-1")},
+				&aws.AccessKey{},
+			)
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("only describes the private hosted zones that conflict", func() {
+			mockEC2API.EXPECT().DescribeVpcEndpointsPages(gomock.Any(), gomock.Any()).Return(nil)
+			mockRoute53API.EXPECT().ListHostedZonesPages(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ *route53.ListHostedZonesInput,
+					fn func(*route53.ListHostedZonesOutput, bool) bool) error {
+					private := &route53.HostedZoneConfig{PrivateZone: awssdk.Bool(true)}
+					fn(&route53.ListHostedZonesOutput{
+						HostedZones: []*route53.HostedZone{
+							{Id: awssdk.String("/hostedzone/Z1"), Name: awssdk.String("openshiftapps.com."),
+								Config: private},
+							{Id: awssdk.String("/hostedzone/Z2"), Name: awssdk.String("example.com."),
+								Config: private},
+							{Id: awssdk.String("/hostedzone/Z3"), Name: awssdk.String("com."),
+								Config: &route53.HostedZoneConfig{PrivateZone: awssdk.Bool(false)}},
+						},
+					}, true)
+					return nil
+				})
+			mockRoute53API.EXPECT().GetHostedZone(&route53.GetHostedZoneInput{
+				Id: awssdk.String("/hostedzone/Z1"),
+			}).Return(&route53.GetHostedZoneOutput{
+				VPCs: []*route53.VPC{{VPCId: awssdk.String("vpc-1")}},
+			}, nil)
+
+			result, err := client.GetPrivateLinkLayout(layout.SubnetLayout)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.SubnetLayout).To(BeIdenticalTo(layout.SubnetLayout))
+			Expect(result.Region).To(Equal("us-east-1"))
+			Expect(result.HostedZones).To(Equal([]*aws.PrivateHostedZone{
+				{ID: "Z1", Name: "openshiftapps.com", VPCIDs: []string{"vpc-1"}},
+			}))
+		})
+	})
+})
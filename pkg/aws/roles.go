@@ -0,0 +1,521 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to create and update the IAM roles used by STS clusters.
+
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+
+	"github.com/openshift/rosa/assets"
+	"github.com/openshift/rosa/pkg/interactive/confirm"
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// DefaultRolePrefix is the prefix used to name the account roles when the user doesn't give one.
+const DefaultRolePrefix = "ManagedOpenShift"
+
+// RedHatAccountID is the AWS account that Red Hat uses to assume the installer and support roles.
+const RedHatAccountID = "710019948333"
+
+// AccountRole describes one of the account-wide IAM roles used by STS clusters.
+type AccountRole struct {
+	// Name is used to build the name of the role and of its permission policy.
+	Name string
+
+	// Flag is the 'rosa create cluster' flag that receives the ARN of the role.
+	Flag string
+
+	// template is the name used by the trust and permission policy templates of the role.
+	template string
+}
+
+// AccountRoles are the roles that need to exist in the account in order to create STS clusters.
+var AccountRoles = []AccountRole{
+	{Name: "Installer", Flag: "role-arn", template: "installer"},
+	{Name: "Support", Flag: "support-role-arn", template: "support"},
+	{Name: "ControlPlane", Flag: "master-iam-role", template: "instance_controlplane"},
+	{Name: "Worker", Flag: "worker-iam-role", template: "instance_worker"},
+}
+
+// RoleName returns the name of the role for the given prefix.
+func (r AccountRole) RoleName(prefix string) string {
+	return fmt.Sprintf("%s-%s-Role", prefix, r.Name)
+}
+
+// PolicyName returns the name of the inline permission policy of the role for the given prefix.
+func (r AccountRole) PolicyName(prefix string) string {
+	return fmt.Sprintf("%s-%s-Role-Policy", prefix, r.Name)
+}
+
+// TrustPolicyPath returns the path of the template of the trust policy of the role.
+func (r AccountRole) TrustPolicyPath() string {
+	return fmt.Sprintf("templates/policies/sts_%s_trust_policy.json", r.template)
+}
+
+// PermissionPolicyPath returns the path of the template of the permission policy of the role.
+func (r AccountRole) PermissionPolicyPath() string {
+	return fmt.Sprintf("templates/policies/sts_%s_permission_policy.json", r.template)
+}
+
+// ReadPolicyTemplate reads a policy document from the embedded templates, replacing the
+// '%{name}' variables it contains with the given values.
+func ReadPolicyTemplate(path string, vars map[string]string) (string, error) {
+	data, err := assets.Asset(path)
+	if err != nil {
+		return "", fmt.Errorf("Unable to load file '%s': %v", path, err)
+	}
+	policy := string(data)
+	for name, value := range vars {
+		policy = strings.ReplaceAll(policy, fmt.Sprintf("%%{%s}", name), value)
+	}
+	return policy, nil
+}
+
+// AddExternalIDCondition adds to every statement of the given trust policy a condition that
+// requires the principal to present the given external ID. Existing conditions of the statements
+// are kept.
+func AddExternalIDCondition(trustPolicy string, externalID string) (string, error) {
+	document := map[string]interface{}{}
+	err := json.Unmarshal([]byte(trustPolicy), &document)
+	if err != nil {
+		return "", fmt.Errorf("Failed to parse trust policy: %v", err)
+	}
+	statements, ok := document["Statement"].([]interface{})
+	if !ok {
+		return "", fmt.Errorf("Trust policy doesn't contain any statement")
+	}
+	for _, statement := range statements {
+		s, ok := statement.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condition, ok := s["Condition"].(map[string]interface{})
+		if !ok {
+			condition = map[string]interface{}{}
+			s["Condition"] = condition
+		}
+		stringEquals, ok := condition["StringEquals"].(map[string]interface{})
+		if !ok {
+			stringEquals = map[string]interface{}{}
+			condition["StringEquals"] = stringEquals
+		}
+		stringEquals["sts:ExternalId"] = externalID
+	}
+	data, err := json.MarshalIndent(document, "", "    ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// PolicyDiff compares two policy documents, ignoring formatting and the order of the keys, and
+// returns a line based diff that turns the current document into the expected one. The result
+// is empty if both documents are equivalent.
+func PolicyDiff(current string, expected string) (string, error) {
+	currentLines, err := normalizePolicy(current)
+	if err != nil {
+		return "", err
+	}
+	expectedLines, err := normalizePolicy(expected)
+	if err != nil {
+		return "", err
+	}
+	return diffLines(currentLines, expectedLines), nil
+}
+
+func normalizePolicy(policy string) ([]string, error) {
+	var document interface{}
+	err := json.Unmarshal([]byte(policy), &document)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse policy document: %v", err)
+	}
+	// Maps are marshalled with their keys sorted, so both documents end up in the same shape:
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(data), "\n"), nil
+}
+
+// Number of unchanged lines kept around the changes of a diff.
+const diffContext = 2
+
+// diffLines returns the lines removed from 'a' prefixed with '-', the lines added from 'b'
+// prefixed with '+' and the unchanged lines close to them, or an empty string if there are no
+// differences.
+func diffLines(a []string, b []string) string {
+	// Length of the longest common subsequence of the suffixes of both lists:
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	lines := []line{}
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			changed = true
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			changed = true
+			j++
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	// Only keep the unchanged lines that are close to a change:
+	keep := make([]bool, len(lines))
+	for k, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for c := k - diffContext; c <= k+diffContext; c++ {
+			if c >= 0 && c < len(lines) {
+				keep[c] = true
+			}
+		}
+	}
+	var out strings.Builder
+	skipped := false
+	for k, l := range lines {
+		if !keep[k] {
+			if !skipped {
+				out.WriteString("  ...\n")
+				skipped = true
+			}
+			continue
+		}
+		skipped = false
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.text)
+	}
+	return out.String()
+}
+
+// EnsureRole creates the role if it doesn't exist yet. If it already exists the policies are
+// compared with the expected ones, and the differences are displayed and applied only if the
+// user confirms them.
+func EnsureRole(reporter *rprtr.Object, awsClient Client, roleName string, policyName string,
+	trustPolicy string, permissionPolicy string, permissionsBoundary string,
+	tagsList map[string]string) (string, error) {
+	role, err := awsClient.GetRole(roleName)
+	if err != nil {
+		return "", err
+	}
+
+	if role == nil {
+		reporter.Infof("Creating role '%s'", roleName)
+		roleARN, err := awsClient.CreateRole(roleName, trustPolicy, permissionsBoundary, tagsList)
+		if err != nil {
+			return "", err
+		}
+		err = awsClient.PutRolePolicy(roleName, policyName, permissionPolicy)
+		if err != nil {
+			return "", err
+		}
+		reporter.Infof("Created role '%s' with ARN '%s'", roleName, roleARN)
+		return roleARN, nil
+	}
+
+	roleARN := aws.StringValue(role.Arn)
+
+	currentTrustPolicy, err := GetRoleTrustPolicy(role)
+	if err != nil {
+		return "", err
+	}
+	trustDiff, err := PolicyDiff(currentTrustPolicy, trustPolicy)
+	if err != nil {
+		return "", err
+	}
+
+	currentPermissionPolicy, err := awsClient.GetRolePolicy(roleName, policyName)
+	if err != nil {
+		return "", err
+	}
+	permissionDiff := ""
+	if currentPermissionPolicy != "" {
+		permissionDiff, err = PolicyDiff(currentPermissionPolicy, permissionPolicy)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	currentBoundary := ""
+	if role.PermissionsBoundary != nil {
+		currentBoundary = aws.StringValue(role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+	boundaryChanged := permissionsBoundary != "" && permissionsBoundary != currentBoundary
+
+	tagsDiff := roleTagsDiff(role.Tags, tagsList)
+
+	if trustDiff == "" && permissionDiff == "" && currentPermissionPolicy != "" && !boundaryChanged &&
+		len(tagsDiff) == 0 {
+		reporter.Infof("Role '%s' already exists and is up to date", roleName)
+		return roleARN, nil
+	}
+
+	reporter.Warnf("Role '%s' already exists but doesn't match the expected configuration", roleName)
+	if trustDiff != "" {
+		fmt.Printf("Trust policy:\n%s\n", trustDiff)
+	}
+	if currentPermissionPolicy == "" {
+		fmt.Printf("Permission policy '%s' is missing\n\n", policyName)
+	} else if permissionDiff != "" {
+		fmt.Printf("Permission policy '%s':\n%s\n", policyName, permissionDiff)
+	}
+	if boundaryChanged {
+		fmt.Printf("Permissions boundary:\n- %s\n+ %s\n\n", currentBoundary, permissionsBoundary)
+	}
+	if len(tagsDiff) > 0 {
+		fmt.Printf("Tags:\n%s\n\n", strings.Join(tagsDiff, "\n"))
+	}
+	if !confirm.Confirm("update role '%s'", roleName) {
+		reporter.Warnf("Role '%s' was left unchanged", roleName)
+		return roleARN, nil
+	}
+
+	if trustDiff != "" {
+		err = awsClient.UpdateTrustPolicy(roleName, trustPolicy)
+		if err != nil {
+			return "", err
+		}
+	}
+	if currentPermissionPolicy == "" || permissionDiff != "" {
+		err = awsClient.PutRolePolicy(roleName, policyName, permissionPolicy)
+		if err != nil {
+			return "", err
+		}
+	}
+	if boundaryChanged {
+		err = awsClient.PutPermissionsBoundary(roleName, permissionsBoundary)
+		if err != nil {
+			return "", err
+		}
+	}
+	if len(tagsDiff) > 0 {
+		err = awsClient.TagRole(roleName, tagsList)
+		if err != nil {
+			return "", err
+		}
+	}
+	reporter.Infof("Updated role '%s'", roleName)
+
+	return roleARN, nil
+}
+
+// roleTagsDiff returns the tags that need to be added to the role, or whose value needs to change, in
+// the format used to display the differences of the policies. Tags of the role that aren't in the
+// expected list are left alone, so they aren't part of the result.
+func roleTagsDiff(current []*iam.Tag, expected map[string]string) []string {
+	values := map[string]string{}
+	for _, tag := range current {
+		values[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	keys := []string{}
+	for key := range expected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := []string{}
+	for _, key := range keys {
+		value, ok := values[key]
+		if ok && value == expected[key] {
+			continue
+		}
+		if ok {
+			result = append(result, fmt.Sprintf("- %s: %s", key, value))
+		}
+		result = append(result, fmt.Sprintf("+ %s: %s", key, expected[key]))
+	}
+	return result
+}
+
+// Role names may only contain alphanumeric characters and '+=,.@-_':
+var rolePrefixRE = regexp.MustCompile(`^[\w+=,.@-]+$`)
+
+// ValidatePrefix checks that the prefix can be used to build the names of the account roles.
+func ValidatePrefix(prefix string) error {
+	if !rolePrefixRE.MatchString(prefix) {
+		return fmt.Errorf("Role prefix '%s' isn't valid: it must contain only alphanumeric "+
+			"characters and '+=,.@-_'", prefix)
+	}
+	for _, role := range AccountRoles {
+		if len(role.RoleName(prefix)) > maxRoleNameLength {
+			return fmt.Errorf("Role prefix '%s' is too long: role name '%s' exceeds %d characters",
+				prefix, role.RoleName(prefix), maxRoleNameLength)
+		}
+	}
+	return nil
+}
+
+// ValidatePolicyARN checks that the given string is the ARN of an IAM policy.
+func ValidatePolicyARN(policyARN string) error {
+	parsed, err := arn.Parse(policyARN)
+	if err != nil {
+		return err
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "policy/") {
+		return fmt.Errorf("'%s' isn't the ARN of an IAM policy", policyARN)
+	}
+	return nil
+}
+
+// GetRole returns the IAM role with the given name, or nil if it doesn't exist.
+func (c *awsClient) GetRole(roleName string) (*iam.Role, error) {
+	output, err := c.iamClient.GetRole(&iam.GetRoleInput{
+		RoleName: aws.String(roleName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return output.Role, nil
+}
+
+// GetRoleTrustPolicy returns the decoded trust policy of the given IAM role.
+func GetRoleTrustPolicy(role *iam.Role) (string, error) {
+	return url.QueryUnescape(aws.StringValue(role.AssumeRolePolicyDocument))
+}
+
+// CreateRole creates an IAM role with the given trust policy, permissions boundary and tags,
+// and returns its ARN. The permissions boundary is optional.
+func (c *awsClient) CreateRole(roleName string, trustPolicy string, permissionsBoundary string,
+	tagList map[string]string) (string, error) {
+	input := &iam.CreateRoleInput{
+		RoleName:                 aws.String(roleName),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+		Tags:                     getIAMTags(tagList),
+	}
+	if permissionsBoundary != "" {
+		input.PermissionsBoundary = aws.String(permissionsBoundary)
+	}
+	output, err := c.iamClient.CreateRole(input)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(output.Role.Arn), nil
+}
+
+// UpdateTrustPolicy replaces the trust policy of the given IAM role.
+func (c *awsClient) UpdateTrustPolicy(roleName string, trustPolicy string) error {
+	_, err := c.iamClient.UpdateAssumeRolePolicy(&iam.UpdateAssumeRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyDocument: aws.String(trustPolicy),
+	})
+	return err
+}
+
+// PutPermissionsBoundary sets the permissions boundary of the given IAM role.
+func (c *awsClient) PutPermissionsBoundary(roleName string, permissionsBoundary string) error {
+	_, err := c.iamClient.PutRolePermissionsBoundary(&iam.PutRolePermissionsBoundaryInput{
+		RoleName:            aws.String(roleName),
+		PermissionsBoundary: aws.String(permissionsBoundary),
+	})
+	return err
+}
+
+// GetRolePolicy returns the decoded inline policy of the given IAM role, or an empty string if
+// the role doesn't have a policy with that name.
+func (c *awsClient) GetRolePolicy(roleName string, policyName string) (string, error) {
+	output, err := c.iamClient.GetRolePolicy(&iam.GetRolePolicyInput{
+		RoleName:   aws.String(roleName),
+		PolicyName: aws.String(policyName),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == iam.ErrCodeNoSuchEntityException {
+			return "", nil
+		}
+		return "", err
+	}
+	return url.QueryUnescape(aws.StringValue(output.PolicyDocument))
+}
+
+// PutRolePolicy creates or replaces the inline policy of the given IAM role.
+func (c *awsClient) PutRolePolicy(roleName string, policyName string, policy string) error {
+	_, err := c.iamClient.PutRolePolicy(&iam.PutRolePolicyInput{
+		RoleName:       aws.String(roleName),
+		PolicyName:     aws.String(policyName),
+		PolicyDocument: aws.String(policy),
+	})
+	return err
+}
+
+// TagRole adds the given tags to the IAM role, replacing the values of existing keys.
+func (c *awsClient) TagRole(roleName string, tagList map[string]string) error {
+	if len(tagList) == 0 {
+		return nil
+	}
+	_, err := c.iamClient.TagRole(&iam.TagRoleInput{
+		RoleName: aws.String(roleName),
+		Tags:     getIAMTags(tagList),
+	})
+	return err
+}
+
+func getIAMTags(tagList map[string]string) []*iam.Tag {
+	keys := []string{}
+	for key := range tagList {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	iamTags := []*iam.Tag{}
+	for _, key := range keys {
+		iamTags = append(iamTags, &iam.Tag{
+			Key:   aws.String(key),
+			Value: aws.String(tagList[key]),
+		})
+	}
+	return iamTags
+}
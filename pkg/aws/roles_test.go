@@ -0,0 +1,121 @@
+package aws_test
+
+import (
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+
+	"github.com/openshift/rosa/pkg/aws"
+	"github.com/openshift/rosa/pkg/aws/mocks"
+)
+
+var _ = Describe("Roles", func() {
+	Context("PolicyDiff", func() {
+		It("ignores formatting and key order", func() {
+			diff, err := aws.PolicyDiff(
+				`{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":["ec2:*"]}]}`,
+				`{
+					"Statement": [{"Action": ["ec2:*"], "Effect": "Allow"}],
+					"Version": "2012-10-17"
+				}`,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(BeEmpty())
+		})
+
+		It("reports added and removed lines", func() {
+			diff, err := aws.PolicyDiff(
+				`{"Statement":[{"Action":["ec2:DescribeRegions","s3:GetObject"]}]}`,
+				`{"Statement":[{"Action":["ec2:DescribeRegions","ec2:DescribeInstances"]}]}`,
+			)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(diff).To(ContainSubstring("-         \"s3:GetObject\""))
+			Expect(diff).To(ContainSubstring("+         \"ec2:DescribeInstances\""))
+		})
+	})
+
+	Context("ReadPolicyTemplate", func() {
+		It("replaces the template variables", func() {
+			policy, err := aws.ReadPolicyTemplate(aws.AccountRoles[0].TrustPolicyPath(), map[string]string{
+				"aws_account_id": "123456789012",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(ContainSubstring("arn:aws:iam::123456789012:role/"))
+			Expect(policy).NotTo(ContainSubstring("%{"))
+		})
+
+		It("adds the external ID condition", func() {
+			policy, err := aws.ReadPolicyTemplate(aws.AccountRoles[0].TrustPolicyPath(), nil)
+			Expect(err).NotTo(HaveOccurred())
+			policy, err = aws.AddExternalIDCondition(policy, "my-external-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(ContainSubstring(`"sts:ExternalId": "my-external-id"`))
+		})
+
+		It("keeps the existing conditions", func() {
+			policy, err := aws.AddExternalIDCondition(`{"Statement": [{
+				"Effect": "Allow",
+				"Condition": {
+					"StringEquals": {"aws:PrincipalAccount": "123456789012"},
+					"Bool": {"aws:MultiFactorAuthPresent": "true"}
+				}
+			}]}`, "my-external-id")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(ContainSubstring(`"aws:PrincipalAccount": "123456789012"`))
+			Expect(policy).To(ContainSubstring(`"aws:MultiFactorAuthPresent": "true"`))
+			Expect(policy).To(ContainSubstring(`"sts:ExternalId": "my-external-id"`))
+		})
+	})
+
+	Context("GetRole", func() {
+		var (
+			client     aws.Client
+			mockCtrl   *gomock.Controller
+			mockIamAPI *mocks.MockIAMAPI
+		)
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			mockIamAPI = mocks.NewMockIAMAPI(mockCtrl)
+			client = aws.New(
+				logrus.New(),
+				mockIamAPI,
+				mocks.NewMockEC2API(mockCtrl),
+				mocks.NewMockOrganizationsAPI(mockCtrl),
+				mocks.NewMockSTSAPI(mockCtrl),
+				mocks.NewMockCloudFormationAPI(mockCtrl),
+				mocks.NewMockServiceQuotasAPI(mockCtrl),
+				mocks.NewMockKMSAPI(mockCtrl),
+				mocks.NewMockRoute53API(mockCtrl),
+				&session.Session{},
+				&aws.AccessKey{},
+			)
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("returns nil when the role doesn't exist", func() {
+			mockIamAPI.EXPECT().GetRole(gomock.Any()).Return(nil,
+				awserr.New(iam.ErrCodeNoSuchEntityException, "not found", nil))
+			role, err := client.GetRole("ManagedOpenShift-Installer-Role")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(role).To(BeNil())
+		})
+
+		It("decodes the inline policy of the role", func() {
+			mockIamAPI.EXPECT().GetRolePolicy(gomock.Any()).Return(&iam.GetRolePolicyOutput{
+				PolicyDocument: awssdk.String("%7B%22Version%22%3A%222012-10-17%22%7D"),
+			}, nil)
+			policy, err := client.GetRolePolicy("role", "policy")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy).To(Equal(`{"Version":"2012-10-17"}`))
+		})
+	})
+})
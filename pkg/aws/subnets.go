@@ -0,0 +1,343 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check, before creating a cluster, that the existing
+// subnets given by the user have a layout that the installer supports.
+
+package aws
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+// SubnetLayout contains the AWS resources needed to validate the subnets of a cluster.
+type SubnetLayout struct {
+	Subnets []*ec2.Subnet
+
+	// RouteTables are the route tables of the VPCs of the subnets.
+	RouteTables []*ec2.RouteTable
+
+	// DNSSupport and DNSHostnames contain the DNS attributes of the VPCs, indexed by VPC ID.
+	DNSSupport   map[string]bool
+	DNSHostnames map[string]bool
+}
+
+// SubnetReport contains the result of the validation of one subnet.
+type SubnetReport struct {
+	SubnetID         string
+	AvailabilityZone string
+	CIDR             string
+	Public           bool
+	Failures         []string
+}
+
+// SubnetLayoutReport contains the result of the validation of the subnets of a cluster. The
+// failures that don't concern a single subnet are reported separately.
+type SubnetLayoutReport struct {
+	Subnets  []*SubnetReport
+	Failures []string
+}
+
+// Valid returns true if none of the checks failed.
+func (r *SubnetLayoutReport) Valid() bool {
+	if len(r.Failures) > 0 {
+		return false
+	}
+	for _, subnet := range r.Subnets {
+		if len(subnet.Failures) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a human readable report with the failures of each subnet.
+func (r *SubnetLayoutReport) String() string {
+	var out strings.Builder
+	for _, failure := range r.Failures {
+		fmt.Fprintf(&out, "- %s\n", failure)
+	}
+	for _, subnet := range r.Subnets {
+		kind := "private"
+		if subnet.Public {
+			kind = "public"
+		}
+		fmt.Fprintf(&out, "%s (%s, %s, %s): ", subnet.SubnetID, subnet.AvailabilityZone, kind, subnet.CIDR)
+		if len(subnet.Failures) == 0 {
+			out.WriteString("OK\n")
+			continue
+		}
+		out.WriteString("FAILED\n")
+		for _, failure := range subnet.Failures {
+			fmt.Fprintf(&out, "  - %s\n", failure)
+		}
+	}
+	return out.String()
+}
+
+// GetSubnetLayout returns the given subnets together with the route tables and DNS attributes
+// of their VPCs.
+func (c *awsClient) GetSubnetLayout(subnetIDs []string) (*SubnetLayout, error) {
+	subnets, err := c.ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{
+		SubnetIds: aws.StringSlice(subnetIDs),
+	})
+	if err != nil {
+		return nil, err
+	}
+	layout := &SubnetLayout{
+		Subnets:      subnets.Subnets,
+		DNSSupport:   map[string]bool{},
+		DNSHostnames: map[string]bool{},
+	}
+
+	vpcIDs := []string{}
+	for _, subnet := range subnets.Subnets {
+		vpcID := aws.StringValue(subnet.VpcId)
+		if _, ok := layout.DNSSupport[vpcID]; ok {
+			continue
+		}
+		vpcIDs = append(vpcIDs, vpcID)
+
+		support, err := c.ec2Client.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String(vpcID),
+			Attribute: aws.String(ec2.VpcAttributeNameEnableDnsSupport),
+		})
+		if err != nil {
+			return nil, err
+		}
+		layout.DNSSupport[vpcID] = support.EnableDnsSupport != nil &&
+			aws.BoolValue(support.EnableDnsSupport.Value)
+
+		hostnames, err := c.ec2Client.DescribeVpcAttribute(&ec2.DescribeVpcAttributeInput{
+			VpcId:     aws.String(vpcID),
+			Attribute: aws.String(ec2.VpcAttributeNameEnableDnsHostnames),
+		})
+		if err != nil {
+			return nil, err
+		}
+		layout.DNSHostnames[vpcID] = hostnames.EnableDnsHostnames != nil &&
+			aws.BoolValue(hostnames.EnableDnsHostnames.Value)
+	}
+
+	err = c.ec2Client.DescribeRouteTablesPages(&ec2.DescribeRouteTablesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("vpc-id"),
+				Values: aws.StringSlice(vpcIDs),
+			},
+		},
+	}, func(page *ec2.DescribeRouteTablesOutput, lastPage bool) bool {
+		layout.RouteTables = append(layout.RouteTables, page.RouteTables...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// PrivateSubnetZones returns the availability zones of the private subnets of the layout, indexed
+// by subnet ID. Subnets whose default route goes through an internet gateway are public, and
+// aren't included.
+func (l *SubnetLayout) PrivateSubnetZones() map[string]string {
+	zones := map[string]string{}
+	for _, subnet := range l.Subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		target := defaultRouteTarget(l.RouteTables, aws.StringValue(subnet.VpcId), subnetID)
+		if !strings.HasPrefix(target, "igw-") {
+			zones[subnetID] = aws.StringValue(subnet.AvailabilityZone)
+		}
+	}
+	return zones
+}
+
+// ValidateSubnetLayout checks that the subnets are in a single VPC with DNS enabled, that there
+// is one private subnet, and one public subnet unless the cluster uses PrivateLink, in each of
+// the availability zones of the cluster, that the subnets have the right egress routes and that
+// their CIDR blocks are inside the machine CIDR. The egress path of the private subnets of
+// PrivateLink clusters, which may be only VPC endpoints, is checked by ValidatePrivateLink.
+func ValidateSubnetLayout(layout *SubnetLayout, multiAZ bool, privateLink bool,
+	machineCIDR *net.IPNet) *SubnetLayoutReport {
+	report := &SubnetLayoutReport{}
+
+	vpcIDs := []string{}
+	privateByZone := map[string][]string{}
+	publicByZone := map[string][]string{}
+	for _, subnet := range layout.Subnets {
+		subnetID := aws.StringValue(subnet.SubnetId)
+		subnetReport := &SubnetReport{
+			SubnetID:         subnetID,
+			AvailabilityZone: aws.StringValue(subnet.AvailabilityZone),
+			CIDR:             aws.StringValue(subnet.CidrBlock),
+		}
+		report.Subnets = append(report.Subnets, subnetReport)
+
+		vpcID := aws.StringValue(subnet.VpcId)
+		if !contains(vpcIDs, vpcID) {
+			vpcIDs = append(vpcIDs, vpcID)
+		}
+
+		target := defaultRouteTarget(layout.RouteTables, vpcID, subnetID)
+		subnetReport.Public = strings.HasPrefix(target, "igw-")
+		if subnetReport.Public {
+			publicByZone[subnetReport.AvailabilityZone] = append(
+				publicByZone[subnetReport.AvailabilityZone], subnetID)
+			if privateLink {
+				subnetReport.Failures = append(subnetReport.Failures,
+					"public subnets can't be used by PrivateLink clusters")
+			}
+		} else {
+			privateByZone[subnetReport.AvailabilityZone] = append(
+				privateByZone[subnetReport.AvailabilityZone], subnetID)
+			switch {
+			case strings.HasPrefix(target, "nat-") || strings.HasPrefix(target, "i-"):
+			case privateLink:
+			case target == "":
+				subnetReport.Failures = append(subnetReport.Failures,
+					"no default route, private subnets need to route through a NAT gateway")
+			default:
+				subnetReport.Failures = append(subnetReport.Failures, fmt.Sprintf(
+					"default route goes through '%s' instead of a NAT gateway", target))
+			}
+		}
+
+		_, subnetCIDR, err := net.ParseCIDR(subnetReport.CIDR)
+		if err != nil {
+			subnetReport.Failures = append(subnetReport.Failures,
+				fmt.Sprintf("CIDR block '%s' isn't valid", subnetReport.CIDR))
+		} else if machineCIDR != nil && !cidrContains(machineCIDR, subnetCIDR) {
+			subnetReport.Failures = append(subnetReport.Failures,
+				fmt.Sprintf("CIDR block isn't contained in machine CIDR '%s'", machineCIDR))
+		}
+	}
+
+	if len(vpcIDs) > 1 {
+		sort.Strings(vpcIDs)
+		report.Failures = append(report.Failures,
+			fmt.Sprintf("subnets must all be in the same VPC, found %s", strings.Join(vpcIDs, ", ")))
+	}
+	for _, vpcID := range vpcIDs {
+		if !layout.DNSSupport[vpcID] {
+			report.Failures = append(report.Failures, fmt.Sprintf("VPC '%s' doesn't have DNS support enabled", vpcID))
+		}
+		if !layout.DNSHostnames[vpcID] {
+			report.Failures = append(report.Failures,
+				fmt.Sprintf("VPC '%s' doesn't have DNS hostnames enabled", vpcID))
+		}
+	}
+
+	zones := []string{}
+	for zone := range privateByZone {
+		zones = append(zones, zone)
+	}
+	for zone := range publicByZone {
+		if _, ok := privateByZone[zone]; !ok {
+			zones = append(zones, zone)
+		}
+	}
+	sort.Strings(zones)
+
+	expectedZones := 1
+	if multiAZ {
+		expectedZones = 3
+	}
+	if len(zones) != expectedZones {
+		report.Failures = append(report.Failures, fmt.Sprintf(
+			"expected subnets in %d availability zones, found %d", expectedZones, len(zones)))
+	}
+	for _, zone := range zones {
+		switch private := privateByZone[zone]; {
+		case len(private) == 0:
+			report.Failures = append(report.Failures,
+				fmt.Sprintf("availability zone '%s' doesn't have a private subnet", zone))
+		case len(private) > 1:
+			report.Failures = append(report.Failures, fmt.Sprintf(
+				"availability zone '%s' has more than one private subnet: %s", zone, strings.Join(private, ", ")))
+		}
+		if privateLink {
+			continue
+		}
+		switch public := publicByZone[zone]; {
+		case len(public) == 0:
+			report.Failures = append(report.Failures,
+				fmt.Sprintf("availability zone '%s' doesn't have a public subnet", zone))
+		case len(public) > 1:
+			report.Failures = append(report.Failures, fmt.Sprintf(
+				"availability zone '%s' has more than one public subnet: %s", zone, strings.Join(public, ", ")))
+		}
+	}
+
+	return report
+}
+
+// defaultRouteTarget returns the identifier of the target of the default route of the route
+// table associated to the subnet, or to the VPC if the subnet doesn't have its own, or an empty
+// string if there is no such route.
+func defaultRouteTarget(routeTables []*ec2.RouteTable, vpcID string, subnetID string) string {
+	var subnetTable, mainTable *ec2.RouteTable
+	for _, table := range routeTables {
+		if aws.StringValue(table.VpcId) != vpcID {
+			continue
+		}
+		for _, association := range table.Associations {
+			if aws.StringValue(association.SubnetId) == subnetID {
+				subnetTable = table
+			}
+			if aws.BoolValue(association.Main) {
+				mainTable = table
+			}
+		}
+	}
+	table := subnetTable
+	if table == nil {
+		table = mainTable
+	}
+	if table == nil {
+		return ""
+	}
+	for _, route := range table.Routes {
+		if aws.StringValue(route.DestinationCidrBlock) != "0.0.0.0/0" ||
+			aws.StringValue(route.State) == ec2.RouteStateBlackhole {
+			continue
+		}
+		for _, target := range []*string{
+			route.GatewayId,
+			route.NatGatewayId,
+			route.TransitGatewayId,
+			route.InstanceId,
+			route.NetworkInterfaceId,
+			route.VpcPeeringConnectionId,
+		} {
+			if aws.StringValue(target) != "" {
+				return aws.StringValue(target)
+			}
+		}
+	}
+	return ""
+}
+
+// cidrContains returns true if the 'inner' block is contained in the 'outer' one.
+func cidrContains(outer *net.IPNet, inner *net.IPNet) bool {
+	outerOnes, _ := outer.Mask.Size()
+	innerOnes, _ := inner.Mask.Size()
+	return outerOnes <= innerOnes && outer.Contains(inner.IP)
+}
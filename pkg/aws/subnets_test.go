@@ -0,0 +1,135 @@
+package aws_test
+
+import (
+	"net"
+
+	awssdk "github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/aws"
+)
+
+var _ = Describe("Subnet layout", func() {
+	subnet := func(id string, vpcID string, zone string, cidr string) *ec2.Subnet {
+		return &ec2.Subnet{
+			SubnetId:         awssdk.String(id),
+			VpcId:            awssdk.String(vpcID),
+			AvailabilityZone: awssdk.String(zone),
+			CidrBlock:        awssdk.String(cidr),
+		}
+	}
+	routeTable := func(vpcID string, subnetID string, route *ec2.Route) *ec2.RouteTable {
+		route.DestinationCidrBlock = awssdk.String("0.0.0.0/0")
+		return &ec2.RouteTable{
+			VpcId: awssdk.String(vpcID),
+			Associations: []*ec2.RouteTableAssociation{
+				{SubnetId: awssdk.String(subnetID)},
+			},
+			Routes: []*ec2.Route{route},
+		}
+	}
+	_, machineCIDR, _ := net.ParseCIDR("10.0.0.0/16")
+
+	var layout *aws.SubnetLayout
+
+	BeforeEach(func() {
+		layout = &aws.SubnetLayout{
+			Subnets: []*ec2.Subnet{
+				subnet("subnet-private", "vpc-1", "us-east-1a", "10.0.0.0/20"),
+				subnet("subnet-public", "vpc-1", "us-east-1a", "10.0.16.0/20"),
+			},
+			RouteTables: []*ec2.RouteTable{
+				routeTable("vpc-1", "subnet-private", &ec2.Route{NatGatewayId: awssdk.String("nat-1")}),
+				routeTable("vpc-1", "subnet-public", &ec2.Route{GatewayId: awssdk.String("igw-1")}),
+			},
+			DNSSupport:   map[string]bool{"vpc-1": true},
+			DNSHostnames: map[string]bool{"vpc-1": true},
+		}
+	})
+
+	It("accepts a valid single AZ layout", func() {
+		report := aws.ValidateSubnetLayout(layout, false, false, machineCIDR)
+		Expect(report.Valid()).To(BeTrue(), report.String())
+		Expect(report.Subnets[0].Public).To(BeFalse())
+		Expect(report.Subnets[1].Public).To(BeTrue())
+	})
+
+	It("fails if the number of zones doesn't match multi AZ", func() {
+		report := aws.ValidateSubnetLayout(layout, true, false, machineCIDR)
+		Expect(report.Valid()).To(BeFalse())
+		Expect(report.Failures).To(ContainElement("expected subnets in 3 availability zones, found 1"))
+	})
+
+	It("fails if the VPC doesn't have DNS hostnames enabled", func() {
+		layout.DNSHostnames["vpc-1"] = false
+		report := aws.ValidateSubnetLayout(layout, false, false, machineCIDR)
+		Expect(report.Failures).To(ConsistOf("VPC 'vpc-1' doesn't have DNS hostnames enabled"))
+	})
+
+	It("fails if the subnets are in different VPCs", func() {
+		layout.Subnets[1].VpcId = awssdk.String("vpc-2")
+		layout.RouteTables[1].VpcId = awssdk.String("vpc-2")
+		layout.DNSSupport["vpc-2"] = true
+		layout.DNSHostnames["vpc-2"] = true
+		report := aws.ValidateSubnetLayout(layout, false, false, machineCIDR)
+		Expect(report.Failures).To(ConsistOf("subnets must all be in the same VPC, found vpc-1, vpc-2"))
+	})
+
+	It("fails if a private subnet doesn't route through a NAT gateway", func() {
+		layout.RouteTables[0].Routes = nil
+		report := aws.ValidateSubnetLayout(layout, false, false, machineCIDR)
+		Expect(report.Subnets[0].Failures).To(ConsistOf(
+			"no default route, private subnets need to route through a NAT gateway"))
+	})
+
+	It("uses the main route table of the VPC for subnets without their own", func() {
+		layout.RouteTables[0].Associations = []*ec2.RouteTableAssociation{
+			{Main: awssdk.Bool(true)},
+		}
+		report := aws.ValidateSubnetLayout(layout, false, false, machineCIDR)
+		Expect(report.Valid()).To(BeTrue(), report.String())
+	})
+
+	It("fails if a zone doesn't have a public subnet", func() {
+		layout.Subnets = layout.Subnets[:1]
+		report := aws.ValidateSubnetLayout(layout, false, false, machineCIDR)
+		Expect(report.Failures).To(ConsistOf("availability zone 'us-east-1a' doesn't have a public subnet"))
+	})
+
+	It("fails if a PrivateLink cluster uses public subnets", func() {
+		report := aws.ValidateSubnetLayout(layout, false, true, machineCIDR)
+		Expect(report.Subnets[1].Failures).To(ConsistOf("public subnets can't be used by PrivateLink clusters"))
+	})
+
+	It("accepts PrivateLink subnets that route through a transit gateway", func() {
+		layout.Subnets = layout.Subnets[:1]
+		layout.RouteTables[0].Routes[0] = &ec2.Route{
+			DestinationCidrBlock: awssdk.String("0.0.0.0/0"),
+			TransitGatewayId:     awssdk.String("tgw-1"),
+		}
+		report := aws.ValidateSubnetLayout(layout, false, true, machineCIDR)
+		Expect(report.Valid()).To(BeTrue(), report.String())
+	})
+
+	It("leaves the egress path of PrivateLink subnets without a default route to the preflight", func() {
+		layout.Subnets = layout.Subnets[:1]
+		layout.RouteTables[0].Routes = nil
+		report := aws.ValidateSubnetLayout(layout, false, true, machineCIDR)
+		Expect(report.Valid()).To(BeTrue(), report.String())
+	})
+
+	It("fails if a subnet isn't contained in the machine CIDR", func() {
+		layout.Subnets[1].CidrBlock = awssdk.String("10.1.0.0/20")
+		report := aws.ValidateSubnetLayout(layout, false, false, machineCIDR)
+		Expect(report.Subnets[1].Failures).To(ConsistOf("CIDR block isn't contained in machine CIDR '10.0.0.0/16'"))
+		Expect(report.String()).To(ContainSubstring("subnet-public (us-east-1a, public, 10.1.0.0/20): FAILED"))
+	})
+
+	It("returns the zones of the private subnets", func() {
+		Expect(layout.PrivateSubnetZones()).To(Equal(map[string]string{
+			"subnet-private": "us-east-1a",
+		}))
+	})
+})
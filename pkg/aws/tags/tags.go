@@ -19,6 +19,14 @@ limitations under the License.
 
 package tags
 
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
 // Prefix used by all the tag names:
 const prefix = "rosa_"
 
@@ -28,5 +36,147 @@ const ClusterName = prefix + "cluster_name"
 // ClusterID is the name of the tag that will contain the identifier of the cluster.
 const ClusterID = prefix + "cluster_id"
 
-// ClusterID is the name of the tag that will contain the identifier of the cluster.
+// ClusterRegion is the name of the tag that will contain the region of the cluster.
 const ClusterRegion = prefix + "region"
+
+// Tags that are added by the service to all the resources of the cluster:
+const (
+	RedHatManaged     = "red-hat-managed"
+	RedHatClusterType = "red-hat-clustertype"
+)
+
+// MaxTags is the maximum number of user defined tags that AWS allows for a resource.
+const MaxTags = 50
+
+// Limits of the length of the keys and values, in characters:
+const (
+	maxKeyLength   = 128
+	maxValueLength = 256
+)
+
+// reservedKeys are the tags owned by ROSA and by the service, that users can't set:
+var reservedKeys = []string{
+	ClusterName,
+	ClusterID,
+	ClusterRegion,
+	RedHatManaged,
+	RedHatClusterType,
+}
+
+// reservedPrefixes are the prefixes of the tags that are reserved by AWS and by OpenShift. They
+// are compared ignoring case.
+var reservedPrefixes = []string{
+	"aws:",
+	"kubernetes.io/cluster/",
+}
+
+// Characters that AWS allows in the keys and values of the tags:
+var tagRE = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// Parse parses a list of tags in the 'key:value' format. The key ends at the first colon, so the
+// value may contain colons.
+func Parse(values []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		index := strings.Index(value, ":")
+		if index < 0 {
+			return nil, fmt.Errorf("Invalid tag '%s': expected the 'key:value' format", value)
+		}
+		key := strings.TrimSpace(value[:index])
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("Invalid tags: key '%s' is repeated", key)
+		}
+		result[key] = strings.TrimSpace(value[index+1:])
+	}
+	return result, nil
+}
+
+// Validate checks that the tags follow the rules of AWS and that they don't use the reserved keys.
+func Validate(tags map[string]string) error {
+	if len(tags) > MaxTags {
+		return fmt.Errorf("Invalid tags: there are %d tags, but at most %d are allowed", len(tags), MaxTags)
+	}
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := tags[key]
+		if key == "" {
+			return fmt.Errorf("Invalid tags: keys can't be empty")
+		}
+		if utf8.RuneCountInString(key) > maxKeyLength {
+			return fmt.Errorf("Invalid tag key '%s': it can't be longer than %d characters", key, maxKeyLength)
+		}
+		if utf8.RuneCountInString(value) > maxValueLength {
+			return fmt.Errorf("Invalid value for tag '%s': it can't be longer than %d characters",
+				key, maxValueLength)
+		}
+		if !tagRE.MatchString(key) {
+			return fmt.Errorf("Invalid tag key '%s': it can only contain letters, numbers, spaces "+
+				"and the characters '_.:/=+-@'", key)
+		}
+		if !tagRE.MatchString(value) {
+			return fmt.Errorf("Invalid value for tag '%s': it can only contain letters, numbers, "+
+				"spaces and the characters '_.:/=+-@'", key)
+		}
+		if IsReserved(key) {
+			return fmt.Errorf("Invalid tag key '%s': it is reserved", key)
+		}
+	}
+	return nil
+}
+
+// IsReserved returns true if the given key is owned by ROSA, by the service, by OpenShift or by
+// AWS.
+func IsReserved(key string) bool {
+	for _, reserved := range reservedKeys {
+		if key == reserved {
+			return true
+		}
+	}
+	for _, prefix := range reservedPrefixes {
+		if strings.HasPrefix(strings.ToLower(key), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithoutReserved returns a copy of the given tags without the ones that use reserved keys, for
+// example to reuse the tags of an existing cluster for a new one.
+func WithoutReserved(tags map[string]string) map[string]string {
+	result := map[string]string{}
+	for key, value := range tags {
+		if !IsReserved(key) {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// Merge returns the result of adding the extra tags to the base tags. Extra tags can't change the
+// value of a base tag.
+func Merge(base map[string]string, extra map[string]string) (map[string]string, error) {
+	result := map[string]string{}
+	for key, value := range base {
+		result[key] = value
+	}
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if current, ok := result[key]; ok && current != extra[key] {
+			return nil, fmt.Errorf("Invalid tag key '%s': it is already set to '%s'", key, current)
+		}
+		result[key] = extra[key]
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package tags_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/aws/tags"
+)
+
+var _ = Describe("Tags", func() {
+	Context("Parse", func() {
+		It("splits keys and values at the first colon", func() {
+			result, err := tags.Parse([]string{"owner:me", " url : https://example.com ", ""})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(map[string]string{
+				"owner": "me",
+				"url":   "https://example.com",
+			}))
+		})
+
+		It("rejects tags without a value and repeated keys", func() {
+			_, err := tags.Parse([]string{"owner"})
+			Expect(err).To(MatchError(ContainSubstring("expected the 'key:value' format")))
+			_, err = tags.Parse([]string{"owner:me", "owner:you"})
+			Expect(err).To(MatchError(ContainSubstring("key 'owner' is repeated")))
+		})
+	})
+
+	Context("Validate", func() {
+		It("accepts valid tags", func() {
+			Expect(tags.Validate(map[string]string{
+				"cost-center": "1234",
+				"Team Name":   "Platform @ HQ",
+				"app/owner":   "",
+			})).To(Succeed())
+		})
+
+		It("rejects reserved keys", func() {
+			Expect(tags.Validate(map[string]string{tags.ClusterID: "x"})).To(MatchError(
+				ContainSubstring("'rosa_cluster_id': it is reserved")))
+			Expect(tags.Validate(map[string]string{"AWS:foo": "x"})).To(MatchError(
+				ContainSubstring("'AWS:foo': it is reserved")))
+			Expect(tags.Validate(map[string]string{"kubernetes.io/cluster/abc": "owned"})).To(MatchError(
+				ContainSubstring("it is reserved")))
+		})
+
+		It("rejects invalid characters and lengths", func() {
+			Expect(tags.Validate(map[string]string{"owner!": "me"})).To(MatchError(
+				ContainSubstring("Invalid tag key 'owner!'")))
+			Expect(tags.Validate(map[string]string{"owner": "me;you"})).To(MatchError(
+				ContainSubstring("Invalid value for tag 'owner'")))
+			Expect(tags.Validate(map[string]string{strings.Repeat("k", 129): "v"})).To(MatchError(
+				ContainSubstring("longer than 128 characters")))
+			Expect(tags.Validate(map[string]string{"k": strings.Repeat("v", 257)})).To(MatchError(
+				ContainSubstring("longer than 256 characters")))
+		})
+
+		It("rejects more than 50 tags", func() {
+			many := map[string]string{}
+			for i := 0; i <= tags.MaxTags; i++ {
+				many[fmt.Sprintf("tag%d", i)] = "v"
+			}
+			Expect(tags.Validate(many)).To(MatchError(ContainSubstring("at most 50 are allowed")))
+		})
+	})
+
+	Context("WithoutReserved", func() {
+		It("removes the tags owned by ROSA, the service, OpenShift and AWS", func() {
+			Expect(tags.WithoutReserved(map[string]string{
+				"owner":                           "me",
+				tags.ClusterID:                    "123",
+				tags.RedHatManaged:                "true",
+				"kubernetes.io/cluster/mycluster": "owned",
+				"AWS:cloudformation:stack-name":   "mystack",
+			})).To(Equal(map[string]string{
+				"owner": "me",
+			}))
+		})
+	})
+
+	Context("Merge", func() {
+		It("adds the extra tags to the base tags", func() {
+			result, err := tags.Merge(map[string]string{"owner": "me"}, map[string]string{
+				"owner": "me",
+				"pool":  "gpu",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(map[string]string{"owner": "me", "pool": "gpu"}))
+		})
+
+		It("doesn't change the values of the base tags", func() {
+			_, err := tags.Merge(map[string]string{"owner": "me"}, map[string]string{"owner": "you"})
+			Expect(err).To(MatchError(ContainSubstring("'owner': it is already set to 'me'")))
+		})
+	})
+})
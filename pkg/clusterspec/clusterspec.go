@@ -0,0 +1,345 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to read declarative cluster spec files, which
+// can be given to 'rosa create cluster' instead of a long list of command line flags.
+
+package clusterspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CurrentVersion is the version of the spec file format understood by this client.
+const CurrentVersion = "v1"
+
+// EtcdEncryptionUsage is the help text of the 'etcd-encryption' flag, shared by the commands that
+// create clusters and profiles.
+const EtcdEncryptionUsage = "Enable etcd encryption for your cluster to provide an additional layer of data security."
+
+// File is the representation of a cluster spec file. It can be written either in YAML or in JSON,
+// and each of its fields maps onto a field of ocm.Spec and onto a 'rosa create cluster' flag.
+// Boolean fields are pointers so that an explicit 'false' can be told apart from a missing value,
+// and can override a 'true' coming from a profile.
+type File struct {
+	Version          string            `json:"version" yaml:"version"`
+	Name             string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Region           string            `json:"region,omitempty" yaml:"region,omitempty"`
+	MultiAZ          *bool             `json:"multiAZ,omitempty" yaml:"multiAZ,omitempty"`
+	OpenShiftVersion string            `json:"openshiftVersion,omitempty" yaml:"openshiftVersion,omitempty"`
+	ChannelGroup     string            `json:"channelGroup,omitempty" yaml:"channelGroup,omitempty"`
+	EtcdEncryption   *bool             `json:"etcdEncryption,omitempty" yaml:"etcdEncryption,omitempty"`
+	KMSKeyARN        string            `json:"kmsKeyARN,omitempty" yaml:"kmsKeyARN,omitempty"`
+	Tags             map[string]string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Compute          *Compute          `json:"compute,omitempty" yaml:"compute,omitempty"`
+	Network          *Network          `json:"network,omitempty" yaml:"network,omitempty"`
+	STS              *STS              `json:"sts,omitempty" yaml:"sts,omitempty"`
+}
+
+// Compute contains the settings of the default machine pool of the cluster.
+type Compute struct {
+	MachineType string       `json:"machineType,omitempty" yaml:"machineType,omitempty"`
+	Nodes       int          `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+	Autoscaling *Autoscaling `json:"autoscaling,omitempty" yaml:"autoscaling,omitempty"`
+}
+
+// Autoscaling enables autoscaling of the compute nodes within the given range.
+type Autoscaling struct {
+	MinReplicas int `json:"minReplicas,omitempty" yaml:"minReplicas,omitempty"`
+	MaxReplicas int `json:"maxReplicas,omitempty" yaml:"maxReplicas,omitempty"`
+}
+
+// Network contains the networking settings of the cluster.
+type Network struct {
+	MachineCIDR string   `json:"machineCIDR,omitempty" yaml:"machineCIDR,omitempty"`
+	ServiceCIDR string   `json:"serviceCIDR,omitempty" yaml:"serviceCIDR,omitempty"`
+	PodCIDR     string   `json:"podCIDR,omitempty" yaml:"podCIDR,omitempty"`
+	HostPrefix  int      `json:"hostPrefix,omitempty" yaml:"hostPrefix,omitempty"`
+	Private     *bool    `json:"private,omitempty" yaml:"private,omitempty"`
+	PrivateLink *bool    `json:"privateLink,omitempty" yaml:"privateLink,omitempty"`
+	SubnetIDs   []string `json:"subnetIDs,omitempty" yaml:"subnetIDs,omitempty"`
+
+	// AvailabilityZones is only used when the cluster isn't installed into existing subnets,
+	// otherwise the zones are derived from the subnets.
+	AvailabilityZones []string `json:"availabilityZones,omitempty" yaml:"availabilityZones,omitempty"`
+
+	// Cluster-wide proxy, only used when the cluster is installed into existing subnets.
+	HTTPProxy  string `json:"httpProxy,omitempty" yaml:"httpProxy,omitempty"`
+	HTTPSProxy string `json:"httpsProxy,omitempty" yaml:"httpsProxy,omitempty"`
+	NoProxy    string `json:"noProxy,omitempty" yaml:"noProxy,omitempty"`
+
+	// Path of a PEM file with additional certificates trusted by the nodes.
+	TrustBundleFile string `json:"additionalTrustBundleFile,omitempty" yaml:"additionalTrustBundleFile,omitempty"`
+}
+
+// STS contains the roles used by STS clusters.
+type STS struct {
+	RoleARN        string         `json:"roleARN,omitempty" yaml:"roleARN,omitempty"`
+	ExternalID     string         `json:"externalID,omitempty" yaml:"externalID,omitempty"`
+	SupportRoleARN string         `json:"supportRoleARN,omitempty" yaml:"supportRoleARN,omitempty"`
+	MasterRoleARN  string         `json:"masterRoleARN,omitempty" yaml:"masterRoleARN,omitempty"`
+	WorkerRoleARN  string         `json:"workerRoleARN,omitempty" yaml:"workerRoleARN,omitempty"`
+	OperatorRoles  []OperatorRole `json:"operatorRoles,omitempty" yaml:"operatorRoles,omitempty"`
+}
+
+// OperatorRole is the IAM role used by an OpenShift operator to perform credential requests.
+type OperatorRole struct {
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	RoleARN   string `json:"roleARN" yaml:"roleARN"`
+}
+
+// Formats that the spec file can be written in.
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// FlagValue is the value of a single 'rosa create cluster' command line flag.
+type FlagValue struct {
+	Name  string
+	Value string
+}
+
+// Load reads and parses the cluster spec file stored in the given path.
+func Load(path string) (*File, error) {
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read spec file '%s': %v", path, err)
+	}
+	file, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse spec file '%s': %v", path, err)
+	}
+	return file, nil
+}
+
+// Parse parses the given YAML or JSON cluster spec. Keys that aren't part of the format are
+// rejected, and the error reports the line where they were found.
+func Parse(data []byte) (*File, error) {
+	file := new(File)
+	err := yaml.UnmarshalStrict(data, file)
+	if err != nil {
+		return nil, cleanError(err)
+	}
+	if file.Version == "" {
+		return nil, fmt.Errorf("Missing spec version, expected '%s'", CurrentVersion)
+	}
+	if file.Version != CurrentVersion {
+		return nil, fmt.Errorf("Unsupported spec version '%s', expected '%s'", file.Version, CurrentVersion)
+	}
+	return file, nil
+}
+
+// Marshal writes the spec file in the given format, either YAML or JSON.
+func (f *File) Marshal(format string) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(f)
+	case FormatJSON:
+		data, err := json.MarshalIndent(f, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("Unknown spec format '%s'", format)
+	}
+}
+
+// FlagValues returns the 'rosa create cluster' flags that are equivalent to the content of the
+// file. Flags that can be repeated, like 'operator-iam-roles', are returned once per value. Fields
+// that aren't set don't produce any flag.
+func (f *File) FlagValues() []FlagValue {
+	values := []FlagValue{}
+	add := func(name string, value string) {
+		values = append(values, FlagValue{Name: name, Value: value})
+	}
+
+	if f.Name != "" {
+		add("cluster-name", f.Name)
+	}
+	if f.Region != "" {
+		add("region", f.Region)
+	}
+	if f.MultiAZ != nil {
+		add("multi-az", strconv.FormatBool(*f.MultiAZ))
+	}
+	if f.OpenShiftVersion != "" {
+		add("version", strings.TrimPrefix(f.OpenShiftVersion, "openshift-v"))
+	}
+	if f.ChannelGroup != "" {
+		add("channel-group", f.ChannelGroup)
+	}
+	if f.EtcdEncryption != nil {
+		add("etcd-encryption", strconv.FormatBool(*f.EtcdEncryption))
+	}
+	if f.KMSKeyARN != "" {
+		add("kms-key-arn", f.KMSKeyARN)
+	}
+	if len(f.Tags) > 0 {
+		keys := []string{}
+		for k := range f.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tags := []string{}
+		for _, k := range keys {
+			tags = append(tags, fmt.Sprintf("%s:%s", k, f.Tags[k]))
+		}
+		add("tags", strings.Join(tags, ","))
+	}
+
+	if f.Compute != nil {
+		if f.Compute.MachineType != "" {
+			add("compute-machine-type", f.Compute.MachineType)
+		}
+		if f.Compute.Nodes != 0 {
+			add("compute-nodes", strconv.Itoa(f.Compute.Nodes))
+		}
+		if f.Compute.Autoscaling != nil {
+			add("enable-autoscaling", "true")
+			if f.Compute.Autoscaling.MinReplicas != 0 {
+				add("min-replicas", strconv.Itoa(f.Compute.Autoscaling.MinReplicas))
+			}
+			if f.Compute.Autoscaling.MaxReplicas != 0 {
+				add("max-replicas", strconv.Itoa(f.Compute.Autoscaling.MaxReplicas))
+			}
+		}
+	}
+
+	if f.Network != nil {
+		if f.Network.MachineCIDR != "" {
+			add("machine-cidr", f.Network.MachineCIDR)
+		}
+		if f.Network.ServiceCIDR != "" {
+			add("service-cidr", f.Network.ServiceCIDR)
+		}
+		if f.Network.PodCIDR != "" {
+			add("pod-cidr", f.Network.PodCIDR)
+		}
+		if f.Network.HostPrefix != 0 {
+			add("host-prefix", strconv.Itoa(f.Network.HostPrefix))
+		}
+		if f.Network.Private != nil {
+			add("private", strconv.FormatBool(*f.Network.Private))
+		}
+		if f.Network.PrivateLink != nil {
+			add("private-link", strconv.FormatBool(*f.Network.PrivateLink))
+		}
+		if len(f.Network.SubnetIDs) > 0 {
+			add("subnet-ids", strings.Join(f.Network.SubnetIDs, ","))
+		}
+		if f.Network.HTTPProxy != "" {
+			add("http-proxy", f.Network.HTTPProxy)
+		}
+		if f.Network.HTTPSProxy != "" {
+			add("https-proxy", f.Network.HTTPSProxy)
+		}
+		if f.Network.NoProxy != "" {
+			add("no-proxy", f.Network.NoProxy)
+		}
+		if f.Network.TrustBundleFile != "" {
+			add("additional-trust-bundle-file", f.Network.TrustBundleFile)
+		}
+	}
+
+	if f.STS != nil {
+		if f.STS.RoleARN != "" {
+			add("role-arn", f.STS.RoleARN)
+		}
+		if f.STS.ExternalID != "" {
+			add("external-id", f.STS.ExternalID)
+		}
+		if f.STS.SupportRoleARN != "" {
+			add("support-role-arn", f.STS.SupportRoleARN)
+		}
+		if f.STS.MasterRoleARN != "" {
+			add("master-iam-role", f.STS.MasterRoleARN)
+		}
+		if f.STS.WorkerRoleARN != "" {
+			add("worker-iam-role", f.STS.WorkerRoleARN)
+		}
+		for _, role := range f.STS.OperatorRoles {
+			add("operator-iam-roles", fmt.Sprintf("%s,%s,%s", role.Name, role.Namespace, role.RoleARN))
+		}
+	}
+
+	return values
+}
+
+// MissingFlagValues returns the flag values of the spec that should be applied on top of the flags
+// that are already set, which are given with their values. Values of flags that are already set
+// are skipped, and so are the values that would conflict with them, so that the command line, or
+// a source with higher precedence, wins. For example, when '--compute-nodes' is given the
+// autoscaling settings of the spec are ignored.
+func (f *File) MissingFlagValues(set map[string]string) []FlagValue {
+	skip := map[string]bool{}
+	for name := range set {
+		skip[name] = true
+	}
+	if _, ok := set["compute-nodes"]; ok {
+		skip["enable-autoscaling"] = true
+		skip["min-replicas"] = true
+		skip["max-replicas"] = true
+	}
+	_, minSet := set["min-replicas"]
+	_, maxSet := set["max-replicas"]
+	if minSet || maxSet {
+		skip["compute-nodes"] = true
+	}
+	if autoscaling, ok := set["enable-autoscaling"]; ok {
+		skip["compute-nodes"] = true
+		if autoscaling == "false" {
+			skip["min-replicas"] = true
+			skip["max-replicas"] = true
+		}
+	}
+
+	values := []FlagValue{}
+	for _, value := range f.FlagValues() {
+		if !skip[value.Name] {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// cleanError removes the noise that the YAML library adds to its errors, so that only the
+// offending lines are reported to the user.
+func cleanError(err error) error {
+	msg := err.Error()
+	msg = strings.TrimPrefix(msg, "yaml: unmarshal errors:\n")
+	msg = strings.TrimPrefix(msg, "yaml: ")
+	lines := strings.Split(msg, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return fmt.Errorf("%s", strings.Join(lines, "; "))
+}
+
+// isTrue returns true if the given optional boolean is set to true.
+func isTrue(value *bool) bool {
+	return value != nil && *value
+}
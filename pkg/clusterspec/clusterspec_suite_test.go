@@ -0,0 +1,13 @@
+package clusterspec_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestClusterspec(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Clusterspec Suite")
+}
@@ -0,0 +1,197 @@
+package clusterspec_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+)
+
+var _ = Describe("Clusterspec", func() {
+	Context("Parse", func() {
+		It("parses a YAML spec", func() {
+			file, err := clusterspec.Parse([]byte(`
+version: v1
+name: mycluster
+region: us-east-2
+multiAZ: true
+compute:
+  machineType: m5.2xlarge
+  autoscaling:
+    minReplicas: 3
+    maxReplicas: 6
+network:
+  machineCIDR: 10.0.0.0/16
+  hostPrefix: 23
+`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.Name).To(Equal("mycluster"))
+			Expect(file.MultiAZ).NotTo(BeNil())
+			Expect(*file.MultiAZ).To(BeTrue())
+			Expect(file.Compute.Autoscaling.MaxReplicas).To(Equal(6))
+			Expect(file.Network.HostPrefix).To(Equal(23))
+		})
+
+		It("parses a JSON spec", func() {
+			file, err := clusterspec.Parse([]byte(`{
+  "version": "v1",
+  "name": "mycluster",
+  "sts": {
+    "roleARN": "arn:aws:iam::123456789012:role/installer",
+    "operatorRoles": [
+      {"name": "ebs", "namespace": "openshift-cluster-csi-drivers", "roleARN": "arn:aws:iam::123456789012:role/ebs"}
+    ]
+  }
+}`))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(file.STS.RoleARN).To(Equal("arn:aws:iam::123456789012:role/installer"))
+			Expect(file.STS.OperatorRoles).To(HaveLen(1))
+		})
+
+		It("rejects unknown keys reporting the line", func() {
+			_, err := clusterspec.Parse([]byte(`
+version: v1
+name: mycluster
+compute:
+  machinetype: m5.xlarge
+`))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("line 5"))
+			Expect(err.Error()).To(ContainSubstring("machinetype"))
+		})
+
+		It("rejects a missing version", func() {
+			_, err := clusterspec.Parse([]byte(`name: mycluster`))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects an unsupported version", func() {
+			_, err := clusterspec.Parse([]byte(`version: v2`))
+			Expect(err).To(MatchError(ContainSubstring("Unsupported spec version 'v2'")))
+		})
+	})
+
+	Context("FlagValues", func() {
+		It("maps the spec to create cluster flags", func() {
+			file := &clusterspec.File{
+				Version:          clusterspec.CurrentVersion,
+				Name:             "mycluster",
+				OpenShiftVersion: "openshift-v4.7.2",
+				Tags: map[string]string{
+					"owner": "me",
+					"env":   "dev",
+				},
+				Compute: &clusterspec.Compute{
+					Nodes: 3,
+				},
+				STS: &clusterspec.STS{
+					OperatorRoles: []clusterspec.OperatorRole{
+						{Name: "a", Namespace: "ns-a", RoleARN: "arn-a"},
+						{Name: "b", Namespace: "ns-b", RoleARN: "arn-b"},
+					},
+				},
+			}
+			Expect(file.FlagValues()).To(Equal([]clusterspec.FlagValue{
+				{Name: "cluster-name", Value: "mycluster"},
+				{Name: "version", Value: "4.7.2"},
+				{Name: "tags", Value: "env:dev,owner:me"},
+				{Name: "compute-nodes", Value: "3"},
+				{Name: "operator-iam-roles", Value: "a,ns-a,arn-a"},
+				{Name: "operator-iam-roles", Value: "b,ns-b,arn-b"},
+			}))
+		})
+	})
+
+	Context("MissingFlagValues", func() {
+		var file *clusterspec.File
+
+		BeforeEach(func() {
+			file = &clusterspec.File{
+				Version: clusterspec.CurrentVersion,
+				Name:    "mycluster",
+				Compute: &clusterspec.Compute{
+					Autoscaling: &clusterspec.Autoscaling{MinReplicas: 3, MaxReplicas: 6},
+				},
+			}
+		})
+
+		It("skips the flags that are already set", func() {
+			Expect(file.MissingFlagValues(map[string]string{
+				"cluster-name": "other",
+				"max-replicas": "9",
+			})).To(Equal([]clusterspec.FlagValue{
+				{Name: "enable-autoscaling", Value: "true"},
+				{Name: "min-replicas", Value: "3"},
+			}))
+		})
+
+		It("lets compute nodes override autoscaling", func() {
+			Expect(file.MissingFlagValues(map[string]string{
+				"compute-nodes": "4",
+			})).To(Equal([]clusterspec.FlagValue{
+				{Name: "cluster-name", Value: "mycluster"},
+			}))
+		})
+
+		It("lets autoscaling override compute nodes", func() {
+			file.Compute = &clusterspec.Compute{Nodes: 4}
+			Expect(file.MissingFlagValues(map[string]string{
+				"enable-autoscaling": "true",
+			})).To(Equal([]clusterspec.FlagValue{
+				{Name: "cluster-name", Value: "mycluster"},
+			}))
+		})
+
+		It("applies a profile below the command line and the spec file", func() {
+			// Flags given on the command line, and then set from a spec file with compute nodes:
+			set := map[string]string{
+				"cluster-name":  "mycluster",
+				"compute-nodes": "4",
+				"multi-az":      "true",
+			}
+			multiAZ := false
+			profile := &clusterspec.File{
+				Version: clusterspec.CurrentVersion,
+				MultiAZ: &multiAZ,
+				Compute: &clusterspec.Compute{
+					MachineType: "m5.2xlarge",
+					Autoscaling: &clusterspec.Autoscaling{MinReplicas: 3, MaxReplicas: 6},
+				},
+			}
+			Expect(profile.MissingFlagValues(set)).To(Equal([]clusterspec.FlagValue{
+				{Name: "compute-machine-type", Value: "m5.2xlarge"},
+			}))
+		})
+
+		It("lets a spec file disable what a profile enables", func() {
+			disabled := false
+			enabled := true
+			file.MultiAZ = &disabled
+			file.Network = &clusterspec.Network{Private: &disabled}
+			set := map[string]string{}
+			for _, value := range file.MissingFlagValues(set) {
+				set[value.Name] = value.Value
+			}
+			Expect(set).To(HaveKeyWithValue("multi-az", "false"))
+			Expect(set).To(HaveKeyWithValue("private", "false"))
+
+			profile := &clusterspec.File{
+				Version:        clusterspec.CurrentVersion,
+				MultiAZ:        &enabled,
+				EtcdEncryption: &enabled,
+				Network:        &clusterspec.Network{Private: &enabled},
+			}
+			Expect(profile.MissingFlagValues(set)).To(Equal([]clusterspec.FlagValue{
+				{Name: "etcd-encryption", Value: "true"},
+			}))
+		})
+
+		It("drops the replicas when autoscaling is disabled", func() {
+			Expect(file.MissingFlagValues(map[string]string{
+				"enable-autoscaling": "false",
+			})).To(Equal([]clusterspec.FlagValue{
+				{Name: "cluster-name", Value: "mycluster"},
+			}))
+		})
+	})
+})
@@ -0,0 +1,219 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to turn a cluster spec back into the input accepted by
+// 'rosa create cluster', either as a spec file or as a command line.
+
+package clusterspec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// FromSpec converts the given cluster spec into its spec file representation.
+func FromSpec(spec ocm.Spec) *File {
+	file := &File{
+		Version:          CurrentVersion,
+		Name:             spec.Name,
+		Region:           spec.Region,
+		MultiAZ:          &spec.MultiAZ,
+		OpenShiftVersion: strings.TrimPrefix(spec.Version, "openshift-v"),
+		EtcdEncryption:   &spec.EtcdEncryption,
+		KMSKeyARN:        spec.KMSKeyArn,
+	}
+	if spec.ChannelGroup != ocm.DefaultChannelGroup {
+		file.ChannelGroup = spec.ChannelGroup
+	}
+	if len(spec.Tags) > 0 {
+		file.Tags = spec.Tags
+	}
+
+	compute := &Compute{
+		MachineType: spec.ComputeMachineType,
+	}
+	if spec.Autoscaling {
+		compute.Autoscaling = &Autoscaling{
+			MinReplicas: spec.MinReplicas,
+			MaxReplicas: spec.MaxReplicas,
+		}
+	} else {
+		compute.Nodes = spec.ComputeNodes
+	}
+	if *compute != (Compute{}) {
+		file.Compute = compute
+	}
+
+	network := &Network{
+		HostPrefix:        spec.HostPrefix,
+		SubnetIDs:         spec.SubnetIds,
+		AvailabilityZones: spec.AvailabilityZones,
+	}
+	if !ocm.IsEmptyCIDR(spec.MachineCIDR) {
+		network.MachineCIDR = spec.MachineCIDR.String()
+	}
+	if !ocm.IsEmptyCIDR(spec.ServiceCIDR) {
+		network.ServiceCIDR = spec.ServiceCIDR.String()
+	}
+	if !ocm.IsEmptyCIDR(spec.PodCIDR) {
+		network.PodCIDR = spec.PodCIDR.String()
+	}
+	if spec.PrivateLink != nil && *spec.PrivateLink {
+		network.PrivateLink = spec.PrivateLink
+	} else if spec.Private != nil {
+		network.Private = spec.Private
+	}
+	if spec.HTTPProxy != nil {
+		network.HTTPProxy = *spec.HTTPProxy
+	}
+	if spec.HTTPSProxy != nil {
+		network.HTTPSProxy = *spec.HTTPSProxy
+	}
+	if spec.NoProxy != nil {
+		network.NoProxy = *spec.NoProxy
+	}
+	file.Network = network
+
+	if spec.RoleARN != "" {
+		file.STS = &STS{
+			RoleARN:        spec.RoleARN,
+			ExternalID:     spec.ExternalID,
+			SupportRoleARN: spec.SupportRoleARN,
+			MasterRoleARN:  spec.MasterRoleARN,
+			WorkerRoleARN:  spec.WorkerRoleARN,
+		}
+		for _, role := range spec.OperatorIAMRoles {
+			file.STS.OperatorRoles = append(file.STS.OperatorRoles, OperatorRole{
+				Name:      role.Name,
+				Namespace: role.Namespace,
+				RoleARN:   role.RoleARN,
+			})
+		}
+	}
+
+	return file
+}
+
+// BuildCommand returns the 'rosa create cluster' command line that creates a cluster with the
+// given spec.
+func BuildCommand(spec ocm.Spec) string {
+	command := "rosa create cluster"
+	command += fmt.Sprintf(" --cluster-name %s", spec.Name)
+	if spec.RoleARN != "" {
+		command += fmt.Sprintf(" --role-arn %s", spec.RoleARN)
+	}
+	if spec.ExternalID != "" {
+		command += fmt.Sprintf(" --external-id %s", spec.ExternalID)
+	}
+	if spec.SupportRoleARN != "" {
+		command += fmt.Sprintf(" --support-role-arn %s", spec.SupportRoleARN)
+	}
+	if len(spec.OperatorIAMRoles) > 0 {
+		for _, role := range spec.OperatorIAMRoles {
+			command += fmt.Sprintf(" --operator-iam-roles %s,%s,%s", role.Name, role.Namespace, role.RoleARN)
+		}
+	}
+	if spec.MasterRoleARN != "" {
+		command += fmt.Sprintf(" --master-iam-role %s", spec.MasterRoleARN)
+	}
+	if spec.WorkerRoleARN != "" {
+		command += fmt.Sprintf(" --worker-iam-role %s", spec.WorkerRoleARN)
+	}
+	if len(spec.Tags) > 0 {
+		keys := []string{}
+		for k := range spec.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		tags := []string{}
+		for _, k := range keys {
+			tags = append(tags, fmt.Sprintf("%s:%s", k, spec.Tags[k]))
+		}
+		command += fmt.Sprintf(" --tags %s", strings.Join(tags, ","))
+	}
+	if spec.MultiAZ {
+		command += " --multi-az"
+	}
+	if spec.Region != "" {
+		command += fmt.Sprintf(" --region %s", spec.Region)
+	}
+	if spec.DisableSCPChecks != nil && *spec.DisableSCPChecks {
+		command += " --disable-scp-checks"
+	}
+	if spec.Version != "" {
+		if spec.ChannelGroup != ocm.DefaultChannelGroup {
+			command += fmt.Sprintf(" --channel-group %s", spec.ChannelGroup)
+		}
+		command += fmt.Sprintf(" --version %s", strings.TrimPrefix(spec.Version, "openshift-v"))
+	}
+
+	if spec.Autoscaling {
+		command += " --enable-autoscaling"
+		if spec.MinReplicas > 0 {
+			command += fmt.Sprintf(" --min-replicas %d", spec.MinReplicas)
+		}
+		if spec.MaxReplicas > 0 {
+			command += fmt.Sprintf(" --max-replicas %d", spec.MaxReplicas)
+		}
+	} else {
+		if spec.ComputeNodes != 0 {
+			command += fmt.Sprintf(" --compute-nodes %d", spec.ComputeNodes)
+		}
+	}
+	if spec.ComputeMachineType != "" {
+		command += fmt.Sprintf(" --compute-machine-type %s", spec.ComputeMachineType)
+	}
+
+	if !ocm.IsEmptyCIDR(spec.MachineCIDR) {
+		command += fmt.Sprintf(" --machine-cidr %s", spec.MachineCIDR.String())
+	}
+	if !ocm.IsEmptyCIDR(spec.ServiceCIDR) {
+		command += fmt.Sprintf(" --service-cidr %s", spec.ServiceCIDR.String())
+	}
+	if !ocm.IsEmptyCIDR(spec.PodCIDR) {
+		command += fmt.Sprintf(" --pod-cidr %s", spec.PodCIDR.String())
+	}
+	if spec.HostPrefix != 0 {
+		command += fmt.Sprintf(" --host-prefix %d", spec.HostPrefix)
+	}
+	if spec.PrivateLink != nil && *spec.PrivateLink {
+		command += " --private-link"
+	} else if spec.Private != nil && *spec.Private {
+		command += " --private"
+	}
+	if len(spec.SubnetIds) > 0 {
+		command += fmt.Sprintf(" --subnet-ids %s", strings.Join(spec.SubnetIds, ","))
+	}
+	if spec.HTTPProxy != nil && *spec.HTTPProxy != "" {
+		command += fmt.Sprintf(" --http-proxy %s", *spec.HTTPProxy)
+	}
+	if spec.HTTPSProxy != nil && *spec.HTTPSProxy != "" {
+		command += fmt.Sprintf(" --https-proxy %s", *spec.HTTPSProxy)
+	}
+	if spec.NoProxy != nil && *spec.NoProxy != "" {
+		command += fmt.Sprintf(" --no-proxy %s", *spec.NoProxy)
+	}
+	if spec.EtcdEncryption {
+		command += " --etcd-encryption"
+	}
+	if spec.KMSKeyArn != "" {
+		command += fmt.Sprintf(" --kms-key-arn %s", spec.KMSKeyArn)
+	}
+	return command
+}
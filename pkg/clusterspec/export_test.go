@@ -0,0 +1,73 @@
+package clusterspec_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("Export", func() {
+	var spec ocm.Spec
+
+	BeforeEach(func() {
+		_, machineCIDR, _ := net.ParseCIDR("10.0.0.0/16")
+		privateLink := true
+		httpsProxy := "http://proxy.example.com:3128"
+		spec = ocm.Spec{
+			Name:              "mycluster",
+			Region:            "us-east-1",
+			MultiAZ:           true,
+			Version:           "openshift-v4.7.2",
+			ChannelGroup:      ocm.DefaultChannelGroup,
+			ComputeNodes:      3,
+			MachineCIDR:       *machineCIDR,
+			HostPrefix:        23,
+			PrivateLink:       &privateLink,
+			SubnetIds:         []string{"subnet-a", "subnet-b", "subnet-c"},
+			AvailabilityZones: []string{"us-east-1a", "us-east-1b", "us-east-1c"},
+			Tags:              map[string]string{"owner": "me", "env": "prod"},
+			KMSKeyArn:         "arn:aws:kms:us-east-1:123456789012:key/mykey",
+			HTTPSProxy:        &httpsProxy,
+			RoleARN:           "arn:aws:iam::123456789012:role/installer",
+			OperatorIAMRoles: []ocm.OperatorIAMRole{
+				{Name: "ebs", Namespace: "ns", RoleARN: "arn:aws:iam::123456789012:role/ebs"},
+			},
+		}
+	})
+
+	Context("FromSpec", func() {
+		It("produces a file that can be read back", func() {
+			for _, format := range []string{clusterspec.FormatYAML, clusterspec.FormatJSON} {
+				data, err := clusterspec.FromSpec(spec).Marshal(format)
+				Expect(err).NotTo(HaveOccurred())
+				file, err := clusterspec.Parse(data)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(file).To(Equal(clusterspec.FromSpec(spec)))
+				Expect(file.OpenShiftVersion).To(Equal("4.7.2"))
+				Expect(file.ChannelGroup).To(BeEmpty())
+				Expect(*file.Network.PrivateLink).To(BeTrue())
+				Expect(*file.MultiAZ).To(BeTrue())
+				Expect(*file.EtcdEncryption).To(BeFalse())
+				Expect(file.Network.MachineCIDR).To(Equal("10.0.0.0/16"))
+				Expect(file.Network.HTTPSProxy).To(Equal("http://proxy.example.com:3128"))
+			}
+		})
+	})
+
+	Context("BuildCommand", func() {
+		It("builds the create cluster command line", func() {
+			Expect(clusterspec.BuildCommand(spec)).To(Equal("rosa create cluster --cluster-name mycluster" +
+				" --role-arn arn:aws:iam::123456789012:role/installer" +
+				" --operator-iam-roles ebs,ns,arn:aws:iam::123456789012:role/ebs" +
+				" --tags env:prod,owner:me --multi-az --region us-east-1 --version 4.7.2" +
+				" --compute-nodes 3 --machine-cidr 10.0.0.0/16 --host-prefix 23 --private-link" +
+				" --subnet-ids subnet-a,subnet-b,subnet-c" +
+				" --https-proxy http://proxy.example.com:3128" +
+				" --kms-key-arn arn:aws:kms:us-east-1:123456789012:key/mykey"))
+		})
+	})
+})
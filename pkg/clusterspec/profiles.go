@@ -0,0 +1,88 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to store named cluster profiles, partial cluster specs
+// that hold the defaults of a kind of cluster and are applied with 'rosa create cluster'.
+
+package clusterspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+)
+
+var profileNameRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// ValidateProfileName checks that the name can be used for a profile.
+func ValidateProfileName(name string) error {
+	if !profileNameRE.MatchString(name) {
+		return fmt.Errorf("Profile name '%s' isn't valid: it must contain only lowercase letters, "+
+			"digits and dashes, and start and end with a letter or digit", name)
+	}
+	return nil
+}
+
+// LoadProfiles reads the profiles stored in the given file, indexed by name. The result is empty
+// if the file doesn't exist.
+func LoadProfiles(path string) (map[string]*File, error) {
+	profiles := map[string]*File{}
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read profiles file '%s': %v", path, err)
+	}
+	err = json.Unmarshal(data, &profiles)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse profiles file '%s': %v", path, err)
+	}
+	for name, profile := range profiles {
+		if profile == nil || profile.Version != CurrentVersion {
+			return nil, fmt.Errorf("Profile '%s' in file '%s' doesn't have version '%s'",
+				name, path, CurrentVersion)
+		}
+	}
+	return profiles, nil
+}
+
+// SaveProfiles writes the given profiles to the given file, replacing its content.
+func SaveProfiles(path string, profiles map[string]*File) error {
+	data, err := json.MarshalIndent(profiles, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal profiles: %v", err)
+	}
+	err = ioutil.WriteFile(path, append(data, '\n'), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write file '%s': %v", path, err)
+	}
+	return nil
+}
+
+// ProfileNames returns the names of the given profiles, sorted.
+func ProfileNames(profiles map[string]*File) []string {
+	names := []string{}
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
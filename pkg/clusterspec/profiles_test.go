@@ -0,0 +1,65 @@
+package clusterspec_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+)
+
+var _ = Describe("Profiles", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "profiles")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	It("returns no profiles if the file doesn't exist", func() {
+		profiles, err := clusterspec.LoadProfiles(filepath.Join(dir, "missing.json"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(profiles).To(BeEmpty())
+	})
+
+	It("saves and loads profiles", func() {
+		path := filepath.Join(dir, "profiles.json")
+		multiAZ := true
+		err := clusterspec.SaveProfiles(path, map[string]*clusterspec.File{
+			"prod": {
+				Version: clusterspec.CurrentVersion,
+				MultiAZ: &multiAZ,
+				Compute: &clusterspec.Compute{
+					MachineType: "m5.2xlarge",
+				},
+			},
+			"dev": {
+				Version:      clusterspec.CurrentVersion,
+				ChannelGroup: "candidate",
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		profiles, err := clusterspec.LoadProfiles(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(clusterspec.ProfileNames(profiles)).To(Equal([]string{"dev", "prod"}))
+		Expect(profiles["prod"].FlagValues()).To(Equal([]clusterspec.FlagValue{
+			{Name: "multi-az", Value: "true"},
+			{Name: "compute-machine-type", Value: "m5.2xlarge"},
+		}))
+	})
+
+	It("rejects invalid names", func() {
+		Expect(clusterspec.ValidateProfileName("prod-2")).To(Succeed())
+		Expect(clusterspec.ValidateProfileName("Prod")).NotTo(Succeed())
+		Expect(clusterspec.ValidateProfileName("-prod")).NotTo(Succeed())
+	})
+})
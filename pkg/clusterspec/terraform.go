@@ -0,0 +1,538 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to export a cluster, and the objects that belong to it, as
+// the HCL resources of the OCM Terraform provider.
+
+package clusterspec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// FormatTerraform is the format used to export a cluster as Terraform resources.
+const FormatTerraform = "terraform"
+
+// Types of the Terraform resources generated for each kind of object:
+const (
+	terraformCluster          = "ocm_cluster_rosa_classic"
+	terraformMachinePool      = "ocm_machine_pool"
+	terraformIdentityProvider = "ocm_identity_provider"
+	terraformIngress          = "ocm_ingress"
+	terraformAddOn            = "ocm_addon_installation"
+)
+
+// TerraformExport contains a cluster and the objects that are exported with it. Only the spec is
+// required, the other objects are empty when the cluster hasn't been created yet.
+type TerraformExport struct {
+	Spec              ocm.Spec
+	MachinePools      []*cmv1.MachinePool
+	IdentityProviders []*cmv1.IdentityProvider
+	Ingresses         []*cmv1.Ingress
+	AddOns            []*cmv1.AddOnInstallation
+}
+
+// Render returns the HCL resource blocks. Secrets, like the client secrets of the identity
+// providers, aren't returned by the API, so they are replaced by references to sensitive variables
+// that are declared at the beginning of the output.
+func (e *TerraformExport) Render() string {
+	variables := []string{}
+	variable := func(name string) string {
+		variables = append(variables, name)
+		return "var." + name
+	}
+
+	resources := []string{}
+	add := func(resourceType string, name string, body *hclBody) {
+		resources = append(resources, fmt.Sprintf("resource %s %s {\n%s}\n",
+			hclString(resourceType), hclString(name), body.render(1)))
+	}
+
+	clusterName := terraformName(e.Spec.Name)
+	clusterID := fmt.Sprintf("%s.%s.id", terraformCluster, clusterName)
+	add(terraformCluster, clusterName, e.clusterBody(variable))
+
+	for _, pool := range e.MachinePools {
+		// The default machine pool is part of the cluster resource:
+		if pool.ID() == "default" {
+			continue
+		}
+		add(terraformMachinePool, terraformName(pool.ID()), machinePoolBody(clusterID, pool))
+	}
+	for _, idp := range e.IdentityProviders {
+		name := terraformName(idp.Name())
+		add(terraformIdentityProvider, name, identityProviderBody(clusterID, name, idp, variable))
+	}
+	for _, ingress := range e.Ingresses {
+		name := terraformName(ingress.ID())
+		if ingress.Default() {
+			name = "default"
+		}
+		add(terraformIngress, name, ingressBody(clusterID, ingress))
+	}
+	for _, addOn := range e.AddOns {
+		add(terraformAddOn, terraformName(addOn.Addon().ID()), addOnBody(clusterID, addOn))
+	}
+
+	blocks := []string{}
+	for _, name := range variables {
+		body := &hclBody{}
+		body.set("type", "string")
+		body.set("sensitive", "true")
+		blocks = append(blocks, fmt.Sprintf("variable %s {\n%s}\n", hclString(name), body.render(1)))
+	}
+	blocks = append(blocks, resources...)
+	return strings.Join(blocks, "\n")
+}
+
+func (e *TerraformExport) clusterBody(variable func(string) string) *hclBody {
+	spec := e.Spec
+	body := &hclBody{}
+	body.setString("name", spec.Name)
+	body.setString("cloud_region", spec.Region)
+	body.setBool("multi_az", spec.MultiAZ)
+	if len(spec.AvailabilityZones) > 0 {
+		body.set("availability_zones", hclStringList(spec.AvailabilityZones))
+	}
+	if spec.Version != "" {
+		if spec.ChannelGroup != ocm.DefaultChannelGroup {
+			body.setString("channel_group", spec.ChannelGroup)
+		}
+		body.setString("version", strings.TrimPrefix(spec.Version, "openshift-v"))
+	}
+	if spec.DisableSCPChecks != nil && *spec.DisableSCPChecks {
+		body.setBool("disable_scp_checks", true)
+	}
+	if spec.EtcdEncryption {
+		body.setBool("etcd_encryption", true)
+	}
+	if spec.KMSKeyArn != "" {
+		body.setString("kms_key_arn", spec.KMSKeyArn)
+	}
+	if len(spec.Tags) > 0 {
+		body.set("tags", hclStringMap(spec.Tags))
+	}
+
+	if spec.ComputeMachineType != "" {
+		body.setString("compute_machine_type", spec.ComputeMachineType)
+	}
+	if spec.Autoscaling {
+		body.setBool("autoscaling_enabled", true)
+		if spec.MinReplicas > 0 {
+			body.setInt("min_replicas", spec.MinReplicas)
+		}
+		if spec.MaxReplicas > 0 {
+			body.setInt("max_replicas", spec.MaxReplicas)
+		}
+	} else if spec.ComputeNodes != 0 {
+		body.setInt("replicas", spec.ComputeNodes)
+	}
+
+	if !ocm.IsEmptyCIDR(spec.MachineCIDR) {
+		body.setString("machine_cidr", spec.MachineCIDR.String())
+	}
+	if !ocm.IsEmptyCIDR(spec.ServiceCIDR) {
+		body.setString("service_cidr", spec.ServiceCIDR.String())
+	}
+	if !ocm.IsEmptyCIDR(spec.PodCIDR) {
+		body.setString("pod_cidr", spec.PodCIDR.String())
+	}
+	if spec.HostPrefix != 0 {
+		body.setInt("host_prefix", spec.HostPrefix)
+	}
+	if spec.PrivateLink != nil && *spec.PrivateLink {
+		body.setBool("aws_private_link", true)
+	} else if spec.Private != nil && *spec.Private {
+		body.setBool("private", true)
+	}
+	if len(spec.SubnetIds) > 0 {
+		body.set("aws_subnet_ids", hclStringList(spec.SubnetIds))
+	}
+
+	proxy := &hclBody{}
+	if spec.HTTPProxy != nil && *spec.HTTPProxy != "" {
+		proxy.setString("http_proxy", *spec.HTTPProxy)
+	}
+	if spec.HTTPSProxy != nil && *spec.HTTPSProxy != "" {
+		proxy.setString("https_proxy", *spec.HTTPSProxy)
+	}
+	if spec.NoProxy != nil && *spec.NoProxy != "" {
+		proxy.setString("no_proxy", *spec.NoProxy)
+	}
+	if spec.AdditionalTrustBundle != nil && *spec.AdditionalTrustBundle != "" {
+		proxy.set("additional_trust_bundle",
+			variable(terraformName(spec.Name)+"_additional_trust_bundle"))
+	}
+	if len(proxy.items) > 0 {
+		body.block("proxy", proxy)
+	}
+
+	if spec.RoleARN != "" {
+		sts := &hclBody{}
+		sts.setString("role_arn", spec.RoleARN)
+		if spec.ExternalID != "" {
+			sts.setString("external_id", spec.ExternalID)
+		}
+		if spec.SupportRoleARN != "" {
+			sts.setString("support_role_arn", spec.SupportRoleARN)
+		}
+		if len(spec.OperatorIAMRoles) > 0 {
+			roles := []*hclBody{}
+			for _, role := range spec.OperatorIAMRoles {
+				object := &hclBody{}
+				object.setString("name", role.Name)
+				object.setString("namespace", role.Namespace)
+				object.setString("role_arn", role.RoleARN)
+				roles = append(roles, object)
+			}
+			sts.set("operator_iam_roles", hclObjectList(roles))
+		}
+		instanceRoles := &hclBody{}
+		if spec.MasterRoleARN != "" {
+			instanceRoles.setString("master_role_arn", spec.MasterRoleARN)
+		}
+		if spec.WorkerRoleARN != "" {
+			instanceRoles.setString("worker_role_arn", spec.WorkerRoleARN)
+		}
+		if len(instanceRoles.items) > 0 {
+			sts.block("instance_iam_roles", instanceRoles)
+		}
+		body.block("sts", sts)
+	}
+
+	return body
+}
+
+func machinePoolBody(clusterID string, pool *cmv1.MachinePool) *hclBody {
+	body := &hclBody{}
+	body.set("cluster", clusterID)
+	body.setString("name", pool.ID())
+	body.setString("machine_type", pool.InstanceType())
+	if pool.Autoscaling() != nil {
+		body.setBool("autoscaling_enabled", true)
+		body.setInt("min_replicas", pool.Autoscaling().MinReplicas())
+		body.setInt("max_replicas", pool.Autoscaling().MaxReplicas())
+	} else {
+		body.setInt("replicas", pool.Replicas())
+	}
+	if len(pool.Labels()) > 0 {
+		body.set("labels", hclStringMap(pool.Labels()))
+	}
+	if len(pool.Taints()) > 0 {
+		taints := []*hclBody{}
+		for _, taint := range pool.Taints() {
+			object := &hclBody{}
+			object.setString("key", taint.Key())
+			object.setString("value", taint.Value())
+			object.setString("schedule_type", taint.Effect())
+			taints = append(taints, object)
+		}
+		body.set("taints", hclObjectList(taints))
+	}
+	return body
+}
+
+func identityProviderBody(clusterID string, name string, idp *cmv1.IdentityProvider,
+	variable func(string) string) *hclBody {
+	body := &hclBody{}
+	body.set("cluster", clusterID)
+	body.setString("name", idp.Name())
+	if idp.MappingMethod() != "" {
+		body.setString("mapping_method", string(idp.MappingMethod()))
+	}
+
+	settings := &hclBody{}
+	setOptional := func(name string, value string) {
+		if value != "" {
+			settings.setString(name, value)
+		}
+	}
+	setList := func(name string, values []string) {
+		if len(values) > 0 {
+			settings.set(name, hclStringList(values))
+		}
+	}
+	clientSecret := func() {
+		settings.set("client_secret", variable(name+"_client_secret"))
+	}
+
+	switch idp.Type() {
+	case cmv1.IdentityProviderTypeGithub:
+		github := idp.Github()
+		settings.setString("client_id", github.ClientID())
+		clientSecret()
+		setOptional("hostname", github.Hostname())
+		setOptional("ca", github.CA())
+		setList("organizations", github.Organizations())
+		setList("teams", github.Teams())
+		body.block("github", settings)
+	case cmv1.IdentityProviderTypeGitlab:
+		gitlab := idp.Gitlab()
+		settings.setString("client_id", gitlab.ClientID())
+		clientSecret()
+		settings.setString("url", gitlab.URL())
+		setOptional("ca", gitlab.CA())
+		body.block("gitlab", settings)
+	case cmv1.IdentityProviderTypeGoogle:
+		google := idp.Google()
+		settings.setString("client_id", google.ClientID())
+		clientSecret()
+		setOptional("hosted_domain", google.HostedDomain())
+		body.block("google", settings)
+	case cmv1.IdentityProviderTypeLDAP:
+		ldap := idp.LDAP()
+		settings.setString("url", ldap.URL())
+		setOptional("bind_dn", ldap.BindDN())
+		if ldap.BindDN() != "" {
+			settings.set("bind_password", variable(name+"_bind_password"))
+		}
+		setOptional("ca", ldap.CA())
+		if ldap.Insecure() {
+			settings.setBool("insecure", true)
+		}
+		if attributes := ldap.Attributes(); attributes != nil {
+			object := &hclBody{}
+			for _, attribute := range []struct {
+				name   string
+				values []string
+			}{
+				{"id", attributes.ID()},
+				{"email", attributes.Email()},
+				{"name", attributes.Name()},
+				{"preferred_username", attributes.PreferredUsername()},
+			} {
+				if len(attribute.values) > 0 {
+					object.set(attribute.name, hclStringList(attribute.values))
+				}
+			}
+			settings.block("attributes", object)
+		}
+		body.block("ldap", settings)
+	case cmv1.IdentityProviderTypeOpenID:
+		openID := idp.OpenID()
+		settings.setString("client_id", openID.ClientID())
+		clientSecret()
+		settings.setString("issuer", openID.Issuer())
+		setOptional("ca", openID.CA())
+		setList("extra_scopes", openID.ExtraScopes())
+		if len(openID.ExtraAuthorizeParameters()) > 0 {
+			settings.set("extra_authorize_parameters", hclStringMap(openID.ExtraAuthorizeParameters()))
+		}
+		if claims := openID.Claims(); claims != nil {
+			object := &hclBody{}
+			for _, claim := range []struct {
+				name   string
+				values []string
+			}{
+				{"email", claims.Email()},
+				{"name", claims.Name()},
+				{"preferred_username", claims.PreferredUsername()},
+			} {
+				if len(claim.values) > 0 {
+					object.set(claim.name, hclStringList(claim.values))
+				}
+			}
+			settings.block("claims", object)
+		}
+		body.block("openid", settings)
+	case cmv1.IdentityProviderTypeHtpasswd:
+		settings.set("username", variable(name+"_username"))
+		settings.set("password", variable(name+"_password"))
+		body.block("htpasswd", settings)
+	}
+	return body
+}
+
+func ingressBody(clusterID string, ingress *cmv1.Ingress) *hclBody {
+	body := &hclBody{}
+	body.set("cluster", clusterID)
+	if ingress.Default() {
+		body.setBool("default", true)
+	}
+	body.setBool("private", ingress.Listening() == cmv1.ListeningMethodInternal)
+	if len(ingress.RouteSelectors()) > 0 {
+		body.set("route_selectors", hclStringMap(ingress.RouteSelectors()))
+	}
+	return body
+}
+
+func addOnBody(clusterID string, addOn *cmv1.AddOnInstallation) *hclBody {
+	body := &hclBody{}
+	body.set("cluster", clusterID)
+	body.setString("addon_id", addOn.Addon().ID())
+	parameters := map[string]string{}
+	addOn.Parameters().Each(func(parameter *cmv1.AddOnInstallationParameter) bool {
+		parameters[parameter.ID()] = parameter.Value()
+		return true
+	})
+	if len(parameters) > 0 {
+		body.set("parameters", hclStringMap(parameters))
+	}
+	return body
+}
+
+var terraformNameRE = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+var terraformNameStartRE = regexp.MustCompile(`^[A-Za-z_]`)
+
+// terraformName converts the given name into a valid Terraform identifier, replacing the
+// characters that aren't allowed with underscores. Identifiers can't start with a digit or a dash.
+func terraformName(name string) string {
+	name = terraformNameRE.ReplaceAllString(name, "_")
+	if !terraformNameStartRE.MatchString(name) {
+		name = "_" + name
+	}
+	return name
+}
+
+// hclBody is the body of an HCL block. It keeps the attributes and the nested blocks in the order
+// they were added, so that the output is stable.
+type hclBody struct {
+	items []hclItem
+}
+
+// hclItem is either an attribute, with its value already rendered as an HCL expression, or a
+// nested block.
+type hclItem struct {
+	name  string
+	value string
+	block *hclBody
+}
+
+func (b *hclBody) set(name string, value string) {
+	b.items = append(b.items, hclItem{name: name, value: value})
+}
+
+func (b *hclBody) setString(name string, value string) {
+	b.set(name, hclString(value))
+}
+
+func (b *hclBody) setBool(name string, value bool) {
+	b.set(name, fmt.Sprintf("%t", value))
+}
+
+func (b *hclBody) setInt(name string, value int) {
+	b.set(name, fmt.Sprintf("%d", value))
+}
+
+func (b *hclBody) block(name string, body *hclBody) {
+	b.items = append(b.items, hclItem{name: name, block: body})
+}
+
+// render writes the body with the given indentation level, aligning the equal signs of
+// consecutive attributes the same way that 'terraform fmt' does.
+func (b *hclBody) render(level int) string {
+	indent := strings.Repeat("  ", level)
+	var buffer strings.Builder
+	for i := 0; i < len(b.items); {
+		item := b.items[i]
+		if item.block != nil {
+			if i > 0 {
+				buffer.WriteString("\n")
+			}
+			fmt.Fprintf(&buffer, "%s%s {\n%s%s}\n", indent, item.name, item.block.render(level+1), indent)
+			i++
+			continue
+		}
+		// Find the run of attributes that are aligned together:
+		end := i
+		width := 0
+		for end < len(b.items) && b.items[end].block == nil {
+			if len(b.items[end].name) > width {
+				width = len(b.items[end].name)
+			}
+			end++
+		}
+		if i > 0 {
+			buffer.WriteString("\n")
+		}
+		for _, attribute := range b.items[i:end] {
+			value := strings.ReplaceAll(attribute.value, "\n", "\n"+indent)
+			fmt.Fprintf(&buffer, "%s%-*s = %s\n", indent, width, attribute.name, value)
+		}
+		i = end
+	}
+	return buffer.String()
+}
+
+// hclString returns the given string as a quoted HCL string, escaping the template sequences so
+// that they are taken literally.
+func hclString(value string) string {
+	var buffer strings.Builder
+	buffer.WriteString(`"`)
+	for i, r := range value {
+		switch {
+		case r == '"' || r == '\\':
+			buffer.WriteRune('\\')
+			buffer.WriteRune(r)
+		case r == '\n':
+			buffer.WriteString(`\n`)
+		case r == '\r':
+			buffer.WriteString(`\r`)
+		case r == '\t':
+			buffer.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&buffer, `\u%04x`, r)
+		case (r == '$' || r == '%') && strings.HasPrefix(value[i+1:], "{"):
+			buffer.WriteRune(r)
+			buffer.WriteRune(r)
+		default:
+			buffer.WriteRune(r)
+		}
+	}
+	buffer.WriteString(`"`)
+	return buffer.String()
+}
+
+func hclStringList(values []string) string {
+	quoted := []string{}
+	for _, value := range values {
+		quoted = append(quoted, hclString(value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// hclStringMap returns the map as a multi line HCL object, with the keys sorted and quoted, as
+// keys like label names aren't always valid identifiers.
+func hclStringMap(values map[string]string) string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	object := &hclBody{}
+	for _, key := range keys {
+		object.setString(hclString(key), values[key])
+	}
+	return "{\n" + object.render(1) + "}"
+}
+
+func hclObjectList(objects []*hclBody) string {
+	var buffer strings.Builder
+	buffer.WriteString("[\n")
+	for _, object := range objects {
+		fmt.Fprintf(&buffer, "  {\n%s  },\n", object.render(2))
+	}
+	buffer.WriteString("]")
+	return buffer.String()
+}
@@ -0,0 +1,134 @@
+package clusterspec_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("TerraformExport", func() {
+	var spec ocm.Spec
+
+	BeforeEach(func() {
+		_, machineCIDR, _ := net.ParseCIDR("10.0.0.0/16")
+		privateLink := true
+		spec = ocm.Spec{
+			Name:               "mycluster",
+			Region:             "us-east-1",
+			MultiAZ:            true,
+			Version:            "openshift-v4.7.11",
+			ChannelGroup:       ocm.DefaultChannelGroup,
+			ComputeMachineType: "m5.xlarge",
+			ComputeNodes:       3,
+			MachineCIDR:        *machineCIDR,
+			PrivateLink:        &privateLink,
+			SubnetIds:          []string{"subnet-1", "subnet-2"},
+			Tags:               map[string]string{"team": "sre", "cost-center": "${id}"},
+			RoleARN:            "arn:aws:iam::123456789012:role/installer",
+			OperatorIAMRoles: []ocm.OperatorIAMRole{{
+				Name:      "ebs-cloud-credentials",
+				Namespace: "openshift-cluster-csi-drivers",
+				RoleARN:   "arn:aws:iam::123456789012:role/csi",
+			}},
+			WorkerRoleARN: "arn:aws:iam::123456789012:role/worker",
+		}
+	})
+
+	It("renders the cluster", func() {
+		export := &clusterspec.TerraformExport{Spec: spec}
+		Expect(export.Render()).To(Equal(`resource "ocm_cluster_rosa_classic" "mycluster" {
+  name                 = "mycluster"
+  cloud_region         = "us-east-1"
+  multi_az             = true
+  version              = "4.7.11"
+  tags                 = {
+    "cost-center" = "$${id}"
+    "team"        = "sre"
+  }
+  compute_machine_type = "m5.xlarge"
+  replicas             = 3
+  machine_cidr         = "10.0.0.0/16"
+  aws_private_link     = true
+  aws_subnet_ids       = ["subnet-1", "subnet-2"]
+
+  sts {
+    role_arn           = "arn:aws:iam::123456789012:role/installer"
+    operator_iam_roles = [
+      {
+        name      = "ebs-cloud-credentials"
+        namespace = "openshift-cluster-csi-drivers"
+        role_arn  = "arn:aws:iam::123456789012:role/csi"
+      },
+    ]
+
+    instance_iam_roles {
+      worker_role_arn = "arn:aws:iam::123456789012:role/worker"
+    }
+  }
+}
+`))
+	})
+
+	It("renders the objects of the cluster and declares variables for the secrets", func() {
+		pool, err := cmv1.NewMachinePool().
+			ID("gpu").
+			InstanceType("p3.2xlarge").
+			Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(0).MaxReplicas(3)).
+			Labels(map[string]string{"node-role.kubernetes.io/gpu": ""}).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		idp, err := cmv1.NewIdentityProvider().
+			Name("my github").
+			Type(cmv1.IdentityProviderTypeGithub).
+			MappingMethod(cmv1.IdentityProviderMappingMethodClaim).
+			Github(cmv1.NewGithubIdentityProvider().ClientID("abc").Organizations("openshift")).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		export := &clusterspec.TerraformExport{
+			Spec:              ocm.Spec{Name: "mycluster", Region: "us-east-1"},
+			MachinePools:      []*cmv1.MachinePool{pool},
+			IdentityProviders: []*cmv1.IdentityProvider{idp},
+		}
+		Expect(export.Render()).To(Equal(`variable "my_github_client_secret" {
+  type      = string
+  sensitive = true
+}
+
+resource "ocm_cluster_rosa_classic" "mycluster" {
+  name         = "mycluster"
+  cloud_region = "us-east-1"
+  multi_az     = false
+}
+
+resource "ocm_machine_pool" "gpu" {
+  cluster             = ocm_cluster_rosa_classic.mycluster.id
+  name                = "gpu"
+  machine_type        = "p3.2xlarge"
+  autoscaling_enabled = true
+  min_replicas        = 0
+  max_replicas        = 3
+  labels              = {
+    "node-role.kubernetes.io/gpu" = ""
+  }
+}
+
+resource "ocm_identity_provider" "my_github" {
+  cluster        = ocm_cluster_rosa_classic.mycluster.id
+  name           = "my github"
+  mapping_method = "claim"
+
+  github {
+    client_id     = "abc"
+    client_secret = var.my_github_client_secret
+    organizations = ["openshift"]
+  }
+}
+`))
+	})
+})
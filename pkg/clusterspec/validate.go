@@ -0,0 +1,328 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the checks of cluster spec files that don't need to contact OCM or AWS, so
+// that they can be used to lint spec files before trying to create the clusters. The checks that
+// also apply to the values given to 'rosa create cluster' are exported, so that both use the same
+// rules.
+
+package clusterspec
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+
+	"github.com/openshift/rosa/pkg/aws"
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
+	"github.com/openshift/rosa/pkg/network"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var regionRE = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]+$`)
+
+var versionRE = regexp.MustCompile(`^[0-9]+\.[0-9]+\.[0-9]+(-[0-9A-Za-z.-]+)?$`)
+
+var subnetIDRE = regexp.MustCompile(`^subnet-([0-9a-f]{8}|[0-9a-f]{17})$`)
+
+// Validate checks the content of the spec file without contacting OCM or AWS, and returns all the
+// problems found. The result is empty if the spec is valid. Checks that need the services, like
+// the list of available versions or the existence of the subnets, are left to 'rosa create
+// cluster'.
+func (f *File) Validate() []string {
+	problems := []string{}
+	addf := func(format string, a ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, a...))
+	}
+
+	if f.Version != "" && f.Version != CurrentVersion {
+		addf("Unsupported spec version '%s', expected '%s'", f.Version, CurrentVersion)
+	}
+
+	if f.Name == "" {
+		addf("Cluster name is required")
+	} else if !ocm.IsValidClusterName(f.Name) {
+		addf("Cluster name '%s' must consist of no more than 15 lowercase alphanumeric characters "+
+			"or '-', start with a letter, and end with an alphanumeric character", f.Name)
+	}
+
+	if f.Region != "" && !regionRE.MatchString(f.Region) {
+		addf("Region '%s' isn't a valid AWS region name", f.Region)
+	}
+
+	version := strings.TrimPrefix(f.OpenShiftVersion, "openshift-v")
+	if version != "" && !versionRE.MatchString(version) {
+		addf("OpenShift version '%s' isn't valid, expected a version like '4.7.2'", f.OpenShiftVersion)
+	}
+
+	if f.KMSKeyARN != "" {
+		err := aws.ValidateKMSKeyARN(f.KMSKeyARN, f.Region)
+		if err != nil {
+			addf("%v", err)
+		}
+	}
+
+	if len(f.Tags) > 0 {
+		err := awstags.Validate(f.Tags)
+		if err != nil {
+			addf("%v", err)
+		}
+	}
+
+	maxReplicas := 0
+	if f.Compute != nil {
+		maxReplicas = f.validateCompute(addf)
+	}
+	f.validateNetwork(maxReplicas, addf)
+	if f.STS != nil {
+		f.validateSTS(version, addf)
+	}
+
+	return problems
+}
+
+// validateCompute checks the number of compute nodes against the rules for single and multi AZ
+// clusters, and returns the maximum number of nodes, used to check the capacity of the network.
+// Values that aren't set are replaced by the defaults that 'rosa create cluster' would use.
+func (f *File) validateCompute(addf func(string, ...interface{})) int {
+	minNodes := MinComputeNodes(isTrue(f.MultiAZ))
+
+	autoscaling := f.Compute.Autoscaling
+	if autoscaling == nil {
+		if f.Compute.Nodes == 0 {
+			return minNodes
+		}
+		err := ValidateComputeNodes(isTrue(f.MultiAZ), f.Compute.Nodes)
+		if err != nil {
+			addf("%v", err)
+		}
+		return f.Compute.Nodes
+	}
+
+	if f.Compute.Nodes != 0 {
+		addf("Compute nodes can't be set when autoscaling is enabled")
+	}
+	min := autoscaling.MinReplicas
+	if min == 0 {
+		min = minNodes
+	}
+	max := autoscaling.MaxReplicas
+	if max == 0 {
+		max = min
+	}
+	err := ValidateAutoscaling(isTrue(f.MultiAZ), min, max)
+	if err != nil {
+		addf("%v", err)
+	}
+	return max
+}
+
+// MinComputeNodes returns the minimum number of compute nodes of single or multi AZ clusters.
+func MinComputeNodes(multiAZ bool) int {
+	if multiAZ {
+		return 3
+	}
+	return 2
+}
+
+// ValidateComputeNodes checks the number of compute nodes of a cluster without autoscaling.
+func ValidateComputeNodes(multiAZ bool, nodes int) error {
+	return checkComputeNodes("number of compute nodes", multiAZ, nodes)
+}
+
+// ValidateAutoscaling checks the minimum and maximum number of compute nodes of a cluster with
+// autoscaling.
+func ValidateAutoscaling(multiAZ bool, minReplicas int, maxReplicas int) error {
+	err := checkComputeNodes("minimum number of compute nodes", multiAZ, minReplicas)
+	if err != nil {
+		return err
+	}
+	if maxReplicas < minReplicas {
+		return fmt.Errorf("The maximum number of compute nodes (%d) must be greater or equal to the "+
+			"minimum (%d)", maxReplicas, minReplicas)
+	}
+	return checkComputeNodes("maximum number of compute nodes", multiAZ, maxReplicas)
+}
+
+func checkComputeNodes(what string, multiAZ bool, nodes int) error {
+	minNodes := MinComputeNodes(multiAZ)
+	if nodes < minNodes {
+		return fmt.Errorf("The %s needs to be at least %d", what, minNodes)
+	}
+	if multiAZ && nodes%3 != 0 {
+		return fmt.Errorf("Multi AZ clusters require that the %s be a multiple of 3", what)
+	}
+	return nil
+}
+
+// validateNetwork checks that the CIDRs can be parsed and that they make a valid network plan,
+// and the options that are only allowed for clusters installed into existing subnets.
+func (f *File) validateNetwork(maxReplicas int, addf func(string, ...interface{})) {
+	settings := f.Network
+	if settings == nil {
+		settings = &Network{}
+	}
+
+	plan := network.DefaultPlan()
+	plan.MultiAZ = isTrue(f.MultiAZ)
+	plan.MaxReplicas = maxReplicas
+	validPlan := true
+	parseCIDR := func(name string, value string, target **net.IPNet) {
+		if value == "" {
+			return
+		}
+		_, cidr, err := net.ParseCIDR(value)
+		if err != nil {
+			addf("Invalid %s '%s': %v", name, value, err)
+			validPlan = false
+			return
+		}
+		*target = cidr
+	}
+	parseCIDR("machine CIDR", settings.MachineCIDR, &plan.MachineCIDR)
+	parseCIDR("service CIDR", settings.ServiceCIDR, &plan.ServiceCIDR)
+	parseCIDR("pod CIDR", settings.PodCIDR, &plan.PodCIDR)
+	if settings.HostPrefix != 0 {
+		plan.HostPrefix = settings.HostPrefix
+	}
+	if validPlan {
+		for _, message := range network.ValidatePlan(plan).Errors {
+			addf("Invalid network plan: %s", message)
+		}
+	}
+
+	for _, subnetID := range settings.SubnetIDs {
+		if !subnetIDRE.MatchString(subnetID) {
+			addf("'%s' isn't a valid subnet ID", subnetID)
+		}
+	}
+	existingVPC := len(settings.SubnetIDs) > 0
+	if isTrue(settings.PrivateLink) && !existingVPC {
+		addf("PrivateLink clusters must be installed into existing subnets")
+	}
+	if !existingVPC && len(settings.AvailabilityZones) > 0 {
+		zones := 1
+		if isTrue(f.MultiAZ) {
+			zones = 3
+		}
+		if len(settings.AvailabilityZones) != zones {
+			addf("Expected %d availability zones, got %d", zones, len(settings.AvailabilityZones))
+		}
+	}
+
+	err := ValidateProxy(existingVPC, settings.HTTPProxy, settings.HTTPSProxy, settings.NoProxy,
+		settings.TrustBundleFile)
+	if err != nil {
+		addf("%v", err)
+	}
+	if settings.TrustBundleFile != "" {
+		_, err = ocm.ReadTrustBundle(settings.TrustBundleFile)
+		if err != nil {
+			addf("Invalid additional trust bundle file '%s': %v", settings.TrustBundleFile, err)
+		}
+	}
+}
+
+// ValidateProxy checks the cluster-wide proxy settings, which can only be used when the cluster is
+// installed into existing subnets. The content of the trust bundle file is checked when it is
+// read.
+func ValidateProxy(existingVPC bool, httpProxy string, httpsProxy string, noProxy string,
+	trustBundleFile string) error {
+	proxy := httpProxy != "" || httpsProxy != "" || noProxy != "" || trustBundleFile != ""
+	if proxy && !existingVPC {
+		return errors.New("The cluster-wide proxy and the additional trust bundle can only be used " +
+			"when the cluster is installed into existing subnets")
+	}
+	return ocm.ValidateProxy(httpProxy, httpsProxy, noProxy)
+}
+
+// validateSTS checks the format of the ARNs of the roles, and that the operator roles are
+// complete and not repeated.
+func (f *File) validateSTS(version string, addf func(string, ...interface{})) {
+	sts := f.STS
+	if sts.RoleARN == "" {
+		addf("The installer role ARN is required for STS clusters")
+	}
+	for _, role := range []struct {
+		what  string
+		value string
+	}{
+		{"Installer role ARN", sts.RoleARN},
+		{"Support role ARN", sts.SupportRoleARN},
+		{"Master role ARN", sts.MasterRoleARN},
+		{"Worker role ARN", sts.WorkerRoleARN},
+	} {
+		err := ValidateRoleARN(role.what, role.value)
+		if err != nil {
+			addf("%v", err)
+		}
+	}
+
+	operatorRoles := []ocm.OperatorIAMRole{}
+	for _, role := range sts.OperatorRoles {
+		operatorRoles = append(operatorRoles, ocm.OperatorIAMRole{
+			Name:      role.Name,
+			Namespace: role.Namespace,
+			RoleARN:   role.RoleARN,
+		})
+	}
+	err := ValidateOperatorRoles(operatorRoles)
+	if err != nil {
+		addf("%v", err)
+	}
+
+	if version != "" && versionRE.MatchString(version) && !ocm.HasSTSSupport(version, f.ChannelGroup) {
+		addf("Version '%s' is not supported for STS clusters", version)
+	}
+}
+
+// ValidateRoleARN checks that the value, if set, is the ARN of an IAM role. The description of the
+// role is used in the error message.
+func ValidateRoleARN(what string, value string) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := arn.Parse(value)
+	if err != nil || parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		return fmt.Errorf("%s '%s' isn't a valid IAM role ARN", what, value)
+	}
+	return nil
+}
+
+// ValidateOperatorRoles checks that the operator roles have a name, a namespace and a valid role
+// ARN, and that they aren't repeated.
+func ValidateOperatorRoles(roles []ocm.OperatorIAMRole) error {
+	seen := map[string]bool{}
+	for i, role := range roles {
+		if role.Name == "" || role.Namespace == "" || role.RoleARN == "" {
+			return fmt.Errorf("Operator role %d must have a name, a namespace and a role ARN", i+1)
+		}
+		key := role.Namespace + "/" + role.Name
+		if seen[key] {
+			return fmt.Errorf("Operator role '%s' in namespace '%s' is repeated", role.Name, role.Namespace)
+		}
+		seen[key] = true
+		err := ValidateRoleARN(fmt.Sprintf("Operator role '%s' ARN", role.Name), role.RoleARN)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package clusterspec_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/clusterspec"
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("Validate", func() {
+	const roleARN = "arn:aws:iam::123456789012:role/ManagedOpenShift-Installer-Role"
+
+	var file *clusterspec.File
+	enabled := true
+
+	BeforeEach(func() {
+		file = &clusterspec.File{
+			Version:          clusterspec.CurrentVersion,
+			Name:             "mycluster",
+			Region:           "us-east-1",
+			OpenShiftVersion: "4.7.11",
+			Compute: &clusterspec.Compute{
+				Nodes: 2,
+			},
+			STS: &clusterspec.STS{
+				RoleARN: roleARN,
+				OperatorRoles: []clusterspec.OperatorRole{{
+					Name:      "ebs-cloud-credentials",
+					Namespace: "openshift-cluster-csi-drivers",
+					RoleARN:   "arn:aws:iam::123456789012:role/mycluster-openshift-cluster-csi-drivers",
+				}},
+			},
+		}
+	})
+
+	It("accepts a valid spec", func() {
+		Expect(file.Validate()).To(BeEmpty())
+	})
+
+	It("reports every problem found", func() {
+		file.Name = "MyCluster"
+		file.OpenShiftVersion = "latest"
+		file.MultiAZ = &enabled
+		file.Compute.Nodes = 4
+		file.KMSKeyARN = "arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+		file.Network = &clusterspec.Network{
+			MachineCIDR: "10.0.0.0/33",
+			SubnetIDs:   []string{"subnet-xyz"},
+		}
+		file.STS.SupportRoleARN = "arn:aws:iam::123456789012:user/support"
+		file.STS.OperatorRoles = append(file.STS.OperatorRoles, clusterspec.OperatorRole{
+			Name: "cloud-credentials",
+		})
+		Expect(file.Validate()).To(Equal([]string{
+			"Cluster name 'MyCluster' must consist of no more than 15 lowercase alphanumeric characters " +
+				"or '-', start with a letter, and end with an alphanumeric character",
+			"OpenShift version 'latest' isn't valid, expected a version like '4.7.2'",
+			"KMS key 'arn:aws:kms:eu-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab' is in " +
+				"region 'eu-west-1', but the cluster is in region 'us-east-1'",
+			"Multi AZ clusters require that the number of compute nodes be a multiple of 3",
+			"Invalid machine CIDR '10.0.0.0/33': invalid CIDR address: 10.0.0.0/33",
+			"'subnet-xyz' isn't a valid subnet ID",
+			"Support role ARN 'arn:aws:iam::123456789012:user/support' isn't a valid IAM role ARN",
+			"Operator role 2 must have a name, a namespace and a role ARN",
+		}))
+	})
+
+	It("checks the autoscaling range", func() {
+		file.MultiAZ = &enabled
+		file.Compute = &clusterspec.Compute{
+			Nodes: 3,
+			Autoscaling: &clusterspec.Autoscaling{
+				MinReplicas: 6,
+				MaxReplicas: 3,
+			},
+		}
+		Expect(file.Validate()).To(Equal([]string{
+			"Compute nodes can't be set when autoscaling is enabled",
+			"The maximum number of compute nodes (3) must be greater or equal to the minimum (6)",
+		}))
+	})
+
+	It("uses the defaults for the values that aren't set", func() {
+		file.MultiAZ = &enabled
+		file.Compute = &clusterspec.Compute{
+			Autoscaling: &clusterspec.Autoscaling{},
+		}
+		Expect(file.Validate()).To(BeEmpty())
+	})
+
+	It("only allows the proxy and PrivateLink with existing subnets", func() {
+		file.Network = &clusterspec.Network{
+			PrivateLink: &enabled,
+			HTTPProxy:   "http://proxy.example.com:3128",
+		}
+		Expect(file.Validate()).To(Equal([]string{
+			"PrivateLink clusters must be installed into existing subnets",
+			"The cluster-wide proxy and the additional trust bundle can only be used when the " +
+				"cluster is installed into existing subnets",
+		}))
+	})
+
+	It("shares the rules with 'rosa create cluster'", func() {
+		Expect(clusterspec.ValidateComputeNodes(false, 2)).To(Succeed())
+		Expect(clusterspec.ValidateComputeNodes(true, 2)).To(MatchError(
+			"The number of compute nodes needs to be at least 3"))
+		Expect(clusterspec.ValidateAutoscaling(true, 3, 4)).To(MatchError(
+			"Multi AZ clusters require that the maximum number of compute nodes be a multiple of 3"))
+		Expect(clusterspec.ValidateProxy(false, "", "", "", "ca.pem")).To(HaveOccurred())
+		Expect(clusterspec.ValidateRoleARN("Master role ARN", "arn:aws:iam::123456789012:user/me")).To(
+			MatchError("Master role ARN 'arn:aws:iam::123456789012:user/me' isn't a valid IAM role ARN"))
+		Expect(clusterspec.ValidateOperatorRoles([]ocm.OperatorIAMRole{
+			{Name: "ebs", Namespace: "csi", RoleARN: roleARN},
+			{Name: "ebs", Namespace: "csi", RoleARN: roleARN},
+		})).To(MatchError("Operator role 'ebs' in namespace 'csi' is repeated"))
+	})
+
+	It("checks the network plan", func() {
+		file.Network = &clusterspec.Network{
+			MachineCIDR: "10.128.0.0/16",
+		}
+		problems := file.Validate()
+		Expect(problems).To(HaveLen(1))
+		Expect(problems[0]).To(HavePrefix("Invalid network plan: "))
+	})
+})
@@ -0,0 +1,277 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to compute a rough estimate of the monthly AWS cost of
+// the resources of a cluster. It only covers the on demand price of the instances, volumes, NAT
+// gateways and load balancers, not data transfer nor the ROSA service fees.
+
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/openshift/rosa/assets"
+)
+
+const pricingPath = "templates/pricing/aws.json"
+
+// Size of the root volumes of the nodes, in GiB:
+const (
+	controlPlaneVolumeGB = 350
+	infraVolumeGB        = 300
+	computeVolumeGB      = 300
+)
+
+// Number of control plane nodes, and of infra nodes in single and multi AZ clusters.
+const (
+	controlPlaneNodes  = 3
+	singleAZInfraNodes = 2
+	multiAZInfraNodes  = 3
+)
+
+// Pricing contains the prices used to compute the estimates. Prices are those of the base
+// region, and are multiplied by the multiplier of the region of the cluster.
+type Pricing struct {
+	Currency           string             `json:"currency"`
+	BaseRegion         string             `json:"baseRegion"`
+	HoursPerMonth      float64            `json:"hoursPerMonth"`
+	RegionMultipliers  map[string]float64 `json:"regionMultipliers"`
+	InstanceHourly     map[string]float64 `json:"instanceHourly"`
+	VolumeGBMonthly    float64            `json:"volumeGBMonthly"`
+	NATGatewayHourly   float64            `json:"natGatewayHourly"`
+	LoadBalancerHourly float64            `json:"loadBalancerHourly"`
+}
+
+// LoadPricing reads the pricing table from the given file, or the one embedded in the binary if
+// the path is empty.
+func LoadPricing(path string) (*Pricing, error) {
+	var data []byte
+	var err error
+	if path == "" {
+		data, err = assets.Asset(pricingPath)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read pricing table: %v", err)
+	}
+	pricing := &Pricing{}
+	err = json.Unmarshal(data, pricing)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse pricing table: %v", err)
+	}
+	if pricing.HoursPerMonth <= 0 || len(pricing.InstanceHourly) == 0 {
+		return nil, fmt.Errorf("Pricing table doesn't contain hours per month or instance prices")
+	}
+	return pricing, nil
+}
+
+// ComputePool describes a group of compute nodes of the same instance type. Pools that don't
+// autoscale have the same minimum and maximum number of nodes.
+type ComputePool struct {
+	Name         string
+	InstanceType string
+	MinNodes     int
+	MaxNodes     int
+}
+
+// Cluster describes the resources of a cluster that have a cost.
+type Cluster struct {
+	Region       string
+	MultiAZ      bool
+	ComputePools []ComputePool
+
+	// Private clusters don't have the public API load balancer.
+	Private bool
+
+	// ExistingVPC is true when the cluster is installed into existing subnets, so the NAT
+	// gateways aren't created by the installer and aren't included in the estimate.
+	ExistingVPC bool
+}
+
+// LineItem is one of the resources of the estimate. The minimum and maximum quantities are
+// different only for autoscaling compute pools.
+type LineItem struct {
+	Description string
+	MinQuantity int
+	MaxQuantity int
+	UnitMonthly float64
+}
+
+// MinMonthly returns the monthly cost of the minimum quantity.
+func (i *LineItem) MinMonthly() float64 {
+	return float64(i.MinQuantity) * i.UnitMonthly
+}
+
+// MaxMonthly returns the monthly cost of the maximum quantity.
+func (i *LineItem) MaxMonthly() float64 {
+	return float64(i.MaxQuantity) * i.UnitMonthly
+}
+
+// Estimate is the result of the estimation.
+type Estimate struct {
+	Currency string
+	Items    []*LineItem
+}
+
+// MinMonthly returns the total monthly cost with the minimum number of compute nodes.
+func (e *Estimate) MinMonthly() float64 {
+	total := 0.0
+	for _, item := range e.Items {
+		total += item.MinMonthly()
+	}
+	return total
+}
+
+// MaxMonthly returns the total monthly cost with the maximum number of compute nodes.
+func (e *Estimate) MaxMonthly() float64 {
+	total := 0.0
+	for _, item := range e.Items {
+		total += item.MaxMonthly()
+	}
+	return total
+}
+
+// Print writes the line items and the total in a table.
+func (e *Estimate) Print(out io.Writer) {
+	writer := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(writer, "ITEM\tQUANTITY\tUNIT/MONTH\tMONTHLY\n")
+	for _, item := range e.Items {
+		fmt.Fprintf(writer, "%s\t%s\t%.2f\t%s\n",
+			item.Description,
+			formatRange(float64(item.MinQuantity), float64(item.MaxQuantity), "%.0f"),
+			item.UnitMonthly,
+			formatRange(item.MinMonthly(), item.MaxMonthly(), "%.2f"),
+		)
+	}
+	fmt.Fprintf(writer, "TOTAL (%s)\t\t\t%s\n", e.Currency,
+		formatRange(e.MinMonthly(), e.MaxMonthly(), "%.2f"))
+	writer.Flush()
+}
+
+func formatRange(min float64, max float64, format string) string {
+	if min == max {
+		return fmt.Sprintf(format, min)
+	}
+	return fmt.Sprintf(format+" - "+format, min, max)
+}
+
+// EstimateCluster computes the monthly cost of the resources of the cluster.
+func EstimateCluster(pricing *Pricing, cluster *Cluster) (*Estimate, error) {
+	multiplier, ok := pricing.RegionMultipliers[cluster.Region]
+	if !ok {
+		return nil, fmt.Errorf("Pricing table doesn't contain region '%s'", cluster.Region)
+	}
+	hourly := func(price float64) float64 {
+		return price * multiplier * pricing.HoursPerMonth
+	}
+	instanceMonthly := func(instanceType string) (float64, error) {
+		price, ok := pricing.InstanceHourly[instanceType]
+		if !ok {
+			return 0, fmt.Errorf("Pricing table doesn't contain instance type '%s'", instanceType)
+		}
+		return hourly(price), nil
+	}
+
+	estimate := &Estimate{
+		Currency: pricing.Currency,
+	}
+	add := func(description string, min int, max int, unitMonthly float64) {
+		estimate.Items = append(estimate.Items, &LineItem{
+			Description: description,
+			MinQuantity: min,
+			MaxQuantity: max,
+			UnitMonthly: unitMonthly,
+		})
+	}
+
+	// The size of the control plane and infra nodes depends on the maximum size of the cluster:
+	minCompute := 0
+	maxCompute := 0
+	for _, pool := range cluster.ComputePools {
+		minCompute += pool.MinNodes
+		maxCompute += pool.MaxNodes
+	}
+	controlPlaneType, infraType := managementInstanceTypes(maxCompute)
+
+	infraNodes := singleAZInfraNodes
+	zones := 1
+	if cluster.MultiAZ {
+		infraNodes = multiAZInfraNodes
+		zones = 3
+	}
+
+	unitMonthly, err := instanceMonthly(controlPlaneType)
+	if err != nil {
+		return nil, err
+	}
+	add(fmt.Sprintf("Control plane nodes (%s)", controlPlaneType), controlPlaneNodes, controlPlaneNodes,
+		unitMonthly)
+	unitMonthly, err = instanceMonthly(infraType)
+	if err != nil {
+		return nil, err
+	}
+	add(fmt.Sprintf("Infra nodes (%s)", infraType), infraNodes, infraNodes, unitMonthly)
+
+	pools := append([]ComputePool{}, cluster.ComputePools...)
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].Name < pools[j].Name
+	})
+	for _, pool := range pools {
+		unitMonthly, err = instanceMonthly(pool.InstanceType)
+		if err != nil {
+			return nil, err
+		}
+		add(fmt.Sprintf("Compute nodes, pool '%s' (%s)", pool.Name, pool.InstanceType),
+			pool.MinNodes, pool.MaxNodes, unitMonthly)
+	}
+
+	volumeMonthly := pricing.VolumeGBMonthly * multiplier
+	fixedVolumes := controlPlaneNodes*controlPlaneVolumeGB + infraNodes*infraVolumeGB
+	add("Root volumes (GiB)", fixedVolumes+minCompute*computeVolumeGB,
+		fixedVolumes+maxCompute*computeVolumeGB, volumeMonthly)
+
+	if !cluster.ExistingVPC {
+		add("NAT gateways", zones, zones, hourly(pricing.NATGatewayHourly))
+	}
+
+	// Internal API and ingress load balancers, plus the public API one:
+	loadBalancers := 2
+	if !cluster.Private {
+		loadBalancers++
+	}
+	add("Load balancers", loadBalancers, loadBalancers, hourly(pricing.LoadBalancerHourly))
+
+	return estimate, nil
+}
+
+// managementInstanceTypes returns the instance types of the control plane and infra nodes, which
+// are larger for clusters with more compute nodes.
+func managementInstanceTypes(computeNodes int) (controlPlane string, infra string) {
+	switch {
+	case computeNodes <= 25:
+		return "m5.2xlarge", "r5.xlarge"
+	case computeNodes <= 100:
+		return "m5.4xlarge", "r5.2xlarge"
+	default:
+		return "m5.8xlarge", "r5.4xlarge"
+	}
+}
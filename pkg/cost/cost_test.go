@@ -0,0 +1,115 @@
+package cost_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/cost"
+)
+
+var _ = Describe("EstimateCluster", func() {
+	var pricing *cost.Pricing
+
+	BeforeEach(func() {
+		var err error
+		pricing, err = cost.LoadPricing("")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("estimates a single AZ cluster", func() {
+		estimate, err := cost.EstimateCluster(pricing, &cost.Cluster{
+			Region: "us-east-1",
+			ComputePools: []cost.ComputePool{
+				{Name: "default", InstanceType: "m5.xlarge", MinNodes: 2, MaxNodes: 2},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(estimate.Items).To(HaveLen(6))
+		Expect(estimate.MinMonthly()).To(BeNumerically("~", 1756.80, 0.01))
+		Expect(estimate.MaxMonthly()).To(Equal(estimate.MinMonthly()))
+	})
+
+	It("estimates a range for autoscaling multi AZ clusters", func() {
+		estimate, err := cost.EstimateCluster(pricing, &cost.Cluster{
+			Region:  "us-east-1",
+			MultiAZ: true,
+			Private: true,
+			ComputePools: []cost.ComputePool{
+				{Name: "default", InstanceType: "m5.xlarge", MinNodes: 3, MaxNodes: 9},
+			},
+			ExistingVPC: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		// Six more nodes with their volumes:
+		Expect(estimate.MaxMonthly() - estimate.MinMonthly()).To(
+			BeNumerically("~", 6*(0.192*730+300*0.08), 0.01))
+		for _, item := range estimate.Items {
+			Expect(item.Description).NotTo(Equal("NAT gateways"))
+		}
+
+		out := &bytes.Buffer{}
+		estimate.Print(out)
+		Expect(out.String()).To(ContainSubstring("Compute nodes, pool 'default' (m5.xlarge)  3 - 9"))
+		Expect(out.String()).To(ContainSubstring("Load balancers"))
+	})
+
+	It("applies the region multiplier", func() {
+		cluster := &cost.Cluster{
+			Region: "us-east-1",
+			ComputePools: []cost.ComputePool{
+				{Name: "default", InstanceType: "m5.xlarge", MinNodes: 2, MaxNodes: 2},
+			},
+		}
+		base, err := cost.EstimateCluster(pricing, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		cluster.Region = "sa-east-1"
+		regional, err := cost.EstimateCluster(pricing, cluster)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(regional.MinMonthly()).To(BeNumerically("~", base.MinMonthly()*1.59, 0.01))
+	})
+
+	It("fails for unknown regions and instance types", func() {
+		_, err := cost.EstimateCluster(pricing, &cost.Cluster{Region: "mars-1"})
+		Expect(err).To(MatchError("Pricing table doesn't contain region 'mars-1'"))
+
+		_, err = cost.EstimateCluster(pricing, &cost.Cluster{
+			Region: "us-east-1",
+			ComputePools: []cost.ComputePool{
+				{Name: "default", InstanceType: "x9.huge", MinNodes: 2, MaxNodes: 2},
+			},
+		})
+		Expect(err).To(MatchError("Pricing table doesn't contain instance type 'x9.huge'"))
+	})
+
+	It("reads the pricing table from a file", func() {
+		dir, err := ioutil.TempDir("", "pricing")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "pricing.json")
+		err = ioutil.WriteFile(path, []byte(`{
+			"currency": "EUR",
+			"hoursPerMonth": 730,
+			"regionMultipliers": {"eu-west-1": 1},
+			"instanceHourly": {"m5.2xlarge": 1, "r5.xlarge": 1, "m5.xlarge": 1}
+		}`), 0600)
+		Expect(err).NotTo(HaveOccurred())
+
+		pricing, err := cost.LoadPricing(path)
+		Expect(err).NotTo(HaveOccurred())
+		estimate, err := cost.EstimateCluster(pricing, &cost.Cluster{
+			Region: "eu-west-1",
+			ComputePools: []cost.ComputePool{
+				{Name: "default", InstanceType: "m5.xlarge", MinNodes: 2, MaxNodes: 2},
+			},
+			ExistingVPC: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(estimate.Currency).To(Equal("EUR"))
+		Expect(estimate.MinMonthly()).To(BeNumerically("~", 7*730, 0.01))
+	})
+})
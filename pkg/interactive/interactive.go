@@ -300,6 +300,12 @@ func GetCert(input Input) (a string, err error) {
 	return
 }
 
+// ValidateCertFile checks the path of a certificate file given with a flag, applying the same
+// checks as the interactive prompt.
+func ValidateCertFile(filepath string) error {
+	return certValidator(filepath)
+}
+
 // certValidator validates whether the given filepath is a valid cert file
 func certValidator(filepath interface{}) error {
 	if filepath == nil {
@@ -0,0 +1,165 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to validate and edit the Kubernetes labels of the nodes
+// of machine pools.
+
+package kube
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Limits of the length of the parts of the keys and of the values, in characters:
+const (
+	maxNameLength   = 63
+	maxPrefixLength = 253
+	maxValueLength  = 63
+)
+
+// Names and values start and end with an alphanumeric character, and can contain dashes,
+// underscores and dots in between. Prefixes are DNS subdomains.
+var (
+	nameRE   = regexp.MustCompile(`^([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9]$`)
+	prefixRE = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+)
+
+// ValidateKey checks that the key is a Kubernetes qualified name, an optional DNS subdomain
+// prefix followed by a slash and a name.
+func ValidateKey(key string) error {
+	name := key
+	if index := strings.Index(key, "/"); index >= 0 {
+		prefix := key[:index]
+		name = key[index+1:]
+		if prefix == "" || len(prefix) > maxPrefixLength || !prefixRE.MatchString(prefix) {
+			return fmt.Errorf("Invalid key '%s': the prefix must be a DNS subdomain of at most %d "+
+				"characters", key, maxPrefixLength)
+		}
+	}
+	if name == "" || len(name) > maxNameLength || !nameRE.MatchString(name) {
+		return fmt.Errorf("Invalid key '%s': the name must have at most %d characters, start and end "+
+			"with a letter or digit, and contain only letters, digits and the characters '-_.'",
+			key, maxNameLength)
+	}
+	return nil
+}
+
+// ValidateValue checks that the value can be used as the value of a label or of a taint. Empty
+// values are allowed.
+func ValidateValue(value string) error {
+	if value == "" {
+		return nil
+	}
+	if len(value) > maxValueLength || !nameRE.MatchString(value) {
+		return fmt.Errorf("Invalid value '%s': it must have at most %d characters, start and end "+
+			"with a letter or digit, and contain only letters, digits and the characters '-_.'",
+			value, maxValueLength)
+	}
+	return nil
+}
+
+// ParseLabels parses and validates a list of labels in the 'key=value' format. Empty items are
+// ignored.
+func ParseLabels(values []string) (map[string]string, error) {
+	result := map[string]string{}
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		index := strings.Index(value, "=")
+		if index < 0 {
+			return nil, fmt.Errorf("Invalid label '%s': expected the 'key=value' format", value)
+		}
+		key := strings.TrimSpace(value[:index])
+		if _, ok := result[key]; ok {
+			return nil, fmt.Errorf("Invalid labels: key '%s' is repeated", key)
+		}
+		err := ValidateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid label: %v", err)
+		}
+		labelValue := strings.TrimSpace(value[index+1:])
+		err = ValidateValue(labelValue)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid value for label '%s': %v", key, err)
+		}
+		result[key] = labelValue
+	}
+	return result, nil
+}
+
+// EditLabels returns the result of adding and removing labels from the given ones. Removing a
+// label that isn't set is an error, so that typos aren't silently ignored.
+func EditLabels(labels map[string]string, add map[string]string, remove []string) (map[string]string, error) {
+	result := map[string]string{}
+	for key, value := range labels {
+		result[key] = value
+	}
+	for _, key := range remove {
+		key = strings.TrimSpace(key)
+		if _, ok := add[key]; ok {
+			return nil, fmt.Errorf("Label '%s' can't be added and removed at the same time", key)
+		}
+		if _, ok := result[key]; !ok {
+			return nil, fmt.Errorf("Label '%s' isn't set", key)
+		}
+		delete(result, key)
+	}
+	for key, value := range add {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// LabelStrings returns the labels in the 'key=value' format, sorted.
+func LabelStrings(labels map[string]string) []string {
+	result := make([]string, 0, len(labels))
+	for key, value := range labels {
+		result = append(result, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Diff returns the items that are only in the 'before' list prefixed with '-', followed by the
+// items that are only in the 'after' list prefixed with '+'.
+func Diff(before []string, after []string) []string {
+	result := []string{}
+	for _, item := range before {
+		if !contains(after, item) {
+			result = append(result, "- "+item)
+		}
+	}
+	for _, item := range after {
+		if !contains(before, item) {
+			result = append(result, "+ "+item)
+		}
+	}
+	return result
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package kube_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/kube"
+)
+
+var _ = Describe("Labels", func() {
+	Context("ValidateKey", func() {
+		It("accepts qualified names", func() {
+			Expect(kube.ValidateKey("app")).To(Succeed())
+			Expect(kube.ValidateKey("node-role.kubernetes.io/db")).To(Succeed())
+			Expect(kube.ValidateKey("Example_Key.1")).To(Succeed())
+		})
+
+		It("rejects invalid names and prefixes", func() {
+			Expect(kube.ValidateKey("")).To(MatchError(ContainSubstring("the name must")))
+			Expect(kube.ValidateKey("-app")).To(MatchError(ContainSubstring("the name must")))
+			Expect(kube.ValidateKey("a b")).To(MatchError(ContainSubstring("the name must")))
+			Expect(kube.ValidateKey(strings.Repeat("a", 64))).To(MatchError(ContainSubstring("at most 63")))
+			Expect(kube.ValidateKey("Example.com/app")).To(MatchError(ContainSubstring("DNS subdomain")))
+			Expect(kube.ValidateKey("/app")).To(MatchError(ContainSubstring("DNS subdomain")))
+		})
+	})
+
+	Context("ParseLabels", func() {
+		It("parses and validates labels", func() {
+			labels, err := kube.ParseLabels([]string{"app=db", " tier = backend ", "", "empty="})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(labels).To(Equal(map[string]string{"app": "db", "tier": "backend", "empty": ""}))
+		})
+
+		It("rejects invalid labels", func() {
+			_, err := kube.ParseLabels([]string{"app"})
+			Expect(err).To(MatchError(ContainSubstring("expected the 'key=value' format")))
+			_, err = kube.ParseLabels([]string{"app=db", "app=web"})
+			Expect(err).To(MatchError(ContainSubstring("key 'app' is repeated")))
+			_, err = kube.ParseLabels([]string{"app=" + strings.Repeat("a", 64)})
+			Expect(err).To(MatchError(ContainSubstring("Invalid value for label 'app'")))
+			_, err = kube.ParseLabels([]string{"app=db/web"})
+			Expect(err).To(MatchError(ContainSubstring("Invalid value for label 'app'")))
+		})
+	})
+
+	Context("EditLabels", func() {
+		labels := map[string]string{"app": "db", "tier": "backend"}
+
+		It("adds, replaces and removes labels", func() {
+			result, err := kube.EditLabels(labels, map[string]string{"app": "web", "zone": "a"}, []string{"tier"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(map[string]string{"app": "web", "zone": "a"}))
+			Expect(labels).To(HaveLen(2))
+		})
+
+		It("rejects removing labels that aren't set", func() {
+			_, err := kube.EditLabels(labels, nil, []string{"tire"})
+			Expect(err).To(MatchError("Label 'tire' isn't set"))
+			_, err = kube.EditLabels(labels, map[string]string{"app": "web"}, []string{"app"})
+			Expect(err).To(MatchError(ContainSubstring("can't be added and removed")))
+		})
+	})
+
+	It("shows the differences", func() {
+		before := kube.LabelStrings(map[string]string{"app": "db", "tier": "backend"})
+		after := kube.LabelStrings(map[string]string{"app": "web", "tier": "backend"})
+		Expect(kube.Diff(before, after)).To(Equal([]string{"- app=db", "+ app=web"}))
+		Expect(kube.Diff(before, before)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,181 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to validate and edit the Kubernetes taints of the nodes
+// of machine pools.
+
+package kube
+
+import (
+	"fmt"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// Effects are the effects that Kubernetes allows for taints.
+var Effects = []string{
+	"NoSchedule",
+	"PreferNoSchedule",
+	"NoExecute",
+}
+
+// Taint is a Kubernetes taint of the nodes of a machine pool.
+type Taint struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+// String returns the taint in the 'key=value:Effect' format.
+func (t Taint) String() string {
+	return fmt.Sprintf("%s=%s:%s", t.Key, t.Value, t.Effect)
+}
+
+// Builder returns the builder of the taint for the OCM API.
+func (t Taint) Builder() *cmv1.TaintBuilder {
+	return cmv1.NewTaint().
+		Key(t.Key).
+		Value(t.Value).
+		Effect(t.Effect)
+}
+
+// FromTaints converts the taints returned by the OCM API.
+func FromTaints(taints []*cmv1.Taint) []Taint {
+	result := make([]Taint, 0, len(taints))
+	for _, taint := range taints {
+		result = append(result, Taint{
+			Key:    taint.Key(),
+			Value:  taint.Value(),
+			Effect: taint.Effect(),
+		})
+	}
+	return result
+}
+
+// Builders returns the builders of the taints for the OCM API.
+func Builders(taints []Taint) []*cmv1.TaintBuilder {
+	result := make([]*cmv1.TaintBuilder, 0, len(taints))
+	for _, taint := range taints {
+		result = append(result, taint.Builder())
+	}
+	return result
+}
+
+// ParseTaint parses and validates a taint in the 'key=value:Effect' format. The value may be
+// empty.
+func ParseTaint(value string) (Taint, error) {
+	value = strings.TrimSpace(value)
+	equals := strings.Index(value, "=")
+	colon := strings.LastIndex(value, ":")
+	if equals < 0 || colon < equals {
+		return Taint{}, fmt.Errorf("Invalid taint '%s': expected the 'key=value:Effect' format", value)
+	}
+	taint := Taint{
+		Key:    strings.TrimSpace(value[:equals]),
+		Value:  strings.TrimSpace(value[equals+1 : colon]),
+		Effect: strings.TrimSpace(value[colon+1:]),
+	}
+	err := ValidateKey(taint.Key)
+	if err != nil {
+		return Taint{}, fmt.Errorf("Invalid taint: %v", err)
+	}
+	err = ValidateValue(taint.Value)
+	if err != nil {
+		return Taint{}, fmt.Errorf("Invalid value for taint '%s': %v", taint.Key, err)
+	}
+	if !contains(Effects, taint.Effect) {
+		return Taint{}, fmt.Errorf("Invalid effect '%s' for taint '%s': it must be one of %s",
+			taint.Effect, taint.Key, strings.Join(Effects, ", "))
+	}
+	return taint, nil
+}
+
+// ParseTaints parses and validates a list of taints in the 'key=value:Effect' format. Empty items
+// are ignored, and a key can only be repeated with different effects.
+func ParseTaints(values []string) ([]Taint, error) {
+	result := []Taint{}
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		taint, err := ParseTaint(value)
+		if err != nil {
+			return nil, err
+		}
+		if findTaint(result, taint.Key, taint.Effect) >= 0 {
+			return nil, fmt.Errorf("Invalid taints: key '%s' with effect '%s' is repeated",
+				taint.Key, taint.Effect)
+		}
+		result = append(result, taint)
+	}
+	return result, nil
+}
+
+// EditTaints returns the result of adding and removing taints from the given ones. Added taints
+// replace the existing taints with the same key and effect. Taints are removed by key, or by key
+// and effect in the 'key:Effect' format, and removing a taint that isn't set is an error.
+func EditTaints(taints []Taint, add []Taint, remove []string) ([]Taint, error) {
+	result := append([]Taint{}, taints...)
+	for _, selector := range remove {
+		selector = strings.TrimSpace(selector)
+		key, effect := selector, ""
+		if index := strings.LastIndex(selector, ":"); index >= 0 {
+			key, effect = selector[:index], selector[index+1:]
+		}
+		for _, taint := range add {
+			if taint.Key == key && (effect == "" || taint.Effect == effect) {
+				return nil, fmt.Errorf("Taint '%s' can't be added and removed at the same time", selector)
+			}
+		}
+		kept := []Taint{}
+		for _, taint := range result {
+			if taint.Key != key || (effect != "" && taint.Effect != effect) {
+				kept = append(kept, taint)
+			}
+		}
+		if len(kept) == len(result) {
+			return nil, fmt.Errorf("Taint '%s' isn't set", selector)
+		}
+		result = kept
+	}
+	for _, taint := range add {
+		if index := findTaint(result, taint.Key, taint.Effect); index >= 0 {
+			result[index] = taint
+		} else {
+			result = append(result, taint)
+		}
+	}
+	return result, nil
+}
+
+// TaintStrings returns the taints in the 'key=value:Effect' format, in the same order.
+func TaintStrings(taints []Taint) []string {
+	result := make([]string, 0, len(taints))
+	for _, taint := range taints {
+		result = append(result, taint.String())
+	}
+	return result
+}
+
+func findTaint(taints []Taint, key string, effect string) int {
+	for i, taint := range taints {
+		if taint.Key == key && taint.Effect == effect {
+			return i
+		}
+	}
+	return -1
+}
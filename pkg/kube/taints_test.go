@@ -0,0 +1,72 @@
+package kube_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/kube"
+)
+
+var _ = Describe("Taints", func() {
+	Context("ParseTaints", func() {
+		It("parses and validates taints", func() {
+			taints, err := kube.ParseTaints([]string{"dedicated=db:NoSchedule", "", "gpu=:PreferNoSchedule"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taints).To(Equal([]kube.Taint{
+				{Key: "dedicated", Value: "db", Effect: "NoSchedule"},
+				{Key: "gpu", Value: "", Effect: "PreferNoSchedule"},
+			}))
+		})
+
+		It("rejects invalid taints", func() {
+			_, err := kube.ParseTaints([]string{"dedicated:NoSchedule"})
+			Expect(err).To(MatchError(ContainSubstring("expected the 'key=value:Effect' format")))
+			_, err = kube.ParseTaints([]string{"dedicated=db:NoSchedul"})
+			Expect(err).To(MatchError(ContainSubstring(
+				"Invalid effect 'NoSchedul' for taint 'dedicated': it must be one of NoSchedule")))
+			_, err = kube.ParseTaints([]string{"dedicated=db:NoSchedule", "dedicated=web:NoSchedule"})
+			Expect(err).To(MatchError(ContainSubstring("key 'dedicated' with effect 'NoSchedule' is repeated")))
+			_, err = kube.ParseTaints([]string{"-dedicated=db:NoSchedule"})
+			Expect(err).To(MatchError(ContainSubstring("Invalid taint: Invalid key '-dedicated'")))
+		})
+	})
+
+	Context("EditTaints", func() {
+		taints := []kube.Taint{
+			{Key: "dedicated", Value: "db", Effect: "NoSchedule"},
+			{Key: "dedicated", Value: "db", Effect: "NoExecute"},
+			{Key: "gpu", Value: "true", Effect: "NoSchedule"},
+		}
+
+		It("removes taints by key or by key and effect", func() {
+			result, err := kube.EditTaints(taints, nil, []string{"dedicated:NoExecute"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kube.TaintStrings(result)).To(Equal([]string{
+				"dedicated=db:NoSchedule",
+				"gpu=true:NoSchedule",
+			}))
+			result, err = kube.EditTaints(taints, nil, []string{"dedicated"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kube.TaintStrings(result)).To(Equal([]string{"gpu=true:NoSchedule"}))
+		})
+
+		It("replaces taints with the same key and effect", func() {
+			result, err := kube.EditTaints(taints, []kube.Taint{
+				{Key: "gpu", Value: "false", Effect: "NoSchedule"},
+				{Key: "spot", Value: "true", Effect: "PreferNoSchedule"},
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(kube.TaintStrings(result)).To(Equal([]string{
+				"dedicated=db:NoSchedule",
+				"dedicated=db:NoExecute",
+				"gpu=false:NoSchedule",
+				"spot=true:PreferNoSchedule",
+			}))
+		})
+
+		It("rejects removing taints that aren't set", func() {
+			_, err := kube.EditTaints(taints, nil, []string{"gpu:NoExecute"})
+			Expect(err).To(MatchError("Taint 'gpu:NoExecute' isn't set"))
+		})
+	})
+})
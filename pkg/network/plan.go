@@ -0,0 +1,209 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to check that the machine, service and pod CIDRs and
+// the host prefix of a cluster are consistent, and to compute the capacity that they allow.
+
+package network
+
+import (
+	"fmt"
+	"net"
+)
+
+// Default values used by the service when the cluster doesn't specify them:
+const (
+	DefaultMachineCIDR = "10.0.0.0/16"
+	DefaultServiceCIDR = "172.30.0.0/16"
+	DefaultPodCIDR     = "10.128.0.0/14"
+	DefaultHostPrefix  = 23
+)
+
+// Limits of the host prefix accepted by the service:
+const (
+	MinHostPrefix = 23
+	MaxHostPrefix = 26
+)
+
+// Maximum number of pods that the kubelet runs on a node.
+const maxPodsPerNode = 250
+
+// Number of addresses that AWS reserves in every subnet.
+const awsReservedAddresses = 5
+
+// Number of control plane nodes, and of infra nodes in single and multi AZ clusters.
+const (
+	controlPlaneNodes  = 3
+	singleAZInfraNodes = 2
+	multiAZInfraNodes  = 3
+)
+
+// reservedCIDRs are the blocks that are used by AWS, the hosts or the cluster network itself and
+// can't overlap with the networks of the cluster.
+var reservedCIDRs = []struct {
+	cidr        string
+	description string
+}{
+	{"127.0.0.0/8", "loopback"},
+	{"169.254.0.0/16", "link local and AWS instance metadata"},
+	{"100.64.0.0/16", "OVN-Kubernetes join network"},
+	{"224.0.0.0/4", "multicast"},
+}
+
+// Plan contains the networks of a cluster. The capacity checks use the maximum number of
+// compute nodes, which is ignored if it is zero.
+type Plan struct {
+	MachineCIDR *net.IPNet
+	ServiceCIDR *net.IPNet
+	PodCIDR     *net.IPNet
+	HostPrefix  int
+	MultiAZ     bool
+	MaxReplicas int
+}
+
+// Result contains the problems found in a plan and the capacity that it allows. The capacity is
+// only computed if there are no errors.
+type Result struct {
+	Errors   []string
+	Warnings []string
+
+	// MaxNodes is the maximum number of nodes, including control plane and infra nodes.
+	MaxNodes int
+
+	// PodsPerNode is the maximum number of pods that each node can run.
+	PodsPerNode int
+
+	// MachineIPsPerZone is the number of addresses usable by nodes in the private subnet that
+	// the installer creates in each availability zone.
+	MachineIPsPerZone int
+}
+
+// DefaultPlan returns a plan with the default networks of the service.
+func DefaultPlan() Plan {
+	_, machineCIDR, _ := net.ParseCIDR(DefaultMachineCIDR)
+	_, serviceCIDR, _ := net.ParseCIDR(DefaultServiceCIDR)
+	_, podCIDR, _ := net.ParseCIDR(DefaultPodCIDR)
+	return Plan{
+		MachineCIDR: machineCIDR,
+		ServiceCIDR: serviceCIDR,
+		PodCIDR:     podCIDR,
+		HostPrefix:  DefaultHostPrefix,
+	}
+}
+
+// ValidatePlan checks that the networks of the plan don't overlap with each other or with the
+// reserved ranges, that the host prefix fits in the pod CIDR and that the resulting capacity is
+// enough for the maximum number of compute nodes.
+func ValidatePlan(plan Plan) *Result {
+	result := &Result{}
+
+	networks := []struct {
+		name string
+		cidr *net.IPNet
+	}{
+		{"machine CIDR", plan.MachineCIDR},
+		{"service CIDR", plan.ServiceCIDR},
+		{"pod CIDR", plan.PodCIDR},
+	}
+	for i, network := range networks {
+		if network.cidr == nil || network.cidr.IP.To4() == nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s must be an IPv4 block", network.name))
+			continue
+		}
+		for _, other := range networks[:i] {
+			if other.cidr != nil && overlaps(network.cidr, other.cidr) {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s '%s' overlaps with %s '%s'",
+					network.name, network.cidr, other.name, other.cidr))
+			}
+		}
+		for _, reserved := range reservedCIDRs {
+			_, reservedCIDR, _ := net.ParseCIDR(reserved.cidr)
+			if overlaps(network.cidr, reservedCIDR) {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s '%s' overlaps with reserved range '%s' (%s)",
+					network.name, network.cidr, reserved.cidr, reserved.description))
+			}
+		}
+	}
+
+	if plan.HostPrefix < MinHostPrefix || plan.HostPrefix > MaxHostPrefix {
+		result.Errors = append(result.Errors, fmt.Sprintf("host prefix must be between %d and %d, got %d",
+			MinHostPrefix, MaxHostPrefix, plan.HostPrefix))
+	}
+	if plan.PodCIDR != nil {
+		podOnes, _ := plan.PodCIDR.Mask.Size()
+		if plan.HostPrefix < podOnes {
+			result.Errors = append(result.Errors, fmt.Sprintf("host prefix /%d doesn't fit in pod CIDR '%s'",
+				plan.HostPrefix, plan.PodCIDR))
+		}
+	}
+	if len(result.Errors) > 0 {
+		return result
+	}
+
+	zones := 1
+	infraNodes := singleAZInfraNodes
+	if plan.MultiAZ {
+		zones = 3
+		infraNodes = multiAZInfraNodes
+	}
+
+	// The installer splits the machine CIDR in two halves, for the private and the public
+	// subnets, and each half in a power of two number of subnets, at least two:
+	machineOnes, _ := plan.MachineCIDR.Mask.Size()
+	subnetBits := 1
+	for 1<<subnetBits < zones {
+		subnetBits++
+	}
+	subnetOnes := machineOnes + 1 + subnetBits
+	if subnetOnes <= 32 {
+		result.MachineIPsPerZone = 1<<(32-subnetOnes) - awsReservedAddresses
+	}
+	if result.MachineIPsPerZone <= 0 {
+		result.MachineIPsPerZone = 0
+		result.Errors = append(result.Errors, fmt.Sprintf("machine CIDR '%s' is too small for %d availability zones",
+			plan.MachineCIDR, zones))
+		return result
+	}
+
+	podOnes, _ := plan.PodCIDR.Mask.Size()
+	result.MaxNodes = 1 << (plan.HostPrefix - podOnes)
+	if machineNodes := zones * result.MachineIPsPerZone; machineNodes < result.MaxNodes {
+		result.MaxNodes = machineNodes
+	}
+
+	// The first address of the subnet of each node is the network address and the second one
+	// is used by the gateway:
+	result.PodsPerNode = 1<<(32-plan.HostPrefix) - 2
+	if result.PodsPerNode > maxPodsPerNode {
+		result.PodsPerNode = maxPodsPerNode
+	}
+
+	if plan.MaxReplicas > 0 {
+		needed := plan.MaxReplicas + controlPlaneNodes + infraNodes
+		if needed > result.MaxNodes {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(
+				"%d compute nodes plus %d control plane and %d infra nodes exceed the maximum of %d nodes "+
+					"allowed by the network plan", plan.MaxReplicas, controlPlaneNodes, infraNodes, result.MaxNodes))
+		}
+	}
+
+	return result
+}
+
+// overlaps returns true if the two blocks have at least one address in common.
+func overlaps(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
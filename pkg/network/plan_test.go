@@ -0,0 +1,87 @@
+package network_test
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/openshift/rosa/pkg/network"
+)
+
+var _ = Describe("ValidatePlan", func() {
+	cidr := func(value string) *net.IPNet {
+		_, result, err := net.ParseCIDR(value)
+		Expect(err).NotTo(HaveOccurred())
+		return result
+	}
+
+	It("computes the capacity of the default plan", func() {
+		result := network.ValidatePlan(network.DefaultPlan())
+		Expect(result.Errors).To(BeEmpty())
+		Expect(result.Warnings).To(BeEmpty())
+		// A /14 pod CIDR split in /23 subnets:
+		Expect(result.MaxNodes).To(Equal(512))
+		Expect(result.PodsPerNode).To(Equal(250))
+		// A /16 machine CIDR gives a /18 private subnet in the only zone:
+		Expect(result.MachineIPsPerZone).To(Equal(16379))
+	})
+
+	It("computes the capacity of a multi AZ plan", func() {
+		plan := network.DefaultPlan()
+		plan.MultiAZ = true
+		plan.MachineCIDR = cidr("10.0.0.0/24")
+		plan.HostPrefix = 26
+		result := network.ValidatePlan(plan)
+		Expect(result.Errors).To(BeEmpty())
+		// A /24 machine CIDR gives a /27 private subnet in each of the three zones:
+		Expect(result.MachineIPsPerZone).To(Equal(27))
+		Expect(result.MaxNodes).To(Equal(81))
+		Expect(result.PodsPerNode).To(Equal(62))
+	})
+
+	It("rejects overlapping networks", func() {
+		plan := network.DefaultPlan()
+		plan.MachineCIDR = cidr("172.30.0.0/24")
+		result := network.ValidatePlan(plan)
+		Expect(result.Errors).To(ConsistOf(
+			"service CIDR '172.30.0.0/16' overlaps with machine CIDR '172.30.0.0/24'",
+		))
+	})
+
+	It("rejects networks that overlap with reserved ranges", func() {
+		plan := network.DefaultPlan()
+		plan.PodCIDR = cidr("100.64.0.0/14")
+		result := network.ValidatePlan(plan)
+		Expect(result.Errors).To(ConsistOf(
+			"pod CIDR '100.64.0.0/14' overlaps with reserved range '100.64.0.0/16' (OVN-Kubernetes join network)",
+		))
+	})
+
+	It("rejects host prefixes that don't fit in the pod CIDR", func() {
+		plan := network.DefaultPlan()
+		plan.PodCIDR = cidr("10.128.0.0/24")
+		result := network.ValidatePlan(plan)
+		Expect(result.Errors).To(ConsistOf("host prefix /23 doesn't fit in pod CIDR '10.128.0.0/24'"))
+	})
+
+	It("rejects host prefixes out of the allowed range", func() {
+		plan := network.DefaultPlan()
+		plan.HostPrefix = 28
+		result := network.ValidatePlan(plan)
+		Expect(result.Errors).To(ConsistOf("host prefix must be between 23 and 26, got 28"))
+	})
+
+	It("warns if the maximum number of replicas exceeds the capacity", func() {
+		plan := network.DefaultPlan()
+		plan.PodCIDR = cidr("10.128.0.0/20")
+		plan.MaxReplicas = 4
+		result := network.ValidatePlan(plan)
+		Expect(result.Errors).To(BeEmpty())
+		Expect(result.MaxNodes).To(Equal(8))
+		Expect(result.Warnings).To(ConsistOf(
+			"4 compute nodes plus 3 control plane and 2 infra nodes exceed the maximum of 8 nodes " +
+				"allowed by the network plan",
+		))
+	})
+})
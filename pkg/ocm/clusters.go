@@ -26,6 +26,7 @@ import (
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 
 	"github.com/openshift/rosa/pkg/aws"
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
 	"github.com/openshift/rosa/pkg/info"
 	"github.com/openshift/rosa/pkg/logging"
 	"github.com/openshift/rosa/pkg/properties"
@@ -71,6 +72,16 @@ type Spec struct {
 	// User-defined tags for AWS resources
 	Tags map[string]string
 
+	// Customer managed KMS key used to encrypt the EBS volumes and, if enabled, the etcd data
+	KMSKeyArn string
+
+	// Cluster-wide proxy and additional trust bundle. Nil values are left unchanged, and empty
+	// values remove the setting when updating a cluster.
+	HTTPProxy             *string
+	HTTPSProxy            *string
+	NoProxy               *string
+	AdditionalTrustBundle *string
+
 	// Simulate creating a cluster but don't actually create it
 	DryRun *bool
 
@@ -140,6 +151,15 @@ func (c *Client) CreateCluster(config Spec) (*cmv1.Cluster, error) {
 		return nil, fmt.Errorf("Unable to create cluster spec: %v", err)
 	}
 
+	extra := clusterExtensions(config)
+	if len(extra) > 0 {
+		body, err := clusterJSON(spec, extra)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to create cluster spec: %v", err)
+		}
+		return c.addClusterJSON(body, config.DryRun != nil && *config.DryRun)
+	}
+
 	cluster, err := c.ocm.ClustersMgmt().V1().Clusters().
 		Add().
 		Parameter("dryRun", *config.DryRun).
@@ -160,6 +180,8 @@ func (c *Client) CreateCluster(config Spec) (*cmv1.Cluster, error) {
 	return clusterObject, nil
 }
 
+// GetClusters returns all the clusters created by the given creator. The clusters are fetched in
+// pages of the given size, requesting pages till one isn't full.
 func (c *Client) GetClusters(creator *aws.Creator, count int) (clusters []*cmv1.Cluster, err error) {
 	if count < 1 {
 		err = errors.New("Cannot fetch fewer than 1 cluster")
@@ -306,6 +328,15 @@ func (c *Client) UpdateCluster(clusterKey string, creator *aws.Creator, config S
 		return err
 	}
 
+	extra := clusterExtensions(config)
+	if len(extra) > 0 {
+		body, err := clusterJSON(clusterSpec, extra)
+		if err != nil {
+			return err
+		}
+		return c.updateClusterJSON(cluster.ID(), body)
+	}
+
 	response, err := c.ocm.ClustersMgmt().V1().Clusters().
 		Cluster(cluster.ID()).
 		Update().
@@ -335,6 +366,124 @@ func (c *Client) DeleteCluster(clusterKey string, creator *aws.Creator) (*cmv1.C
 	return cluster, nil
 }
 
+// GetClusterSpec reverse-maps an existing cluster into the spec that would be needed to create
+// a cluster with the same topology. The attributes that the SDK doesn't support yet are read from
+// the JSON representation of the cluster.
+func (c *Client) GetClusterSpec(cluster *cmv1.Cluster) (Spec, error) {
+	spec := clusterSpec(cluster)
+	object, err := c.getClusterJSON(cluster.ID())
+	if err != nil {
+		return spec, err
+	}
+	readClusterExtensions(&spec, object)
+	return spec, nil
+}
+
+// clusterSpec returns the spec of the cluster built from the attributes supported by the SDK.
+func clusterSpec(cluster *cmv1.Cluster) Spec {
+	spec := Spec{
+		Name:              cluster.Name(),
+		Region:            cluster.Region().ID(),
+		MultiAZ:           cluster.MultiAZ(),
+		ChannelGroup:      cluster.Version().ChannelGroup(),
+		EtcdEncryption:    cluster.EtcdEncryption(),
+		SubnetIds:         cluster.AWS().SubnetIDs(),
+		AvailabilityZones: cluster.Nodes().AvailabilityZones(),
+		HostPrefix:        cluster.Network().HostPrefix(),
+		Tags:              awstags.WithoutReserved(cluster.AWS().Tags()),
+		RoleARN:           cluster.AWS().STS().RoleARN(),
+		ExternalID:        cluster.AWS().STS().ExternalID(),
+		SupportRoleARN:    cluster.AWS().STS().SupportRoleARN(),
+		MasterRoleARN:     cluster.AWS().STS().InstanceIAMRoles().MasterRoleARN(),
+		WorkerRoleARN:     cluster.AWS().STS().InstanceIAMRoles().WorkerRoleARN(),
+	}
+
+	if cluster.Version().RawID() != "" {
+		spec.Version = "openshift-v" + cluster.Version().RawID()
+	}
+
+	spec.ComputeMachineType = cluster.Nodes().ComputeMachineType().ID()
+	if cluster.Nodes().AutoscaleCompute() != nil {
+		spec.Autoscaling = true
+		spec.MinReplicas = cluster.Nodes().AutoscaleCompute().MinReplicas()
+		spec.MaxReplicas = cluster.Nodes().AutoscaleCompute().MaxReplicas()
+	} else {
+		spec.ComputeNodes = cluster.Nodes().Compute()
+	}
+
+	for _, cidr := range []struct {
+		value  string
+		target *net.IPNet
+	}{
+		{cluster.Network().MachineCIDR(), &spec.MachineCIDR},
+		{cluster.Network().ServiceCIDR(), &spec.ServiceCIDR},
+		{cluster.Network().PodCIDR(), &spec.PodCIDR},
+	} {
+		_, ipNet, err := net.ParseCIDR(cidr.value)
+		if err == nil {
+			*cidr.target = *ipNet
+		}
+	}
+
+	private := cluster.API().Listening() == cmv1.ListeningMethodInternal
+	spec.Private = &private
+	privateLink := cluster.AWS().PrivateLink()
+	spec.PrivateLink = &privateLink
+
+	for _, role := range cluster.AWS().STS().OperatorIAMRoles() {
+		spec.OperatorIAMRoles = append(spec.OperatorIAMRoles, OperatorIAMRole{
+			Name:      role.Name(),
+			Namespace: role.Namespace(),
+			RoleARN:   role.RoleARN(),
+		})
+	}
+
+	return spec
+}
+
+// clusterExtensions returns the attributes of the cluster that the SDK doesn't support yet, so
+// that they can be merged into the JSON representation of the cluster.
+func clusterExtensions(config Spec) map[string]interface{} {
+	extra := map[string]interface{}{}
+	if config.KMSKeyArn != "" {
+		awsExtra := map[string]interface{}{
+			"kms_key_arn": config.KMSKeyArn,
+		}
+		if config.EtcdEncryption {
+			awsExtra["etcd_encryption"] = map[string]interface{}{
+				"kms_key_arn": config.KMSKeyArn,
+			}
+		}
+		extra["aws"] = awsExtra
+	}
+	mergeJSON(extra, proxyExtensions(config))
+	return extra
+}
+
+// readClusterExtensions sets in the spec the attributes returned by clusterExtensions, taken from
+// the JSON representation of the cluster.
+func readClusterExtensions(spec *Spec, object map[string]interface{}) {
+	if awsObject, ok := object["aws"].(map[string]interface{}); ok {
+		spec.KMSKeyArn, _ = awsObject["kms_key_arn"].(string)
+	}
+	if proxy, ok := object["proxy"].(map[string]interface{}); ok {
+		for key, target := range map[string]**string{
+			"http_proxy":  &spec.HTTPProxy,
+			"https_proxy": &spec.HTTPSProxy,
+			"no_proxy":    &spec.NoProxy,
+		} {
+			if value, ok := proxy[key].(string); ok && value != "" {
+				*target = &value
+			}
+		}
+	}
+	// The API doesn't return the content of the trust bundle, only a placeholder telling that
+	// there is one:
+	if bundle, ok := object["additional_trust_bundle"].(string); ok && bundle != "" {
+		spec.AdditionalTrustBundle = &bundle
+	}
+}
+
 func (c *Client) createClusterSpec(config Spec, awsClient aws.Client) (*cmv1.Cluster, error) {
 	reporter, err := rprtr.New().
 		Build()
@@ -134,6 +134,26 @@ func Location() (path string, err error) {
 	return path, nil
 }
 
+// ProfilesLocation returns the location of the file that stores the cluster profiles, which is
+// kept in the same directory as the configuration file.
+func ProfilesLocation() (string, error) {
+	file, err := Location()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(file), ".rosa-profiles.json"), nil
+}
+
+// MigrationsLocation returns the location of the file that stores the progress of the machine
+// pool migrations, which is kept in the same directory as the configuration file.
+func MigrationsLocation() (string, error) {
+	file, err := Location()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(file), ".rosa-migrations.json"), nil
+}
+
 func (c *Config) GetData(key string) (value string, err error) {
 	if c.AccessToken == "" {
 		return
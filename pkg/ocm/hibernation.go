@@ -0,0 +1,122 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"fmt"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// HibernationOperation is one of the operations that stop the nodes of a cluster or start them
+// again. The hibernate and resume commands use it to validate the state of the cluster, send the
+// request and wait till the cluster reaches the expected state.
+type HibernationOperation struct {
+	// Wait is the operation that waits till the cluster reaches the expected state.
+	Wait WaitOperation
+
+	validate func(c *Client, cluster *cmv1.Cluster) error
+	send     func(c *Client, clusterID string) error
+}
+
+// HibernateOperation stops the nodes of a ready cluster.
+var HibernateOperation = &HibernationOperation{
+	Wait: WaitHibernate,
+	validate: func(c *Client, cluster *cmv1.Cluster) error {
+		scheduledUpgrade, upgradeState, err := c.GetScheduledUpgrade(cluster.ID())
+		if err != nil {
+			return fmt.Errorf("Failed to get scheduled upgrades: %v", err)
+		}
+		return ValidateHibernate(cluster.State(), scheduledUpgrade, upgradeState)
+	},
+	send: (*Client).HibernateCluster,
+}
+
+// ResumeOperation starts again the nodes of a hibernating cluster.
+var ResumeOperation = &HibernationOperation{
+	Wait: WaitResume,
+	validate: func(_ *Client, cluster *cmv1.Cluster) error {
+		return ValidateResume(cluster.State())
+	},
+	send: (*Client).ResumeCluster,
+}
+
+// Validate checks that the operation can be applied to the cluster in its current state.
+func (o *HibernationOperation) Validate(c *Client, cluster *cmv1.Cluster) error {
+	return o.validate(c, cluster)
+}
+
+// Send sends the request that starts the operation on the cluster.
+func (o *HibernationOperation) Send(c *Client, cluster *cmv1.Cluster) error {
+	return o.send(c, cluster.ID())
+}
+
+// ValidateHibernate checks that a cluster in the given state, and with the given scheduled
+// upgrade, if any, can be hibernated.
+func ValidateHibernate(state cmv1.ClusterState, scheduledUpgrade *cmv1.UpgradePolicy,
+	upgradeState *cmv1.UpgradePolicyState) error {
+	switch state {
+	case cmv1.ClusterStateReady:
+	case cmv1.ClusterStateHibernating, cmv1.ClusterStatePoweringDown:
+		return fmt.Errorf("Cluster is already %s", state)
+	default:
+		return fmt.Errorf("Cluster is %s, only ready clusters can be hibernated", state)
+	}
+	if scheduledUpgrade != nil {
+		if upgradeState != nil && upgradeState.Value() == cmv1.UpgradePolicyStateValueStarted {
+			return fmt.Errorf("Cluster is being upgraded to version %s", scheduledUpgrade.Version())
+		}
+		return fmt.Errorf("Cluster has an upgrade to version %s scheduled on %s, cancel it first "+
+			"with 'rosa delete upgrade'",
+			scheduledUpgrade.Version(), scheduledUpgrade.NextRun().Format("2006-01-02 15:04 MST"))
+	}
+	return nil
+}
+
+// ValidateResume checks that a cluster in the given state can be resumed.
+func ValidateResume(state cmv1.ClusterState) error {
+	switch state {
+	case cmv1.ClusterStateHibernating:
+		return nil
+	case cmv1.ClusterStateResuming:
+		return fmt.Errorf("Cluster is already %s", state)
+	default:
+		return fmt.Errorf("Cluster is %s, only hibernating clusters can be resumed", state)
+	}
+}
+
+func (c *Client) HibernateCluster(clusterID string) error {
+	response, err := c.ocm.ClustersMgmt().V1().Clusters().
+		Cluster(clusterID).
+		Hibernate().
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
+
+func (c *Client) ResumeCluster(clusterID string) error {
+	response, err := c.ocm.ClustersMgmt().V1().Clusters().
+		Cluster(clusterID).
+		Resume().
+		Send()
+	if err != nil {
+		return handleErr(response.Error(), err)
+	}
+	return nil
+}
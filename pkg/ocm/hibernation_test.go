@@ -0,0 +1,68 @@
+package ocm_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("Hibernation", func() {
+	upgrade := func() *cmv1.UpgradePolicy {
+		policy, err := cmv1.NewUpgradePolicy().
+			Version("4.7.2").
+			NextRun(time.Date(2021, 5, 1, 10, 0, 0, 0, time.UTC)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		return policy
+	}
+
+	upgradeState := func(value cmv1.UpgradePolicyStateValue) *cmv1.UpgradePolicyState {
+		state, err := cmv1.NewUpgradePolicyState().Value(value).Build()
+		Expect(err).NotTo(HaveOccurred())
+		return state
+	}
+
+	It("allows hibernating ready clusters", func() {
+		Expect(ocm.ValidateHibernate(cmv1.ClusterStateReady, nil, nil)).To(Succeed())
+	})
+
+	It("rejects hibernating clusters that aren't ready", func() {
+		err := ocm.ValidateHibernate(cmv1.ClusterStateInstalling, nil, nil)
+		Expect(err).To(MatchError("Cluster is installing, only ready clusters can be hibernated"))
+
+		err = ocm.ValidateHibernate(cmv1.ClusterStateHibernating, nil, nil)
+		Expect(err).To(MatchError("Cluster is already hibernating"))
+	})
+
+	It("rejects hibernating clusters with a scheduled upgrade", func() {
+		err := ocm.ValidateHibernate(cmv1.ClusterStateReady, upgrade(), upgradeState(cmv1.UpgradePolicyStateValueScheduled))
+		Expect(err).To(MatchError("Cluster has an upgrade to version 4.7.2 scheduled on " +
+			"2021-05-01 10:00 UTC, cancel it first with 'rosa delete upgrade'"))
+	})
+
+	It("rejects hibernating clusters that are being upgraded", func() {
+		err := ocm.ValidateHibernate(cmv1.ClusterStateReady, upgrade(), upgradeState(cmv1.UpgradePolicyStateValueStarted))
+		Expect(err).To(MatchError("Cluster is being upgraded to version 4.7.2"))
+	})
+
+	It("only allows resuming hibernating clusters", func() {
+		Expect(ocm.ValidateResume(cmv1.ClusterStateHibernating)).To(Succeed())
+		Expect(ocm.ValidateResume(cmv1.ClusterStateResuming)).To(MatchError("Cluster is already resuming"))
+		Expect(ocm.ValidateResume(cmv1.ClusterStateReady)).To(
+			MatchError("Cluster is ready, only hibernating clusters can be resumed"))
+	})
+
+	It("validates the resume operation without contacting the API", func() {
+		cluster, err := cmv1.NewCluster().ID("123").State(cmv1.ClusterStateReady).Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ocm.ResumeOperation.Validate(nil, cluster)).To(
+			MatchError("Cluster is ready, only hibernating clusters can be resumed"))
+		Expect(ocm.ResumeOperation.Wait).To(Equal(ocm.WaitResume))
+		Expect(ocm.HibernateOperation.Wait).To(Equal(ocm.WaitHibernate))
+	})
+})
@@ -17,6 +17,8 @@ limitations under the License.
 package ocm
 
 import (
+	"encoding/json"
+
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 )
 
@@ -43,6 +45,134 @@ func (c *Client) CreateMachinePool(clusterID string, machinePool *cmv1.MachinePo
 	}
 	return response.Body(), nil
 }
+
+// MachinePoolAWS contains the AWS settings of a machine pool that the SDK doesn't support yet.
+type MachinePoolAWS struct {
+	// Tags are added to the tags of the cluster for the instances of the machine pool.
+	Tags map[string]string
+
+	// Spot contains the settings of the spot instances of the machine pool. It is nil for machine
+	// pools that use on-demand instances.
+	Spot *MachinePoolSpot
+
+	// Subnets are the subnets where the instances of the machine pool are created, for clusters
+	// installed into existing subnets. They are empty when the machine pool uses all the private
+	// subnets of the cluster.
+	Subnets []string
+}
+
+// MachinePoolSpot contains the spot market settings of a machine pool.
+type MachinePoolSpot struct {
+	// MaxPrice is the maximum hourly price, in US dollars, that will be paid for each instance.
+	// When it is nil the maximum is the on-demand price of the instance type.
+	MaxPrice *float64
+}
+
+func (a *MachinePoolAWS) extensions() map[string]interface{} {
+	awsExtra := map[string]interface{}{}
+	if len(a.Tags) > 0 {
+		awsExtra["tags"] = a.Tags
+	}
+	if a.Spot != nil {
+		spotExtra := map[string]interface{}{}
+		if a.Spot.MaxPrice != nil {
+			spotExtra["max_price"] = *a.Spot.MaxPrice
+		}
+		awsExtra["spot_market_options"] = spotExtra
+	}
+	extra := map[string]interface{}{}
+	if len(awsExtra) > 0 {
+		extra["aws"] = awsExtra
+	}
+	if len(a.Subnets) > 0 {
+		extra["subnets"] = a.Subnets
+	}
+	if len(extra) == 0 {
+		return nil
+	}
+	return extra
+}
+
+// CreateMachinePoolWithAWS creates a machine pool with the given AWS settings.
+func (c *Client) CreateMachinePoolWithAWS(clusterID string, machinePool *cmv1.MachinePool,
+	awsConfig *MachinePoolAWS) (*cmv1.MachinePool, error) {
+	extra := awsConfig.extensions()
+	if len(extra) == 0 {
+		return c.CreateMachinePool(clusterID, machinePool)
+	}
+	body, err := MachinePoolBody(machinePool, awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return c.addMachinePoolJSON(clusterID, body)
+}
+
+// MachinePoolBody returns the body of the request that creates the machine pool with the given
+// AWS settings.
+func MachinePoolBody(machinePool *cmv1.MachinePool, awsConfig *MachinePoolAWS) ([]byte, error) {
+	return machinePoolJSON(machinePool, awsConfig.extensions())
+}
+
+// GetMachinePoolsAWS returns the AWS settings of the machine pools of the cluster that the SDK
+// doesn't support yet, indexed by the identifier of the machine pool.
+func (c *Client) GetMachinePoolsAWS(clusterID string) (map[string]*MachinePoolAWS, error) {
+	data, err := c.getMachinePoolsJSON(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	list := struct {
+		Items []struct {
+			ID      string   `json:"id"`
+			Subnets []string `json:"subnets"`
+			AWS     *struct {
+				Tags              map[string]string `json:"tags"`
+				SpotMarketOptions *struct {
+					MaxPrice *float64 `json:"max_price"`
+				} `json:"spot_market_options"`
+			} `json:"aws"`
+		} `json:"items"`
+	}{}
+	err = json.Unmarshal(data, &list)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]*MachinePoolAWS{}
+	for _, item := range list.Items {
+		awsConfig := &MachinePoolAWS{
+			Subnets: item.Subnets,
+		}
+		if item.AWS != nil {
+			awsConfig.Tags = item.AWS.Tags
+			if item.AWS.SpotMarketOptions != nil {
+				awsConfig.Spot = &MachinePoolSpot{
+					MaxPrice: item.AWS.SpotMarketOptions.MaxPrice,
+				}
+			}
+		}
+		result[item.ID] = awsConfig
+	}
+	return result, nil
+}
+
+// GetMachinePoolReplicas returns the number of replicas of the machine pool that are running, as
+// reported in its status.
+func (c *Client) GetMachinePoolReplicas(clusterID string, machinePoolID string) (int, error) {
+	data, err := c.getMachinePoolJSON(clusterID, machinePoolID)
+	if err != nil {
+		return 0, err
+	}
+	object := struct {
+		Status struct {
+			CurrentReplicas int `json:"current_replicas"`
+		} `json:"status"`
+	}{}
+	err = json.Unmarshal(data, &object)
+	if err != nil {
+		return 0, err
+	}
+	return object.Status.CurrentReplicas, nil
+}
+
 func (c *Client) UpdateMachinePool(clusterID string, machinePool *cmv1.MachinePool) (*cmv1.MachinePool, error) {
 	response, err := c.ocm.ClustersMgmt().V1().
 		Clusters().Cluster(clusterID).
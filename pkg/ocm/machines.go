@@ -19,6 +19,7 @@ package ocm
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	amsv1 "github.com/openshift-online/ocm-sdk-go/accountsmgmt/v1"
@@ -171,3 +172,104 @@ func (c *Client) GetAvailableMachineTypes() ([]*MachineType, error) {
 	}
 	return availableMachineTypes, nil
 }
+
+// MachineTypeCategories are the categories of instance types that can be used to filter them.
+var MachineTypeCategories = []string{
+	"general_purpose",
+	"memory_optimized",
+	"compute_optimized",
+	"storage_optimized",
+	AcceleratedComputing,
+}
+
+// MachineTypeSortKeys are the keys that can be used to sort the instance types.
+var MachineTypeSortKeys = []string{"id", "cpu", "memory"}
+
+// MachineTypeFilter contains the criteria used to select instance types. Zero values mean that
+// the criteria isn't used.
+type MachineTypeFilter struct {
+	MinCPU       int
+	MinMemoryGiB float64
+	Category     string
+
+	// GPU selects only the accelerated computing instance types.
+	GPU bool
+}
+
+// Validate checks that the category of the filter is known.
+func (f *MachineTypeFilter) Validate() error {
+	if f.Category == "" {
+		return nil
+	}
+	for _, category := range MachineTypeCategories {
+		if f.Category == category {
+			return nil
+		}
+	}
+	return fmt.Errorf("Invalid category '%s', valid categories are: %s",
+		f.Category, strings.Join(MachineTypeCategories, ", "))
+}
+
+// Matches returns true if the machine type satisfies all the criteria of the filter.
+func (f *MachineTypeFilter) Matches(machineType *cmv1.MachineType) bool {
+	if f.MinCPU > 0 && int(machineType.CPU().Value()) < f.MinCPU {
+		return false
+	}
+	if f.MinMemoryGiB > 0 && MachineTypeMemoryGiB(machineType) < f.MinMemoryGiB {
+		return false
+	}
+	if f.Category != "" && string(machineType.Category()) != f.Category {
+		return false
+	}
+	if f.GPU && machineType.Category() != AcceleratedComputing {
+		return false
+	}
+	return true
+}
+
+// FilterMachineTypes returns the machine types that match the filter, in the same order.
+func FilterMachineTypes(machineTypes []*MachineType, filter *MachineTypeFilter) []*MachineType {
+	result := []*MachineType{}
+	for _, machineType := range machineTypes {
+		if filter.Matches(machineType.MachineType) {
+			result = append(result, machineType)
+		}
+	}
+	return result
+}
+
+// SortMachineTypes sorts the machine types in place by the given key. Machine types with the same
+// value of the key are sorted by identifier.
+func SortMachineTypes(machineTypes []*MachineType, key string) error {
+	var less func(a, b *cmv1.MachineType) bool
+	switch key {
+	case "id":
+		less = func(a, b *cmv1.MachineType) bool { return false }
+	case "cpu":
+		less = func(a, b *cmv1.MachineType) bool { return a.CPU().Value() < b.CPU().Value() }
+	case "memory":
+		less = func(a, b *cmv1.MachineType) bool {
+			return MachineTypeMemoryGiB(a) < MachineTypeMemoryGiB(b)
+		}
+	default:
+		return fmt.Errorf("Invalid sort key '%s', valid keys are: %s",
+			key, strings.Join(MachineTypeSortKeys, ", "))
+	}
+	sort.SliceStable(machineTypes, func(i, j int) bool {
+		a, b := machineTypes[i].MachineType, machineTypes[j].MachineType
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return a.ID() < b.ID()
+	})
+	return nil
+}
+
+// MachineTypeMemoryGiB returns the memory of the machine type in GiB. The API returns the memory
+// in bytes.
+func MachineTypeMemoryGiB(machineType *cmv1.MachineType) float64 {
+	return machineType.Memory().Value() / (1 << 30)
+}
@@ -0,0 +1,86 @@
+package ocm_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("Machine types", func() {
+	machineType := func(id string, category string, cpu int, memoryGiB int) *ocm.MachineType {
+		result, err := cmv1.NewMachineType().
+			ID(id).
+			Category(cmv1.MachineTypeCategory(category)).
+			CPU(cmv1.NewValue().Value(float64(cpu)).Unit("vCPU")).
+			Memory(cmv1.NewValue().Value(float64(memoryGiB) * (1 << 30)).Unit("B")).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		return &ocm.MachineType{
+			MachineType: result,
+			Available:   true,
+		}
+	}
+
+	ids := func(machineTypes []*ocm.MachineType) []string {
+		result := []string{}
+		for _, machineType := range machineTypes {
+			result = append(result, machineType.MachineType.ID())
+		}
+		return result
+	}
+
+	var machineTypes []*ocm.MachineType
+
+	BeforeEach(func() {
+		machineTypes = []*ocm.MachineType{
+			machineType("m5.xlarge", "general_purpose", 4, 16),
+			machineType("r5.xlarge", "memory_optimized", 4, 32),
+			machineType("c5.2xlarge", "compute_optimized", 8, 16),
+			machineType("g4dn.xlarge", ocm.AcceleratedComputing, 4, 16),
+		}
+	})
+
+	Context("FilterMachineTypes", func() {
+		It("selects by CPU and memory", func() {
+			filter := &ocm.MachineTypeFilter{MinCPU: 4, MinMemoryGiB: 20}
+			Expect(ids(ocm.FilterMachineTypes(machineTypes, filter))).To(Equal([]string{"r5.xlarge"}))
+
+			filter = &ocm.MachineTypeFilter{MinCPU: 8}
+			Expect(ids(ocm.FilterMachineTypes(machineTypes, filter))).To(Equal([]string{"c5.2xlarge"}))
+		})
+
+		It("selects by category and GPU", func() {
+			filter := &ocm.MachineTypeFilter{Category: "general_purpose"}
+			Expect(ids(ocm.FilterMachineTypes(machineTypes, filter))).To(Equal([]string{"m5.xlarge"}))
+
+			filter = &ocm.MachineTypeFilter{GPU: true}
+			Expect(ids(ocm.FilterMachineTypes(machineTypes, filter))).To(Equal([]string{"g4dn.xlarge"}))
+		})
+
+		It("rejects unknown categories", func() {
+			filter := &ocm.MachineTypeFilter{Category: "gpu"}
+			Expect(filter.Validate()).To(MatchError(ContainSubstring("Invalid category 'gpu'")))
+		})
+	})
+
+	Context("SortMachineTypes", func() {
+		It("sorts by the key and then by identifier", func() {
+			Expect(ocm.SortMachineTypes(machineTypes, "memory")).To(Succeed())
+			Expect(ids(machineTypes)).To(Equal([]string{"c5.2xlarge", "g4dn.xlarge", "m5.xlarge", "r5.xlarge"}))
+
+			Expect(ocm.SortMachineTypes(machineTypes, "cpu")).To(Succeed())
+			Expect(ids(machineTypes)).To(Equal([]string{"g4dn.xlarge", "m5.xlarge", "r5.xlarge", "c5.2xlarge"}))
+
+			Expect(ocm.SortMachineTypes(machineTypes, "id")).To(Succeed())
+			Expect(ids(machineTypes)).To(Equal([]string{"c5.2xlarge", "g4dn.xlarge", "m5.xlarge", "r5.xlarge"}))
+		})
+
+		It("rejects unknown keys", func() {
+			Expect(ocm.SortMachineTypes(machineTypes, "price")).To(MatchError(ContainSubstring(
+				"Invalid sort key 'price'")))
+		})
+	})
+})
@@ -0,0 +1,228 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the types and functions used to migrate a machine pool to a different
+// instance type, replacing it with a new machine pool and scaling it down in steps. The progress
+// of each migration is stored in a file, so that an interrupted migration can be resumed.
+
+package ocm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	awstags "github.com/openshift/rosa/pkg/aws/tags"
+)
+
+// MigrationStep is the step that a machine pool migration will run next.
+type MigrationStep string
+
+const (
+	// MigrationCreate creates the replacement machine pool.
+	MigrationCreate MigrationStep = "create"
+
+	// MigrationWait waits till the replicas of the replacement machine pool are ready.
+	MigrationWait MigrationStep = "wait"
+
+	// MigrationScaleDown scales the old machine pool down, one step at a time.
+	MigrationScaleDown MigrationStep = "scale-down"
+
+	// MigrationDelete deletes the old machine pool.
+	MigrationDelete MigrationStep = "delete"
+)
+
+// MachinePoolMigration is the progress of the migration of a machine pool to a new instance type.
+type MachinePoolMigration struct {
+	ClusterID    string        `json:"cluster_id"`
+	MachinePool  string        `json:"machine_pool"`
+	Replacement  string        `json:"replacement"`
+	InstanceType string        `json:"instance_type"`
+	Step         MigrationStep `json:"step"`
+
+	// Replicas is the number of replicas of the old machine pool. It is updated after each
+	// scale down step.
+	Replicas int `json:"replicas"`
+
+	// StepSize is the number of replicas removed from the old machine pool in each step.
+	StepSize int `json:"step_size"`
+}
+
+// Key returns the key of the migration in the migrations file.
+func (m *MachinePoolMigration) Key() string {
+	return MigrationKey(m.ClusterID, m.MachinePool)
+}
+
+// NextReplicas returns the number of replicas of the old machine pool after the next scale down
+// step.
+func (m *MachinePoolMigration) NextReplicas() int {
+	replicas := m.Replicas - m.StepSize
+	if replicas < 0 {
+		return 0
+	}
+	return replicas
+}
+
+// ScaleDownSteps returns the number of replicas of the old machine pool after each of the
+// remaining scale down steps.
+func (m *MachinePoolMigration) ScaleDownSteps() []int {
+	steps := []int{}
+	for replicas := m.Replicas; replicas > 0; {
+		replicas -= m.StepSize
+		if replicas < 0 {
+			replicas = 0
+		}
+		steps = append(steps, replicas)
+	}
+	return steps
+}
+
+// MigrationKey returns the key of the migration of the given machine pool in the migrations file.
+func MigrationKey(clusterID string, machinePoolID string) string {
+	return clusterID + "/" + machinePoolID
+}
+
+// ReplacementMachinePoolName returns the default name of the machine pool that replaces the given
+// one, made of its name and of the new instance type.
+func ReplacementMachinePoolName(machinePoolID string, instanceType string) string {
+	return machinePoolID + "-" + strings.ReplaceAll(instanceType, ".", "-")
+}
+
+// ReplacementMachinePool returns a machine pool with the given name and instance type, and the
+// same replicas, autoscaling, availability zones, labels and taints as the given one.
+func ReplacementMachinePool(machinePool *cmv1.MachinePool, name string,
+	instanceType string) (*cmv1.MachinePool, error) {
+	builder := cmv1.NewMachinePool().
+		ID(name).
+		InstanceType(instanceType).
+		Labels(machinePool.Labels())
+	if len(machinePool.AvailabilityZones()) > 0 {
+		builder = builder.AvailabilityZones(machinePool.AvailabilityZones()...)
+	}
+	taints := []*cmv1.TaintBuilder{}
+	for _, taint := range machinePool.Taints() {
+		taints = append(taints, cmv1.NewTaint().
+			Key(taint.Key()).
+			Value(taint.Value()).
+			Effect(taint.Effect()))
+	}
+	builder = builder.Taints(taints...)
+	if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+		builder = builder.Autoscaling(cmv1.NewMachinePoolAutoscaling().
+			MinReplicas(autoscaling.MinReplicas()).
+			MaxReplicas(autoscaling.MaxReplicas()))
+	} else {
+		builder = builder.Replicas(machinePool.Replicas())
+	}
+	return builder.Build()
+}
+
+// ReplacementMachinePoolAWS returns the AWS settings of the machine pool that replaces one with the
+// given settings: the same subnets, extra tags and spot market options. Reserved tags, which the
+// service adds to every machine pool, aren't copied.
+func ReplacementMachinePoolAWS(awsConfig *MachinePoolAWS) *MachinePoolAWS {
+	replacement := &MachinePoolAWS{
+		Subnets: awsConfig.Subnets,
+	}
+	if len(awsConfig.Tags) > 0 {
+		replacement.Tags = awstags.WithoutReserved(awsConfig.Tags)
+	}
+	if awsConfig.Spot != nil {
+		replacement.Spot = &MachinePoolSpot{
+			MaxPrice: awsConfig.Spot.MaxPrice,
+		}
+	}
+	return replacement
+}
+
+// ReadyReplicas returns the number of ready replicas that the replacement machine pool needs
+// before the old one starts to be scaled down. For autoscaling machine pools it is the minimum
+// number of replicas, as the autoscaler adds more when the old machine pool is scaled down.
+func ReadyReplicas(machinePool *cmv1.MachinePool) int {
+	if autoscaling, ok := machinePool.GetAutoscaling(); ok {
+		return autoscaling.MinReplicas()
+	}
+	return machinePool.Replicas()
+}
+
+// ScaleDownBody returns the body of the request that sets the replicas of the given machine pool.
+// If the machine pool uses autoscaling the body also removes it, as the API rejects replicas for
+// autoscaled machine pools.
+func ScaleDownBody(machinePool *cmv1.MachinePool, replicas int) ([]byte, error) {
+	update, err := cmv1.NewMachinePool().ID(machinePool.ID()).Replicas(replicas).Build()
+	if err != nil {
+		return nil, err
+	}
+	var extra map[string]interface{}
+	if _, ok := machinePool.GetAutoscaling(); ok {
+		extra = map[string]interface{}{"autoscaling": nil}
+	}
+	return machinePoolJSON(update, extra)
+}
+
+// ScaleDownMachinePool sets the replicas of the given machine pool, disabling autoscaling if it
+// was enabled.
+func (c *Client) ScaleDownMachinePool(clusterID string, machinePool *cmv1.MachinePool, replicas int) error {
+	body, err := ScaleDownBody(machinePool, replicas)
+	if err != nil {
+		return err
+	}
+	return c.updateMachinePoolJSON(clusterID, machinePool.ID(), body)
+}
+
+// LoadMigrations reads the migrations stored in the given file, indexed by key. The result is
+// empty if the file doesn't exist.
+func LoadMigrations(path string) (map[string]*MachinePoolMigration, error) {
+	migrations := map[string]*MachinePoolMigration{}
+	// #nosec G304
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return migrations, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read migrations file '%s': %v", path, err)
+	}
+	err = json.Unmarshal(data, &migrations)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse migrations file '%s': %v", path, err)
+	}
+	return migrations, nil
+}
+
+// SaveMigrations writes the given migrations to the given file, replacing its content. The file
+// is removed when there are no migrations left.
+func SaveMigrations(path string, migrations map[string]*MachinePoolMigration) error {
+	if len(migrations) == 0 {
+		err := os.Remove(path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("Failed to remove file '%s': %v", path, err)
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(migrations, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Failed to marshal migrations: %v", err)
+	}
+	err = ioutil.WriteFile(path, append(data, '\n'), 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write file '%s': %v", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package ocm_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("MachinePoolMigration", func() {
+	var migration *ocm.MachinePoolMigration
+
+	BeforeEach(func() {
+		migration = &ocm.MachinePoolMigration{
+			ClusterID:    "123",
+			MachinePool:  "mp1",
+			Replacement:  "mp1-m5-4xlarge",
+			InstanceType: "m5.4xlarge",
+			Step:         ocm.MigrationScaleDown,
+			Replicas:     7,
+			StepSize:     3,
+		}
+	})
+
+	It("scales down in steps", func() {
+		Expect(migration.NextReplicas()).To(Equal(4))
+		Expect(migration.ScaleDownSteps()).To(Equal([]int{4, 1, 0}))
+
+		migration.Replicas = 0
+		Expect(migration.ScaleDownSteps()).To(BeEmpty())
+	})
+
+	It("names the replacement after the instance type", func() {
+		Expect(ocm.ReplacementMachinePoolName("mp1", "m5.4xlarge")).To(Equal("mp1-m5-4xlarge"))
+		Expect(migration.Key()).To(Equal("123/mp1"))
+	})
+
+	It("disables autoscaling when scaling down an autoscaled machine pool", func() {
+		machinePool, err := cmv1.NewMachinePool().
+			ID("mp1").
+			Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(2).MaxReplicas(5)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		body, err := ocm.ScaleDownBody(machinePool, 1)
+		Expect(err).NotTo(HaveOccurred())
+		object := map[string]interface{}{}
+		Expect(json.Unmarshal(body, &object)).To(Succeed())
+		Expect(object).To(HaveKeyWithValue("replicas", BeNumerically("==", 1)))
+		Expect(object).To(HaveKeyWithValue("autoscaling", BeNil()))
+	})
+
+	It("only sets the replicas when scaling down a fixed machine pool", func() {
+		machinePool, err := cmv1.NewMachinePool().ID("mp1").Replicas(3).Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		body, err := ocm.ScaleDownBody(machinePool, 1)
+		Expect(err).NotTo(HaveOccurred())
+		object := map[string]interface{}{}
+		Expect(json.Unmarshal(body, &object)).To(Succeed())
+		Expect(object).To(HaveKeyWithValue("replicas", BeNumerically("==", 1)))
+		Expect(object).NotTo(HaveKey("autoscaling"))
+	})
+
+	It("keeps the subnets and extra tags of the machine pool in the replacement", func() {
+		machinePool, err := cmv1.NewMachinePool().
+			ID("mp1").
+			InstanceType("m5.xlarge").
+			Replicas(2).
+			AvailabilityZones("us-east-1a").
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+		awsConfig := &ocm.MachinePoolAWS{
+			Subnets: []string{"subnet-0123456789abcdef0"},
+			Tags: map[string]string{
+				"team":            "db",
+				"rosa_cluster_id": "123",
+			},
+		}
+
+		replacement, err := ocm.ReplacementMachinePool(machinePool, "mp1-m5-4xlarge", "m5.4xlarge")
+		Expect(err).NotTo(HaveOccurred())
+		body, err := ocm.MachinePoolBody(replacement, ocm.ReplacementMachinePoolAWS(awsConfig))
+		Expect(err).NotTo(HaveOccurred())
+		object := map[string]interface{}{}
+		Expect(json.Unmarshal(body, &object)).To(Succeed())
+		Expect(object).To(HaveKeyWithValue("instance_type", "m5.4xlarge"))
+		Expect(object).To(HaveKeyWithValue("availability_zones", ConsistOf("us-east-1a")))
+		Expect(object).To(HaveKeyWithValue("subnets", ConsistOf("subnet-0123456789abcdef0")))
+		Expect(object).To(HaveKeyWithValue("aws", HaveKeyWithValue("tags", Equal(map[string]interface{}{
+			"team": "db",
+		}))))
+	})
+
+	It("copies the settings of the machine pool to the replacement", func() {
+		machinePool, err := cmv1.NewMachinePool().
+			ID("mp1").
+			InstanceType("m5.xlarge").
+			AvailabilityZones("us-east-1a").
+			Labels(map[string]string{"app": "db"}).
+			Taints(cmv1.NewTaint().Key("dedicated").Value("db").Effect("NoSchedule")).
+			Autoscaling(cmv1.NewMachinePoolAutoscaling().MinReplicas(2).MaxReplicas(5)).
+			Build()
+		Expect(err).NotTo(HaveOccurred())
+
+		replacement, err := ocm.ReplacementMachinePool(machinePool, "mp1-m5-4xlarge", "m5.4xlarge")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replacement.ID()).To(Equal("mp1-m5-4xlarge"))
+		Expect(replacement.InstanceType()).To(Equal("m5.4xlarge"))
+		Expect(replacement.AvailabilityZones()).To(Equal([]string{"us-east-1a"}))
+		Expect(replacement.Labels()).To(Equal(map[string]string{"app": "db"}))
+		Expect(replacement.Taints()).To(HaveLen(1))
+		Expect(replacement.Taints()[0].Effect()).To(Equal("NoSchedule"))
+		Expect(replacement.Autoscaling().MaxReplicas()).To(Equal(5))
+		Expect(ocm.ReadyReplicas(replacement)).To(Equal(2))
+	})
+
+	Context("migrations file", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "migrations")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			os.RemoveAll(dir)
+		})
+
+		It("saves and loads migrations, and removes the file when they are done", func() {
+			path := filepath.Join(dir, "migrations.json")
+			migrations, err := ocm.LoadMigrations(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(migrations).To(BeEmpty())
+
+			migrations[migration.Key()] = migration
+			Expect(ocm.SaveMigrations(path, migrations)).To(Succeed())
+			loaded, err := ocm.LoadMigrations(path)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(loaded).To(Equal(migrations))
+
+			Expect(ocm.SaveMigrations(path, map[string]*ocm.MachinePoolMigration{})).To(Succeed())
+			_, err = os.Stat(path)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+	})
+})
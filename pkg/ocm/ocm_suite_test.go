@@ -0,0 +1,13 @@
+package ocm_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestOcm(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Ocm Suite")
+}
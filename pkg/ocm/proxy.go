@@ -0,0 +1,221 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to validate and read the cluster-wide proxy settings and
+// the additional trust bundle of clusters.
+
+package ocm
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Proxy contains the cluster-wide proxy settings of a cluster.
+type Proxy struct {
+	HTTPProxy  string
+	HTTPSProxy string
+	NoProxy    string
+
+	// AdditionalTrustBundle is true when the cluster has an additional trust bundle. The API
+	// doesn't return the contents of the bundle.
+	AdditionalTrustBundle bool
+}
+
+// Empty returns true if the cluster doesn't use a proxy nor an additional trust bundle.
+func (p *Proxy) Empty() bool {
+	return *p == (Proxy{})
+}
+
+var noProxyDomainRE = regexp.MustCompile(
+	`^\.?([a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([-a-zA-Z0-9]*[a-zA-Z0-9])?$`)
+
+// ValidateHTTPProxy checks that the value is a valid URL for the HTTP proxy, which must use the
+// 'http' scheme.
+func ValidateHTTPProxy(value string) error {
+	return validateProxyURL("http-proxy", value, "http")
+}
+
+// ValidateHTTPSProxy checks that the value is a valid URL for the HTTPS proxy, which can use
+// either the 'http' or the 'https' scheme.
+func ValidateHTTPSProxy(value string) error {
+	return validateProxyURL("https-proxy", value, "http", "https")
+}
+
+func validateProxyURL(name string, value string, schemes ...string) error {
+	if value == "" {
+		return nil
+	}
+	parsed, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("Invalid %s '%s': %v", name, value, err)
+	}
+	validScheme := false
+	for _, scheme := range schemes {
+		if parsed.Scheme == scheme {
+			validScheme = true
+		}
+	}
+	if !validScheme {
+		return fmt.Errorf("Invalid %s '%s': the scheme must be %s", name, value,
+			strings.Join(schemes, " or "))
+	}
+	if parsed.Hostname() == "" {
+		return fmt.Errorf("Invalid %s '%s': the host is missing", name, value)
+	}
+	if (parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("Invalid %s '%s': it can't contain a path, query or fragment", name, value)
+	}
+	return nil
+}
+
+// ValidateNoProxy checks that the value is a comma separated list of domains, IP addresses or
+// CIDR blocks, or '*' to bypass the proxy for all destinations.
+func ValidateNoProxy(value string) error {
+	if value == "" || value == "*" {
+		return nil
+	}
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			return fmt.Errorf("Invalid no-proxy '%s': it contains empty entries", value)
+		}
+		if net.ParseIP(entry) != nil {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(entry); err == nil {
+			continue
+		}
+		if !noProxyDomainRE.MatchString(entry) {
+			return fmt.Errorf("Invalid no-proxy entry '%s': expected a domain, IP address or CIDR block",
+				entry)
+		}
+	}
+	return nil
+}
+
+// ValidateProxy validates the proxy settings of a new cluster. The list of destinations that
+// bypass the proxy can only be given when an HTTP or HTTPS proxy is used.
+func ValidateProxy(httpProxy string, httpsProxy string, noProxy string) error {
+	err := ValidateHTTPProxy(httpProxy)
+	if err != nil {
+		return err
+	}
+	err = ValidateHTTPSProxy(httpsProxy)
+	if err != nil {
+		return err
+	}
+	err = ValidateNoProxy(noProxy)
+	if err != nil {
+		return err
+	}
+	if noProxy != "" && httpProxy == "" && httpsProxy == "" {
+		return fmt.Errorf("Expected at least one of 'http-proxy' or 'https-proxy' to be set when " +
+			"'no-proxy' is set")
+	}
+	return nil
+}
+
+// ValidateTrustBundle checks that the data contains one or more PEM encoded X.509 certificates,
+// and nothing else.
+func ValidateTrustBundle(data []byte) error {
+	count := 0
+	rest := data
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("Trust bundle contains a PEM block of type '%s', only certificates are "+
+				"allowed", block.Type)
+		}
+		_, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("Trust bundle contains an invalid certificate: %v", err)
+		}
+		count++
+	}
+	if len(bytes.TrimSpace(rest)) > 0 {
+		return fmt.Errorf("Trust bundle contains data that isn't a PEM encoded certificate")
+	}
+	if count == 0 {
+		return fmt.Errorf("Trust bundle doesn't contain any certificate")
+	}
+	return nil
+}
+
+// ReadTrustBundle reads the additional trust bundle from the given file and validates it.
+func ReadTrustBundle(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read trust bundle: %v", err)
+	}
+	err = ValidateTrustBundle(data)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// proxyExtensions returns the proxy attributes that have been set in the spec, in the format
+// expected by the API. Empty values are kept, so that updates can remove them.
+func proxyExtensions(config Spec) map[string]interface{} {
+	extra := map[string]interface{}{}
+	proxy := map[string]interface{}{}
+	if config.HTTPProxy != nil {
+		proxy["http_proxy"] = *config.HTTPProxy
+	}
+	if config.HTTPSProxy != nil {
+		proxy["https_proxy"] = *config.HTTPSProxy
+	}
+	if config.NoProxy != nil {
+		proxy["no_proxy"] = *config.NoProxy
+	}
+	if len(proxy) > 0 {
+		extra["proxy"] = proxy
+	}
+	if config.AdditionalTrustBundle != nil {
+		extra["additional_trust_bundle"] = *config.AdditionalTrustBundle
+	}
+	return extra
+}
+
+// GetClusterProxy returns the proxy settings of the cluster.
+func (c *Client) GetClusterProxy(clusterID string) (*Proxy, error) {
+	object, err := c.getClusterJSON(clusterID)
+	if err != nil {
+		return nil, err
+	}
+	result := &Proxy{}
+	if proxy, ok := object["proxy"].(map[string]interface{}); ok {
+		result.HTTPProxy, _ = proxy["http_proxy"].(string)
+		result.HTTPSProxy, _ = proxy["https_proxy"].(string)
+		result.NoProxy, _ = proxy["no_proxy"].(string)
+	}
+	if bundle, ok := object["additional_trust_bundle"].(string); ok && bundle != "" {
+		result.AdditionalTrustBundle = true
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// This file contains the functions used to send cluster attributes that aren't yet supported by
+// the version of the SDK used by the tool. The cluster is marshalled to JSON, the additional
+// attributes are merged into the result and the request is sent directly to the API.
+
+package ocm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocmerrors "github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+const clustersPath = "/api/clusters_mgmt/v1/clusters"
+
+// mergeJSON merges the source object into the destination object. Nested objects are merged
+// recursively, any other value of the source replaces the one of the destination.
+func mergeJSON(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		srcObject, srcOK := value.(map[string]interface{})
+		dstObject, dstOK := dst[key].(map[string]interface{})
+		if srcOK && dstOK {
+			mergeJSON(dstObject, srcObject)
+			continue
+		}
+		dst[key] = value
+	}
+}
+
+// clusterJSON returns the JSON representation of the cluster, with the given additional
+// attributes merged into it.
+func clusterJSON(cluster *cmv1.Cluster, extra map[string]interface{}) ([]byte, error) {
+	return mergedJSON(func(writer io.Writer) error {
+		return cmv1.MarshalCluster(cluster, writer)
+	}, extra)
+}
+
+// machinePoolJSON returns the JSON representation of the machine pool, with the given additional
+// attributes merged into it.
+func machinePoolJSON(machinePool *cmv1.MachinePool, extra map[string]interface{}) ([]byte, error) {
+	return mergedJSON(func(writer io.Writer) error {
+		return cmv1.MarshalMachinePool(machinePool, writer)
+	}, extra)
+}
+
+func mergedJSON(marshal func(io.Writer) error, extra map[string]interface{}) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	err := marshal(buffer)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	err = json.Unmarshal(buffer.Bytes(), &object)
+	if err != nil {
+		return nil, err
+	}
+	mergeJSON(object, extra)
+	return json.Marshal(object)
+}
+
+// addClusterJSON creates the cluster using the given JSON document as the body of the request.
+// The returned cluster is nil for dry runs.
+func (c *Client) addClusterJSON(body []byte, dryRun bool) (*cmv1.Cluster, error) {
+	data, err := sendJSON(c.ocm.Post().
+		Path(clustersPath).
+		Parameter("dryRun", dryRun).
+		Bytes(body))
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return nil, nil
+	}
+	return cmv1.UnmarshalCluster(data)
+}
+
+// addMachinePoolJSON creates the machine pool using the given JSON document as the body of the
+// request.
+func (c *Client) addMachinePoolJSON(clusterID string, body []byte) (*cmv1.MachinePool, error) {
+	data, err := sendJSON(c.ocm.Post().
+		Path(clustersPath + "/" + clusterID + "/machine_pools").
+		Bytes(body))
+	if err != nil {
+		return nil, err
+	}
+	return cmv1.UnmarshalMachinePool(data)
+}
+
+// updateClusterJSON updates the cluster using the given JSON document as the body of the request.
+func (c *Client) updateClusterJSON(clusterID string, body []byte) error {
+	_, err := sendJSON(c.ocm.Patch().
+		Path(clustersPath + "/" + clusterID).
+		Bytes(body))
+	return err
+}
+
+// updateMachinePoolJSON updates the machine pool using the given JSON document as the body of
+// the request.
+func (c *Client) updateMachinePoolJSON(clusterID string, machinePoolID string, body []byte) error {
+	_, err := sendJSON(c.ocm.Patch().
+		Path(clustersPath + "/" + clusterID + "/machine_pools/" + machinePoolID).
+		Bytes(body))
+	return err
+}
+
+// getClusterJSON returns the JSON representation of the cluster, including the attributes that
+// the SDK doesn't support.
+func (c *Client) getClusterJSON(clusterID string) (map[string]interface{}, error) {
+	data, err := sendJSON(c.ocm.Get().
+		Path(clustersPath + "/" + clusterID))
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	err = json.Unmarshal(data, &object)
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+// getMachinePoolsJSON returns the JSON representation of the list of machine pools of the
+// cluster, including the attributes that the SDK doesn't support.
+func (c *Client) getMachinePoolsJSON(clusterID string) ([]byte, error) {
+	return sendJSON(c.ocm.Get().
+		Path(clustersPath+"/"+clusterID+"/machine_pools").
+		Parameter("size", -1))
+}
+
+// getMachinePoolJSON returns the JSON representation of the machine pool, including the attributes
+// that the SDK doesn't support.
+func (c *Client) getMachinePoolJSON(clusterID string, machinePoolID string) ([]byte, error) {
+	return sendJSON(c.ocm.Get().
+		Path(clustersPath + "/" + clusterID + "/machine_pools/" + machinePoolID))
+}
+
+// sendJSON sends the request and returns the body of the response, or the error returned by the
+// API if the request failed.
+func sendJSON(request *sdk.Request) ([]byte, error) {
+	response, err := request.Send()
+	if err != nil {
+		return nil, err
+	}
+	if response.Status() >= http.StatusBadRequest {
+		apiErr, err := ocmerrors.UnmarshalError(response.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("Request failed with status %d: %s",
+				response.Status(), response.String())
+		}
+		return nil, handleErr(apiErr, apiErr)
+	}
+	return response.Bytes(), nil
+}
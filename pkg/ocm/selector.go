@@ -0,0 +1,101 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// ClusterSelector selects clusters by expiration, name and age. The fields that have their zero
+// value don't restrict the selection.
+type ClusterSelector struct {
+	// ExpiringAfter and ExpiringBefore select the clusters whose expiration timestamp is in that
+	// range. When any of them is set, clusters without expiration timestamp aren't selected.
+	ExpiringAfter  time.Time
+	ExpiringBefore time.Time
+
+	// NamePattern is a shell pattern, as supported by path.Match, that the name of the cluster
+	// has to match.
+	NamePattern string
+
+	// CreatedBefore selects the clusters created before that time.
+	CreatedBefore time.Time
+}
+
+// Validate checks that the name pattern is well formed and that the expiration range isn't
+// empty.
+func (s *ClusterSelector) Validate() error {
+	if s.NamePattern != "" {
+		_, err := path.Match(s.NamePattern, "")
+		if err != nil {
+			return fmt.Errorf("Invalid name pattern '%s': %v", s.NamePattern, err)
+		}
+	}
+	if !s.ExpiringAfter.IsZero() && !s.ExpiringBefore.IsZero() && !s.ExpiringAfter.Before(s.ExpiringBefore) {
+		return fmt.Errorf("Expiration range start '%s' must be before its end '%s'",
+			s.ExpiringAfter.Format(time.RFC3339), s.ExpiringBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Empty returns true if the selector doesn't restrict the selection, so it would select all
+// clusters.
+func (s *ClusterSelector) Empty() bool {
+	return s.ExpiringAfter.IsZero() && s.ExpiringBefore.IsZero() && s.NamePattern == "" &&
+		s.CreatedBefore.IsZero()
+}
+
+// Matches returns true if the cluster satisfies all the conditions of the selector.
+func (s *ClusterSelector) Matches(cluster *cmv1.Cluster) bool {
+	if !s.ExpiringAfter.IsZero() || !s.ExpiringBefore.IsZero() {
+		expiration, ok := cluster.GetExpirationTimestamp()
+		if !ok || expiration.IsZero() {
+			return false
+		}
+		if !s.ExpiringAfter.IsZero() && expiration.Before(s.ExpiringAfter) {
+			return false
+		}
+		if !s.ExpiringBefore.IsZero() && !expiration.Before(s.ExpiringBefore) {
+			return false
+		}
+	}
+	if s.NamePattern != "" {
+		matched, _ := path.Match(s.NamePattern, cluster.Name())
+		if !matched {
+			return false
+		}
+	}
+	if !s.CreatedBefore.IsZero() && !cluster.CreationTimestamp().Before(s.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// SelectClusters returns the clusters that match the selector, in the same order.
+func SelectClusters(clusters []*cmv1.Cluster, selector *ClusterSelector) []*cmv1.Cluster {
+	selected := []*cmv1.Cluster{}
+	for _, cluster := range clusters {
+		if selector.Matches(cluster) {
+			selected = append(selected, cluster)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,76 @@
+package ocm_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+var _ = Describe("ClusterSelector", func() {
+	now := time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cluster := func(name string, age time.Duration, expiresIn time.Duration) *cmv1.Cluster {
+		builder := cmv1.NewCluster().
+			Name(name).
+			CreationTimestamp(now.Add(-age))
+		if expiresIn != 0 {
+			builder.ExpirationTimestamp(now.Add(expiresIn))
+		}
+		cluster, err := builder.Build()
+		Expect(err).NotTo(HaveOccurred())
+		return cluster
+	}
+
+	names := func(clusters []*cmv1.Cluster) []string {
+		result := []string{}
+		for _, cluster := range clusters {
+			result = append(result, cluster.Name())
+		}
+		return result
+	}
+
+	var clusters []*cmv1.Cluster
+
+	BeforeEach(func() {
+		clusters = []*cmv1.Cluster{
+			cluster("ci-expired", 48*time.Hour, -time.Hour),
+			cluster("ci-soon", 2*time.Hour, time.Hour),
+			cluster("ci-later", time.Hour, 72*time.Hour),
+			cluster("prod", 30*24*time.Hour, 0),
+		}
+	})
+
+	It("selects expired clusters", func() {
+		selector := &ocm.ClusterSelector{ExpiringBefore: now}
+		Expect(names(ocm.SelectClusters(clusters, selector))).To(Equal([]string{"ci-expired"}))
+	})
+
+	It("selects clusters expiring in a range", func() {
+		selector := &ocm.ClusterSelector{ExpiringAfter: now, ExpiringBefore: now.Add(24 * time.Hour)}
+		Expect(names(ocm.SelectClusters(clusters, selector))).To(Equal([]string{"ci-soon"}))
+	})
+
+	It("selects clusters by name pattern and age", func() {
+		selector := &ocm.ClusterSelector{NamePattern: "ci-*", CreatedBefore: now.Add(-90 * time.Minute)}
+		Expect(names(ocm.SelectClusters(clusters, selector))).To(Equal([]string{"ci-expired", "ci-soon"}))
+	})
+
+	It("selects everything when empty", func() {
+		selector := &ocm.ClusterSelector{}
+		Expect(selector.Empty()).To(BeTrue())
+		Expect(ocm.SelectClusters(clusters, selector)).To(HaveLen(4))
+	})
+
+	It("rejects invalid patterns and ranges", func() {
+		selector := &ocm.ClusterSelector{NamePattern: "ci-["}
+		Expect(selector.Validate()).To(MatchError(ContainSubstring("Invalid name pattern 'ci-['")))
+
+		selector = &ocm.ClusterSelector{ExpiringAfter: now, ExpiringBefore: now}
+		Expect(selector.Validate()).To(MatchError(ContainSubstring("must be before its end")))
+	})
+})
@@ -0,0 +1,275 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ocm
+
+import (
+	"net/http"
+	"time"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	rprtr "github.com/openshift/rosa/pkg/reporter"
+)
+
+// ClusterStateDeleted is the state reported while waiting when the cluster doesn't exist
+// anymore. It isn't a state of the API.
+const ClusterStateDeleted cmv1.ClusterState = "deleted"
+
+// WaitOperation is the operation whose completion is waited for.
+type WaitOperation int
+
+const (
+	// WaitInstall waits till the cluster is ready after being created.
+	WaitInstall WaitOperation = iota
+
+	// WaitUninstall waits till the cluster doesn't exist anymore.
+	WaitUninstall
+
+	// WaitHibernate waits till the cluster is hibernating.
+	WaitHibernate
+
+	// WaitResume waits till a hibernating cluster is ready again.
+	WaitResume
+)
+
+// WaitResult is the outcome of waiting for an operation on a cluster.
+type WaitResult int
+
+const (
+	// WaitSucceeded means that the cluster reached the expected final state.
+	WaitSucceeded WaitResult = iota
+
+	// WaitFailed means that the cluster went into a state it can't recover from, for example
+	// the error state, before reaching the expected one.
+	WaitFailed
+
+	// WaitTimedOut means that the cluster didn't reach a final state in time.
+	WaitTimedOut
+)
+
+// Exit codes of the commands that wait for a cluster. Other failures exit with 1.
+const (
+	ExitCodeSucceeded      = 0
+	ExitCodeProvisionError = 2
+	ExitCodeTimeout        = 3
+)
+
+// maxPollErrors is the number of consecutive errors of the API after which waiting is abandoned.
+// Fewer errors are considered transient, and the next poll is delayed exponentially.
+const maxPollErrors = 5
+
+// ExitCode returns the exit code that commands use to report the result.
+func (r WaitResult) ExitCode() int {
+	switch r {
+	case WaitFailed:
+		return ExitCodeProvisionError
+	case WaitTimedOut:
+		return ExitCodeTimeout
+	default:
+		return ExitCodeSucceeded
+	}
+}
+
+// WaitForCluster polls the state of the cluster until the given operation finishes or the
+// timeout expires. The 'onTransition' function, if given,
+// is called every time the state of the cluster changes. The last status of the cluster is
+// returned as well, and it is nil if the cluster doesn't exist anymore.
+func (c *Client) WaitForCluster(clusterID string, operation WaitOperation, timeout time.Duration,
+	onTransition func(cmv1.ClusterState)) (WaitResult, *cmv1.ClusterStatus, error) {
+	return WaitForClusterStatus(func() (*cmv1.ClusterStatus, error) {
+		response, err := c.ocm.ClustersMgmt().V1().Clusters().
+			Cluster(clusterID).
+			Status().
+			Get().
+			Send()
+		if err != nil {
+			if response.Status() == http.StatusNotFound {
+				return nil, nil
+			}
+			return nil, handleErr(response.Error(), err)
+		}
+		return response.Body(), nil
+	}, operation, timeout, interval, onTransition)
+}
+
+// WaitForClusterAndReport waits for the given operation on the cluster like WaitForCluster,
+// reporting the states that the cluster goes through, and why the operation failed or timed
+// out. Errors of the API are returned, so that the caller can report them.
+func (c *Client) WaitForClusterAndReport(reporter *rprtr.Object, cluster *cmv1.Cluster,
+	operation WaitOperation, timeout time.Duration) (WaitResult, error) {
+	reporter.Infof("Waiting up to %s for cluster '%s'", timeout.Round(time.Second), cluster.Name())
+	result, status, err := c.WaitForCluster(cluster.ID(), operation, timeout,
+		func(state cmv1.ClusterState) {
+			reporter.Infof("Cluster '%s' is now %s", cluster.Name(), state)
+		})
+	if err != nil {
+		return result, err
+	}
+
+	switch result {
+	case WaitFailed:
+		message := ""
+		if status != nil && status.ProvisionErrorMessage() != "" {
+			message = ": " + status.ProvisionErrorMessage()
+			if status.ProvisionErrorCode() != "" {
+				message = ": " + status.ProvisionErrorCode() + " - " + status.ProvisionErrorMessage()
+			}
+		}
+		action := "install"
+		switch operation {
+		case WaitUninstall:
+			action = "uninstall"
+		case WaitHibernate:
+			action = "hibernate"
+		case WaitResume:
+			action = "resume"
+		}
+		reporter.Errorf("Cluster '%s' failed to %s%s", cluster.Name(), action, message)
+	case WaitTimedOut:
+		reporter.Errorf("Timed out after %s waiting for cluster '%s'", timeout.Round(time.Second), cluster.Name())
+	}
+	return result, nil
+}
+
+// WaitForClusterStatus implements the polling of WaitForCluster on top of a function that
+// returns the status of the cluster, or nil if the cluster doesn't exist. Errors returned by the
+// function are retried with backoff, and only returned after maxPollErrors consecutive errors.
+func WaitForClusterStatus(getStatus func() (*cmv1.ClusterStatus, error), operation WaitOperation,
+	timeout time.Duration, pollInterval time.Duration,
+	onTransition func(cmv1.ClusterState)) (WaitResult, *cmv1.ClusterStatus, error) {
+	deadline := time.Now().Add(timeout)
+	lastState := cmv1.ClusterState("")
+	var status *cmv1.ClusterStatus
+	errors := 0
+	for {
+		current, err := getStatus()
+		if err != nil {
+			errors++
+			if errors >= maxPollErrors {
+				return WaitFailed, status, err
+			}
+		} else {
+			errors = 0
+			status = current
+
+			state := ClusterStateDeleted
+			if status != nil {
+				state = status.State()
+			}
+			if state != lastState {
+				if onTransition != nil {
+					onTransition(state)
+				}
+				lastState = state
+			}
+
+			if result, done := waitResult(state, operation); done {
+				return result, status, nil
+			}
+		}
+
+		if time.Until(deadline) <= 0 {
+			return WaitTimedOut, status, nil
+		}
+		time.Sleep(pollDelay(pollInterval, errors, deadline))
+	}
+}
+
+// pollDelay returns the time to wait before polling again: the poll interval, doubled for each
+// consecutive error, and never beyond the deadline.
+func pollDelay(pollInterval time.Duration, errors int, deadline time.Time) time.Duration {
+	delay := pollInterval << uint(errors)
+	if remaining := time.Until(deadline); remaining < delay {
+		delay = remaining
+	}
+	return delay
+}
+
+// waitResult returns the result of the wait when the cluster is in the given state, and false
+// if the cluster is still moving towards a final state. Only the states listed for each operation
+// are final, so the state the cluster was in when the operation was requested, for example ready
+// when hibernating, doesn't end the wait even if the operation isn't reflected in it yet.
+func waitResult(state cmv1.ClusterState, operation WaitOperation) (WaitResult, bool) {
+	switch operation {
+	case WaitUninstall:
+		switch state {
+		case ClusterStateDeleted:
+			return WaitSucceeded, true
+		case cmv1.ClusterStateError:
+			return WaitFailed, true
+		}
+	case WaitHibernate:
+		switch state {
+		case cmv1.ClusterStateHibernating:
+			return WaitSucceeded, true
+		case cmv1.ClusterStateError, cmv1.ClusterStateUninstalling, ClusterStateDeleted:
+			return WaitFailed, true
+		}
+	default:
+		switch state {
+		case cmv1.ClusterStateReady:
+			return WaitSucceeded, true
+		case cmv1.ClusterStateError, cmv1.ClusterStateUninstalling, ClusterStateDeleted:
+			return WaitFailed, true
+		}
+	}
+	return WaitSucceeded, false
+}
+
+// WaitForMachinePool polls the number of running replicas of the machine pool until the 'ready'
+// function accepts it or the timeout expires, and returns false if the timeout expired. The
+// 'onChange' function, if given, is called every time the number of replicas changes.
+func (c *Client) WaitForMachinePool(clusterID string, machinePoolID string, ready func(int) bool,
+	timeout time.Duration, onChange func(int)) (bool, error) {
+	return WaitForMachinePoolReplicas(func() (int, error) {
+		return c.GetMachinePoolReplicas(clusterID, machinePoolID)
+	}, ready, timeout, interval, onChange)
+}
+
+// WaitForMachinePoolReplicas implements the polling of WaitForMachinePool on top of a function
+// that returns the number of running replicas. Errors are retried like in WaitForClusterStatus.
+func WaitForMachinePoolReplicas(getReplicas func() (int, error), ready func(int) bool,
+	timeout time.Duration, pollInterval time.Duration, onChange func(int)) (bool, error) {
+	deadline := time.Now().Add(timeout)
+	lastReplicas := -1
+	errors := 0
+	for {
+		replicas, err := getReplicas()
+		if err != nil {
+			errors++
+			if errors >= maxPollErrors {
+				return false, err
+			}
+		} else {
+			errors = 0
+			if replicas != lastReplicas {
+				if onChange != nil {
+					onChange(replicas)
+				}
+				lastReplicas = replicas
+			}
+			if ready(replicas) {
+				return true, nil
+			}
+		}
+
+		if time.Until(deadline) <= 0 {
+			return false, nil
+		}
+		time.Sleep(pollDelay(pollInterval, errors, deadline))
+	}
+}
@@ -0,0 +1,201 @@
+package ocm_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/openshift/rosa/pkg/ocm"
+)
+
+// statuses returns a function that returns the given states one after the other, repeating the
+// last one. The deleted state is returned as a nil status.
+func statuses(states ...cmv1.ClusterState) func() (*cmv1.ClusterStatus, error) {
+	return func() (*cmv1.ClusterStatus, error) {
+		state := states[0]
+		if len(states) > 1 {
+			states = states[1:]
+		}
+		if state == ocm.ClusterStateDeleted {
+			return nil, nil
+		}
+		return cmv1.NewClusterStatus().
+			State(state).
+			ProvisionErrorCode("OCM3999").
+			Build()
+	}
+}
+
+var _ = Describe("WaitForClusterStatus", func() {
+	var transitions []cmv1.ClusterState
+
+	onTransition := func(state cmv1.ClusterState) {
+		transitions = append(transitions, state)
+	}
+
+	BeforeEach(func() {
+		transitions = nil
+	})
+
+	It("succeeds when the cluster becomes ready", func() {
+		result, status, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStatePending,
+			cmv1.ClusterStatePending,
+			cmv1.ClusterStateInstalling,
+			cmv1.ClusterStateReady,
+		), ocm.WaitInstall, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitSucceeded))
+		Expect(result.ExitCode()).To(Equal(ocm.ExitCodeSucceeded))
+		Expect(status.State()).To(Equal(cmv1.ClusterStateReady))
+		Expect(transitions).To(Equal([]cmv1.ClusterState{
+			cmv1.ClusterStatePending,
+			cmv1.ClusterStateInstalling,
+			cmv1.ClusterStateReady,
+		}))
+	})
+
+	It("fails when the installation fails", func() {
+		result, status, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateInstalling,
+			cmv1.ClusterStateError,
+		), ocm.WaitInstall, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitFailed))
+		Expect(result.ExitCode()).To(Equal(ocm.ExitCodeProvisionError))
+		Expect(status.ProvisionErrorCode()).To(Equal("OCM3999"))
+	})
+
+	It("fails when the cluster is uninstalled while waiting for the installation", func() {
+		result, _, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateInstalling,
+			cmv1.ClusterStateUninstalling,
+		), ocm.WaitInstall, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitFailed))
+	})
+
+	It("times out when the cluster doesn't reach a final state", func() {
+		result, status, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateInstalling,
+		), ocm.WaitInstall, 10*time.Millisecond, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitTimedOut))
+		Expect(result.ExitCode()).To(Equal(ocm.ExitCodeTimeout))
+		Expect(status.State()).To(Equal(cmv1.ClusterStateInstalling))
+	})
+
+	It("succeeds when the cluster is gone after the uninstallation", func() {
+		result, status, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateUninstalling,
+			ocm.ClusterStateDeleted,
+		), ocm.WaitUninstall, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitSucceeded))
+		Expect(status).To(BeNil())
+		Expect(transitions).To(Equal([]cmv1.ClusterState{
+			cmv1.ClusterStateUninstalling,
+			ocm.ClusterStateDeleted,
+		}))
+	})
+
+	It("fails when the uninstallation fails", func() {
+		result, _, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateUninstalling,
+			cmv1.ClusterStateError,
+		), ocm.WaitUninstall, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitFailed))
+	})
+
+	It("succeeds when the cluster is hibernating", func() {
+		result, _, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateReady,
+			cmv1.ClusterStatePoweringDown,
+			cmv1.ClusterStateHibernating,
+		), ocm.WaitHibernate, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitSucceeded))
+		Expect(transitions).To(Equal([]cmv1.ClusterState{
+			cmv1.ClusterStateReady,
+			cmv1.ClusterStatePoweringDown,
+			cmv1.ClusterStateHibernating,
+		}))
+	})
+
+	It("succeeds when the cluster is ready after resuming", func() {
+		result, _, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateHibernating,
+			cmv1.ClusterStateResuming,
+			cmv1.ClusterStateReady,
+		), ocm.WaitResume, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitSucceeded))
+	})
+
+	It("fails when the cluster goes into error while resuming", func() {
+		result, _, err := ocm.WaitForClusterStatus(statuses(
+			cmv1.ClusterStateResuming,
+			cmv1.ClusterStateError,
+		), ocm.WaitResume, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitFailed))
+	})
+
+	It("retries transient errors of the API", func() {
+		calls := 0
+		ready := statuses(cmv1.ClusterStateReady)
+		result, _, err := ocm.WaitForClusterStatus(func() (*cmv1.ClusterStatus, error) {
+			calls++
+			if calls <= 3 {
+				return nil, fmt.Errorf("service unavailable")
+			}
+			return ready()
+		}, ocm.WaitInstall, time.Minute, time.Millisecond, onTransition)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result).To(Equal(ocm.WaitSucceeded))
+		Expect(transitions).To(Equal([]cmv1.ClusterState{cmv1.ClusterStateReady}))
+	})
+
+	It("returns the errors of the API when they persist", func() {
+		_, _, err := ocm.WaitForClusterStatus(func() (*cmv1.ClusterStatus, error) {
+			return nil, fmt.Errorf("service unavailable")
+		}, ocm.WaitInstall, time.Minute, time.Millisecond, onTransition)
+		Expect(err).To(MatchError("service unavailable"))
+	})
+})
+
+var _ = Describe("WaitForMachinePoolReplicas", func() {
+	replicas := func(values ...int) func() (int, error) {
+		return func() (int, error) {
+			value := values[0]
+			if len(values) > 1 {
+				values = values[1:]
+			}
+			return value, nil
+		}
+	}
+
+	It("succeeds when the replicas are ready", func() {
+		changes := []int{}
+		ready, err := ocm.WaitForMachinePoolReplicas(replicas(0, 0, 2, 3), func(replicas int) bool {
+			return replicas >= 3
+		}, time.Minute, time.Millisecond, func(replicas int) {
+			changes = append(changes, replicas)
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ready).To(BeTrue())
+		Expect(changes).To(Equal([]int{0, 2, 3}))
+	})
+
+	It("times out when the replicas aren't ready", func() {
+		ready, err := ocm.WaitForMachinePoolReplicas(replicas(6), func(replicas int) bool {
+			return replicas <= 3
+		}, 10*time.Millisecond, time.Millisecond, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ready).To(BeFalse())
+	})
+})